@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"os/signal"
-
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	_ "github.com/rustacean-dev/possystem/docs"
 	"github.com/rustacean-dev/possystem/http"
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/internal/reorder"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
 	"golang.org/x/sync/errgroup"
 	"maragu.dev/env"
 )
@@ -18,8 +25,12 @@ import (
 // @version 1.0
 // @description Modern POS built with PocketBase, Gomponents, HTMX
 // @contact.name Rustacean
-// @host localhost:8080
-// @BasePath /
+// @BasePath /api/v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description PocketBase token from /auth/login or an API key from the settings page, sent as "Bearer <token>"
 
 func main() {
 	// Set up a logger that is used throughout the app
@@ -42,10 +53,34 @@ func start(log *slog.Logger) error {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
 	defer stop()
 
+	// Low-stock alerts are shared between the checker and the page banner
+	alerts := &reorder.Alerts{}
+
+	// Order, stock and kitchen changes are published on the event bus
+	bus := event.NewBus()
+
+	// Background jobs and API key lookups act as a service user, since they run
+	// outside of any login
+	identity := env.GetStringOrDefault("SERVICE_IDENTITY", "")
+	password := env.GetStringOrDefault("SERVICE_PASSWORD", "")
+
 	// Set up the HTTP server, injecting the database and logger
-	s := http.NewServer(http.NewServerOptions{
-		Log: log,
-	})
+	opts := http.NewServerOptions{
+		Log:    log,
+		Alerts: alerts,
+		Events: bus,
+	}
+	// Requests made with API keys act as a user of their own, who is not
+	// an admin
+	keyIdentity := env.GetStringOrDefault("API_KEY_IDENTITY", "")
+	keyPassword := env.GetStringOrDefault("API_KEY_PASSWORD", "")
+	if identity == "" || password == "" || keyIdentity == "" || keyPassword == "" {
+		log.Info("SERVICE_IDENTITY, SERVICE_PASSWORD, API_KEY_IDENTITY and API_KEY_PASSWORD not all set, not accepting API keys")
+	} else {
+		opts.ServiceToken = cachedToken(serviceToken(identity, password))
+		opts.KeyUserToken = cachedToken(keyUserToken(keyIdentity, keyPassword))
+	}
+	s := http.NewServer(opts)
 
 	// Use an errgroup to wait for separate goroutines which can error
 	eg, ctx := errgroup.WithContext(ctx)
@@ -56,6 +91,52 @@ func start(log *slog.Logger) error {
 		return s.Start()
 	})
 
+	// The reorder checker reads stock as the service user
+	if identity == "" || password == "" {
+		log.Info("SERVICE_IDENTITY and SERVICE_PASSWORD not set, not checking stock levels")
+	} else {
+		checker := reorder.NewChecker(alerts, reorder.CheckerOptions{
+			Log:      log,
+			Fetch:    serviceItems(identity, password),
+			Interval: env.GetDurationOrDefault("REORDER_CHECK_INTERVAL", time.Minute),
+		})
+		eg.Go(func() error {
+			return checker.Run(ctx)
+		})
+	}
+
+	// Webhooks post events to the comma-separated WEBHOOK_URLS
+	if urls := env.GetStringOrDefault("WEBHOOK_URLS", ""); urls != "" {
+		var names []string
+		if types := env.GetStringOrDefault("WEBHOOK_EVENTS", ""); types != "" {
+			names = strings.Split(types, ",")
+		}
+		webhooks := event.NewWebhooks(bus, event.WebhookOptions{
+			Log:    log,
+			URLs:   strings.Split(urls, ","),
+			Secret: env.GetStringOrDefault("WEBHOOK_SECRET", ""),
+			Names:  names,
+		})
+		eg.Go(func() error {
+			return webhooks.Run(ctx)
+		})
+	}
+
+	// The realtime bridge also publishes changes made outside this app
+	if env.GetBoolOrDefault("POCKETBASE_REALTIME", false) {
+		if identity == "" || password == "" {
+			log.Info("SERVICE_IDENTITY and SERVICE_PASSWORD not set, not bridging PocketBase realtime events")
+		} else {
+			bridge := event.NewBridge(bus, event.BridgeOptions{
+				Log:   log,
+				Token: serviceToken(identity, password),
+			})
+			eg.Go(func() error {
+				return bridge.Run(ctx)
+			})
+		}
+	}
+
 	// Wait for the context to be done, which happens when a signal is caught
 	<-ctx.Done()
 	log.Info("Stopping app")
@@ -74,3 +155,73 @@ func start(log *slog.Logger) error {
 
 	return nil
 }
+
+// serviceItems returns a fetch function for the reorder checker. It logs in
+// once and logs in again only when the token stops working.
+func serviceItems(identity, password string) func(ctx context.Context) ([]model.Item, error) {
+	var token string
+	return func(ctx context.Context) ([]model.Item, error) {
+		if token != "" {
+			if items, err := repository.GetAllItems(token); err == nil {
+				return items, nil
+			}
+		}
+
+		res, err := repository.LoginUser(model.LoginRequest{Identity: identity, Password: password})
+		if err != nil {
+			return nil, err
+		}
+		token = res.Token
+		return repository.GetAllItems(token)
+	}
+}
+
+// serviceToken returns a function logging in as the service user, for the
+// realtime bridge to subscribe with.
+func serviceToken(identity, password string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		res, err := repository.LoginUser(model.LoginRequest{Identity: identity, Password: password})
+		if err != nil {
+			return "", err
+		}
+		return res.Token, nil
+	}
+}
+
+// keyUserToken returns a function logging in as the user requests made
+// with API keys act as. It refuses admins, whose rights go far beyond any
+// key's scopes.
+func keyUserToken(identity, password string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		res, err := repository.LoginUser(model.LoginRequest{Identity: identity, Password: password})
+		if err != nil {
+			return "", err
+		}
+		if res.User.Role == model.RoleAdmin {
+			return "", errors.New("the API key user must not be an admin")
+		}
+		return res.Token, nil
+	}
+}
+
+// cachedToken returns a function handing out the token login gets, for
+// requests made with API keys. It logs in once and again only when the
+// token is about to expire, not on every request.
+func cachedToken(login func(ctx context.Context) (string, error)) func(ctx context.Context) (string, error) {
+	var mu sync.Mutex
+	var token string
+	return func(ctx context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if token != "" && !repository.TokenExpired(token, time.Now().Add(time.Minute)) {
+			return token, nil
+		}
+		t, err := login(ctx)
+		if err != nil {
+			return "", err
+		}
+		token = t
+		return token, nil
+	}
+}
@@ -0,0 +1,135 @@
+package html
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/apikey"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// APIKeysPage renders /settings/api-keys, where admins create API keys for
+// machine clients and revoke them.
+//
+// Parameters:
+//   - keys: every key, newest first, revoked ones included.
+//   - newKey: a key just created, shown this once; empty otherwise.
+//   - admin: whether the user is an admin; others only see errorMsg.
+//   - now: the time expiries are checked against.
+//   - errorMsg: optional error message to display above the keys.
+func APIKeysPage(keys []model.APIKey, newKey string, admin bool, now time.Time, errorMsg string) Node {
+	return Layout("/settings/api-keys", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			H2(Class("text-2xl font-bold text-gray-800"), Text("API Keys")),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			If(admin, Group([]Node{
+				P(Class("text-gray-600"),
+					Text("Machine clients and integrations send a key as "),
+					Code(Class("bg-gray-100 px-1 rounded"), Text("Authorization: Bearer <key>")),
+					Text(" to call the JSON API, and may only do what its scopes allow."),
+				),
+
+				If(newKey != "",
+					Div(Class("bg-green-50 border border-green-400 text-green-800 px-4 py-3 rounded space-y-2"),
+						P(Class("font-semibold"), Text("Copy the new key now. It is not shown again.")),
+						Input(Type("text"), Value(newKey), ReadOnly(), Attr("onclick", "this.select()"),
+							Class("w-full font-mono text-sm border border-green-300 rounded p-2 bg-white"),
+						),
+					),
+				),
+
+				apiKeyTable(keys, now),
+
+				H3(Class("text-lg font-semibold text-gray-700"), Text("Create Key")),
+				Form(
+					Method("POST"),
+					Action("/settings/api-keys"),
+					Class("bg-white border border-gray-200 rounded-lg p-4 space-y-4"),
+					Div(Class("flex gap-3"),
+						Input(Type("text"), Name("name"), Placeholder("Name, e.g. Delivery app"), Required(),
+							Class("flex-grow border border-gray-300 rounded p-2"),
+						),
+						Input(Type("date"), Name("expires"), Attr("title", "Last day the key works (optional)"),
+							Class("border border-gray-300 rounded p-2"),
+						),
+					),
+					Div(Class("grid grid-cols-2 gap-2"),
+						Map(apikey.Scopes, func(s string) Node {
+							return Label(Class("flex items-center gap-2 text-sm text-gray-700"),
+								Input(Type("checkbox"), Name("scopes"), Value(s)),
+								Code(Class("text-xs bg-gray-100 px-1 rounded"), Text(s)),
+								Text(apikey.Label(s)),
+							)
+						}),
+					),
+					Button(Type("submit"), Class("text-sm bg-green-600 text-white px-3 py-2 rounded hover:bg-green-700"), Text("Create")),
+				),
+			})),
+		),
+	)
+}
+
+// apiKeyTable lists the keys with their scopes, expiry and last use.
+func apiKeyTable(keys []model.APIKey, now time.Time) Node {
+	if len(keys) == 0 {
+		return P(Class("text-gray-500"), Text("No API keys yet."))
+	}
+	return Table(Class("w-full bg-white border border-gray-200 rounded-lg text-sm"),
+		THead(Class("bg-gray-50 text-left text-gray-600"),
+			Tr(
+				Th(Class("p-3"), Text("Name")),
+				Th(Class("p-3"), Text("Key")),
+				Th(Class("p-3"), Text("Scopes")),
+				Th(Class("p-3"), Text("Expires")),
+				Th(Class("p-3"), Text("Last used")),
+				Th(Class("p-3")),
+			),
+		),
+		TBody(
+			Map(keys, func(k model.APIKey) Node {
+				return Tr(Class("border-t border-gray-100"),
+					Td(Class("p-3 font-medium"), Text(k.Name)),
+					Td(Class("p-3 font-mono text-xs"), Text(apikey.Prefix+k.Prefix+"_…")),
+					Td(Class("p-3 text-xs"), Text(strings.Join(k.Scopes, ", "))),
+					Td(Class("p-3"), Text(orNever(k.ExpiresAt))),
+					Td(Class("p-3"), Text(orNever(k.LastUsedAt))),
+					Td(Class("p-3 text-right"), apiKeyAction(k, now)),
+				)
+			}),
+		),
+	)
+}
+
+// apiKeyAction is the revoke button of a working key, or why it no longer
+// works.
+func apiKeyAction(k model.APIKey, now time.Time) Node {
+	switch {
+	case k.Revoked:
+		return Span(Class("text-gray-500"), Text("Revoked"))
+	case apikey.Expired(k, now):
+		return Span(Class("text-gray-500"), Text("Expired"))
+	}
+	return Form(
+		Method("POST"),
+		Action("/settings/api-keys/"+k.ID+"/revoke"),
+		Attr("onsubmit", "return confirm('Revoke this key? Clients using it stop working at once.')"),
+		Button(Type("submit"), Class("text-sm border border-red-400 text-red-700 px-3 py-1 rounded hover:bg-red-50"), Text("Revoke")),
+	)
+}
+
+// orNever formats an optional PocketBase datetime, or says "Never".
+func orNever(s string) string {
+	if s == "" {
+		return "Never"
+	}
+	return formatTimestamp(s)
+}
@@ -0,0 +1,83 @@
+package html
+
+import (
+	"fmt"
+
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// CategoriesPage renders the /categories admin page.
+// Each category can be renamed, recoloured, reordered or deleted in place,
+// and new categories are added with the form at the bottom.
+//
+// Parameters:
+//   - categories: all categories in display order.
+//   - errorMsg: optional error message to display above the list.
+func CategoriesPage(categories []model.Category, errorMsg string) Node {
+	return Layout("/categories", true,
+		Div(
+			ID("main"),
+			Class("max-w-3xl mx-auto mt-12 mb-12 space-y-6"),
+
+			H2(Class("text-2xl font-bold text-gray-800"), Text("Menu Categories")),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Div(Class("space-y-3"),
+				If(len(categories) == 0, P(Class("text-gray-500"), Text("No categories yet."))),
+				Map(categories, func(c model.Category) Node {
+					return Div(Class("flex items-center gap-3 bg-white border border-gray-200 rounded-lg p-3"),
+						Form(
+							Method("POST"),
+							Action("/categories/"+c.ID),
+							Class("flex flex-grow items-center gap-3"),
+							categoryFields(c),
+							Button(Type("submit"), Class("text-sm bg-indigo-600 text-white px-3 py-2 rounded hover:bg-indigo-700"), Text("Save")),
+						),
+						Form(
+							Method("POST"),
+							Action("/categories/"+c.ID+"/delete"),
+							Attr("onsubmit", "return confirm('Delete this category?')"),
+							Button(Type("submit"), Class("text-sm border border-red-400 text-red-700 px-3 py-2 rounded hover:bg-red-50"), Text("Delete")),
+						),
+					)
+				}),
+			),
+
+			H3(Class("text-lg font-semibold text-gray-700"), Text("Add Category")),
+			Form(
+				Method("POST"),
+				Action("/categories"),
+				Class("flex items-center gap-3 bg-white border border-gray-200 rounded-lg p-3"),
+				categoryFields(model.Category{Colour: "#6366f1", SortOrder: len(categories) + 1}),
+				Button(Type("submit"), Class("text-sm bg-green-600 text-white px-3 py-2 rounded hover:bg-green-700"), Text("Add")),
+			),
+		),
+	)
+}
+
+// categoryFields are the inputs shared by the add and edit forms.
+func categoryFields(c model.Category) Node {
+	return Group([]Node{
+		Input(Type("color"), Name("colour"), Value(c.Colour), Title("Colour"),
+			Class("h-10 w-12 border border-gray-300 rounded"),
+		),
+		Input(Type("text"), Name("name"), Value(c.Name), Placeholder("Name"), Required(),
+			Class("flex-grow border border-gray-300 rounded p-2"),
+		),
+		Input(Type("number"), Name("sort_order"), Value(fmt.Sprint(c.SortOrder)), Title("Sort order"),
+			Class("w-20 border border-gray-300 rounded p-2"),
+		),
+		Select(Name("station"), Title("Kitchen station"), Class("border border-gray-300 rounded p-2"),
+			Map(model.Stations, func(s string) Node {
+				// Categories without a station go to the grill
+				selected := s == c.Station || (c.Station == "" && s == model.StationGrill)
+				return Option(Value(s), Text(stationLabel(s)), If(selected, Selected()))
+			}),
+		),
+	})
+}
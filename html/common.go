@@ -3,12 +3,14 @@ package html
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 
+	"github.com/rustacean-dev/possystem/model"
 	. "maragu.dev/gomponents"
 	. "maragu.dev/gomponents/components"
 	. "maragu.dev/gomponents/html"
@@ -23,6 +25,10 @@ type PageProps struct {
 	Description string
 }
 
+// StockAlerts, when set, returns the items due for reordering. They are
+// shown in a banner at the top of every signed-in page.
+var StockAlerts func() []model.Item
+
 func Layout(path string, authenticated bool, children ...Node) Node {
 	// Run only once to compute file paths
 	hashOnce.Do(func() {
@@ -47,25 +53,79 @@ func Layout(path string, authenticated bool, children ...Node) Node {
 			Div(Class("min-h-screen bg-gray-50 font-sans flex flex-col"),
 				If(!isLoginPage,
 					Header(
-						Class("h-16 flex items-center justify-between px-6 bg-gradient-to-r from-indigo-700 via-purple-700 to-pink-700 text-white shadow-md sticky top-0 z-50"),
+						Class("h-16 flex items-center justify-between px-6 bg-gradient-to-r from-indigo-700 via-purple-700 to-pink-700 text-white shadow-md sticky top-0 z-50 print:hidden"),
 						H1(Class("text-lg md:text-xl font-bold tracking-tight select-none"), Text("POS System")),
 						Nav(Class("flex space-x-4 text-sm font-medium"),
 							navLink("/", "Home"),
 							navLink("/orders", "Orders"),
 							navLink("/orders/new", "New Order"),
+							navLink("/orders/pos", "POS"),
+							navLink("/kitchen", "Kitchen"),
+							navLink("/tables", "Tables"),
+							navLink("/tabs", "Tabs"),
+							navLink("/dispatch", "Dispatch"),
+							navLink("/items", "Items"),
 							navLink("/items/new", "Add Item"),
+							navLink("/ingredients", "Ingredients"),
+							navLink("/reports/x", "Reports"),
+							navLink("/settings/api-keys", "Settings"),
 							If(authenticated,
 								navLink("/logout", "Logout"),
 							),
 						),
 					),
 				),
+				If(authenticated && !isLoginPage, reorderBanner()),
 				Main(ID("main"), Class("flex-grow"), Group(children)),
 			),
 		},
 	})
 }
 
+// reorderBanner links to the reorder list when [StockAlerts] reports items
+// at or below their reorder point.
+func reorderBanner() Node {
+	if StockAlerts == nil {
+		return nil
+	}
+	items := StockAlerts()
+	if len(items) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, 3)
+	for _, it := range items[:min(len(items), 3)] {
+		names = append(names, it.Name)
+	}
+	if len(items) > 3 {
+		names = append(names, fmt.Sprintf("%d more", len(items)-3))
+	}
+
+	return Div(Class("bg-yellow-50 border-b border-yellow-300 text-yellow-800 text-sm px-6 py-2 flex items-center justify-between gap-4 print:hidden"),
+		Span(Textf("Low stock: %s.", strings.Join(names, ", "))),
+		A(Href("/reorder"), Class("font-semibold hover:underline whitespace-nowrap"), Text("Reorder list")),
+	)
+}
+
+// liveUpdates listens to the Server-Sent Events stream at src and
+// dispatches a DOM event named trigger on the body for every message of the
+// given types, or for every unnamed message if no types are given. Parts of
+// the page reload themselves with hx-trigger="<trigger> from:body".
+func liveUpdates(src, trigger string, types ...string) Node {
+	if types == nil {
+		types = []string{"message"}
+	}
+	args, _ := json.Marshal([]any{src, trigger, types})
+	return Script(Raw(`(function (src, trigger, types) {
+	var source = new EventSource(src);
+	types.forEach(function (type) {
+		source.addEventListener(type, function () {
+			document.body.dispatchEvent(new Event(trigger));
+		});
+	});
+}).apply(null, ` + string(args) + `);`))
+}
+
 func navLink(href, label string) Node {
 	return A(Href(href),
 		Class("transition hover:text-yellow-300 whitespace-nowrap"), Text(label),
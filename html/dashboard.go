@@ -0,0 +1,171 @@
+package html
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/rustacean-dev/possystem/internal/analytics"
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// DashboardPage renders the home page for logged-in users.
+// It shows today's revenue against the same weekday last week, order count,
+// average ticket, an hourly sales chart, the top sellers and low-stock warnings.
+// The figures reload as orders come in and stock changes.
+func DashboardPage(d analytics.Dashboard) Node {
+	return Layout("/", true,
+		liveUpdates("/events?types="+event.NameOrderCreated+","+event.NameOrderStatusChanged+","+event.NameStockChanged,
+			"shop-changed", event.NameOrderCreated, event.NameOrderStatusChanged, event.NameStockChanged),
+		Div(
+			ID("dashboard"),
+			Attr("hx-get", "/"),
+			Attr("hx-select", "#dashboard"),
+			Attr("hx-swap", "outerHTML"),
+			Attr("hx-trigger", "shop-changed from:body throttle:5s"),
+			Class("max-w-6xl mx-auto mt-12 mb-12 space-y-8"),
+
+			H2(Class("text-2xl font-bold text-gray-800"), Text("Today")),
+
+			Div(Class("grid grid-cols-2 md:grid-cols-4 gap-4"),
+				Div(Class("bg-white border border-gray-200 rounded-lg p-4"),
+					P(Class("text-sm text-gray-500"), Text("Revenue")),
+					P(Class("text-xl font-semibold text-gray-800"), Text(FormatTZS(d.Today))),
+					revenueDelta(d.Today, d.LastWeek),
+				),
+				statCard("Orders", fmt.Sprintf("%d", d.OrderCount)),
+				statCard("Average Ticket", FormatTZS(d.AverageTicket)),
+				statCard("Same Day Last Week", FormatTZS(d.LastWeek)),
+			),
+
+			Div(Class("bg-white border border-gray-200 rounded-lg p-4"),
+				H3(Class("text-lg font-semibold text-gray-700 mb-2"), Text("Sales by Hour")),
+				hourlyChart(d.Hourly),
+			),
+
+			Div(Class("grid md:grid-cols-2 gap-6"),
+				Div(
+					H3(Class("text-lg font-semibold text-gray-700 mb-2"), Text("Top Items")),
+					If(len(d.TopItems) == 0, P(Class("text-gray-500"), Text("No sales yet today."))),
+					If(len(d.TopItems) > 0,
+						Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+							THead(Class("bg-gray-100 text-gray-700"),
+								Tr(
+									Th(Class("px-4 py-2 text-left"), Text("Item")),
+									Th(Class("px-4 py-2 text-left"), Text("Sold")),
+									Th(Class("px-4 py-2 text-left"), Text("Revenue")),
+								),
+							),
+							TBody(
+								Map(d.TopItems, func(s analytics.ItemSales) Node {
+									return Tr(
+										Td(Class("px-4 py-2 border-t capitalize"), Text(s.Name)),
+										Td(Class("px-4 py-2 border-t"), Textf("%d", s.Quantity)),
+										Td(Class("px-4 py-2 border-t"), Text(FormatTZS(s.Revenue))),
+									)
+								}),
+							),
+						),
+					),
+				),
+
+				Div(
+					H3(Class("text-lg font-semibold text-gray-700 mb-2"), Text("Low Stock")),
+					If(len(d.LowStock) == 0, P(Class("text-gray-500"), Text("All items are well stocked."))),
+					Ul(Class("space-y-2"),
+						Map(d.LowStock, func(it model.Item) Node {
+							return Li(
+								Classes{
+									"flex justify-between px-4 py-2 rounded border":  true,
+									"bg-red-50 border-red-300 text-red-700":          it.Quantity == 0,
+									"bg-yellow-50 border-yellow-300 text-yellow-800": it.Quantity > 0,
+								},
+								Span(Class("capitalize"), Text(it.Name)),
+								Span(Textf("%d left", it.Quantity)),
+							)
+						}),
+					),
+				),
+			),
+		),
+	)
+}
+
+// revenueDelta shows the change against the same weekday last week.
+func revenueDelta(today, lastWeek float64) Node {
+	if lastWeek == 0 {
+		return P(Class("text-sm text-gray-400"), Text("No sales last week"))
+	}
+	pct := (today - lastWeek) / lastWeek * 100
+	return P(
+		Classes{
+			"text-sm":        true,
+			"text-green-600": pct >= 0,
+			"text-red-600":   pct < 0,
+		},
+		Textf("%+.0f%% vs last week", pct),
+	)
+}
+
+// hourlyChart renders a bar chart of sales per hour as inline SVG.
+func hourlyChart(hourly [24]float64) Node {
+	const (
+		width    = 720
+		height   = 200
+		barWidth = width / 24
+		chartTop = 10
+		chartH   = height - 30
+	)
+
+	maxValue := 0.0
+	for _, v := range hourly {
+		maxValue = math.Max(maxValue, v)
+	}
+
+	var bars []Node
+	for hour, v := range hourly {
+		h := 0.0
+		if maxValue > 0 {
+			h = v / maxValue * chartH
+		}
+		x := hour * barWidth
+		bars = append(bars,
+			El("rect",
+				Attr("x", fmt.Sprint(x+3)),
+				Attr("y", fmt.Sprintf("%.1f", chartTop+chartH-h)),
+				Attr("width", fmt.Sprint(barWidth-6)),
+				Attr("height", fmt.Sprintf("%.1f", h)),
+				Attr("rx", "2"),
+				Attr("class", "fill-indigo-500"),
+				El("title", Textf("%02d:00 – %s", hour, FormatTZS(v))),
+			),
+		)
+		if hour%3 == 0 {
+			bars = append(bars,
+				El("text",
+					Attr("x", fmt.Sprint(x+barWidth/2)),
+					Attr("y", fmt.Sprint(height-5)),
+					Attr("text-anchor", "middle"),
+					Attr("class", "fill-gray-500 text-xs"),
+					Textf("%02d", hour),
+				),
+			)
+		}
+	}
+
+	return SVG(
+		Attr("viewBox", fmt.Sprintf("0 0 %d %d", width, height)),
+		Attr("role", "img"),
+		Attr("aria-label", "Sales by hour"),
+		Class("w-full h-auto"),
+		El("line",
+			Attr("x1", "0"), Attr("x2", fmt.Sprint(width)),
+			Attr("y1", fmt.Sprint(chartTop+chartH)), Attr("y2", fmt.Sprint(chartTop+chartH)),
+			Attr("class", "stroke-gray-300"),
+		),
+		Group(bars),
+	)
+}
@@ -0,0 +1,366 @@
+package html
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/dispatch"
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// DispatchPage renders /dispatch: delivery orders in the kitchen, ready to
+// go out with a rider, and out for delivery, with the cash each rider
+// holds. The board reloads itself as orders change.
+//
+// Parameters:
+//   - board: the delivery orders by stage.
+//   - riders: users with the rider role, to assign.
+//   - zones: delivery zones, to name the zone of each order.
+//   - owed: the cash each rider holds from deliveries not yet settled, by
+//     rider ID.
+//   - now: the time waiting and delivery times are measured against.
+//   - errorMsg: optional error message to display above the board.
+func DispatchPage(board dispatch.Board, riders []model.User, zones []model.Zone, owed map[string]float64, now time.Time, errorMsg string) Node {
+	names := riderNames(riders)
+	zoneNames := map[string]string{}
+	for _, z := range zones {
+		zoneNames[z.ID] = z.Name
+	}
+
+	return Layout("/dispatch", true,
+		liveUpdates("/events?types="+event.NameOrderCreated+","+event.NameOrderStatusChanged,
+			"orders-changed", event.NameOrderCreated, event.NameOrderStatusChanged),
+		Div(
+			ID("board"),
+			Attr("hx-get", "/dispatch"),
+			Attr("hx-select", "#board"),
+			Attr("hx-swap", "outerHTML"),
+			Attr("hx-trigger", "orders-changed from:body throttle:2s, every 60s"),
+			Class("max-w-7xl mx-auto mt-8 mb-12 px-4 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Dispatch")),
+				Div(Class("flex gap-2"),
+					reportLink("/orders/pos?type="+model.OrderDelivery, "New Delivery"),
+					reportLink("/zones", "Zones"),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+			If(len(riders) == 0,
+				P(Class("text-gray-500"), Text("No riders yet. Give users the rider role in PocketBase to assign deliveries to them.")),
+			),
+
+			Div(Class("grid gap-6 lg:grid-cols-3"),
+				dispatchColumn("In the kitchen", board.Preparing, func(o model.Order) Node {
+					return deliveryCard(o, zoneNames, now, Span(Class("text-sm text-gray-500"), Textf("Ordered %s ago", formatSeated(since(o.CreatedAt, now)))))
+				}),
+				dispatchColumn("Ready to go", board.Ready, func(o model.Order) Node {
+					return deliveryCard(o, zoneNames, now,
+						Form(
+							Method("POST"),
+							Action("/dispatch/"+o.ID+"/send"),
+							Class("flex gap-2"),
+							Select(Name("rider"), Required(), Class("flex-grow border border-gray-300 rounded p-2"),
+								Option(Value(""), Text("Choose rider")),
+								Map(riders, func(u model.User) Node {
+									return Option(Value(u.ID), Text(u.Username), If(u.ID == o.Rider, Selected()))
+								}),
+							),
+							Button(Type("submit"), Class("bg-green-600 text-white font-semibold px-4 rounded hover:bg-green-700"), Text("Send")),
+						),
+					)
+				}),
+				dispatchColumn("Out for delivery", board.Out, func(o model.Order) Node {
+					return deliveryCard(o, zoneNames, now,
+						Div(Class("flex items-center justify-between gap-2"),
+							Span(Class("text-sm text-gray-700"),
+								Textf("%s · out %s", names[o.Rider], formatSeated(dispatch.Duration(o, now))),
+							),
+							postButton("/dispatch/"+o.ID+"/delivered", "Delivered", false),
+						),
+					)
+				}),
+			),
+
+			H3(Class("text-lg font-semibold text-gray-700"), Text("Riders")),
+			If(len(riders) > 0,
+				Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+					THead(Class("bg-gray-100 text-gray-700 text-left"),
+						Tr(
+							Th(Class("px-4 py-2"), Text("Rider")),
+							Th(Class("px-4 py-2 text-right"), Text("Out now")),
+							Th(Class("px-4 py-2 text-right"), Text("Cash held")),
+							Th(Class("px-4 py-2")),
+						),
+					),
+					TBody(
+						Map(riders, func(u model.User) Node {
+							out := 0
+							for _, o := range board.Out {
+								if o.Rider == u.ID {
+									out++
+								}
+							}
+							return Tr(Class("border-t"),
+								Td(Class("px-4 py-2 font-medium"), Text(u.Username)),
+								Td(Class("px-4 py-2 text-right"), Text(strconv.Itoa(out))),
+								Td(Class("px-4 py-2 text-right"), Text(FormatTZS(owed[u.ID]))),
+								Td(Class("px-4 py-2 text-right"),
+									A(Href("/riders/"+u.ID), Class("text-sm text-indigo-600 hover:underline"), Text("Cash up")),
+								),
+							)
+						}),
+					),
+				),
+			),
+		),
+	)
+}
+
+// dispatchColumn renders one stage of the dispatch board.
+func dispatchColumn(title string, orders []model.Order, card func(model.Order) Node) Node {
+	return Section(Class("space-y-3"),
+		H3(Class("font-semibold text-gray-700"), Textf("%s (%d)", title, len(orders))),
+		If(len(orders) == 0, P(Class("text-sm text-gray-400"), Text("Nothing here."))),
+		Map(orders, card),
+	)
+}
+
+// deliveryCard shows where a delivery goes and what it costs, with the
+// actions of its stage below.
+func deliveryCard(o model.Order, zones map[string]string, now time.Time, actions Node) Node {
+	return Div(Class("bg-white border border-gray-200 rounded-lg shadow-sm p-4 space-y-2"),
+		Div(Class("flex justify-between"),
+			A(Href("/orders/"+o.ID), Class("font-mono text-indigo-600 hover:underline"), Text("#"+shortID(o.ID))),
+			Span(Class("font-semibold"), Text(FormatTZS(o.TotalCost))),
+		),
+		If(o.Customer != "", P(Class("font-medium text-gray-800"), Text(o.Customer))),
+		P(Class("text-gray-700"), Text(o.Address)),
+		Div(Class("flex justify-between text-sm text-gray-600"),
+			A(Href("tel:"+o.Phone), Class("hover:underline"), Text(o.Phone)),
+			If(zones[o.Zone] != "", Span(Class("px-2 rounded-full bg-gray-100"), Text(zones[o.Zone]))),
+		),
+		If(o.Tender != "" && o.Tender != model.TenderCash, P(Class("text-sm text-green-700"), Textf("Paid by %s", o.Tender))),
+		If(o.Tender == "" || o.Tender == model.TenderCash, P(Class("text-sm text-amber-700"), Textf("Collect %s cash", FormatTZS(o.TotalCost)))),
+		actions,
+	)
+}
+
+// RiderPage renders /riders/{id}: the deliveries a rider made since their
+// last settlement with the cash each collected, a form to record the cash
+// handed in, and past settlements.
+//
+// Parameters:
+//   - rider: the rider.
+//   - orders: delivered orders not yet settled.
+//   - settlements: past settlements, newest first.
+//   - errorMsg: optional error message to display at the top.
+func RiderPage(rider model.User, orders []model.Order, settlements []model.RiderSettlement, errorMsg string) Node {
+	var expected float64
+	for _, o := range orders {
+		expected += o.Collected
+	}
+
+	return Layout("/dispatch", true,
+		Div(
+			ID("main"),
+			Class("max-w-3xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Cash up: "+rider.Username)),
+				reportLink("/dispatch", "Dispatch"),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			If(len(orders) == 0, P(Class("text-gray-500"), Text("Every delivery is settled."))),
+			If(len(orders) > 0,
+				Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+					THead(Class("bg-gray-100 text-gray-700 text-left"),
+						Tr(
+							Th(Class("px-4 py-2"), Text("Order")),
+							Th(Class("px-4 py-2"), Text("Delivered")),
+							Th(Class("px-4 py-2 text-right"), Text("Took")),
+							Th(Class("px-4 py-2 text-right"), Text("Cash")),
+						),
+					),
+					TBody(
+						Map(orders, func(o model.Order) Node {
+							return Tr(Class("border-t"),
+								Td(Class("px-4 py-2"), A(Href("/orders/"+o.ID), Class("font-mono text-indigo-600 hover:underline"), Text("#"+shortID(o.ID)))),
+								Td(Class("px-4 py-2 text-gray-600"), Text(formatTimestamp(o.DeliveredAt))),
+								Td(Class("px-4 py-2 text-right text-gray-600"), Text(formatSeated(dispatch.Duration(o, time.Time{})))),
+								Td(Class("px-4 py-2 text-right"),
+									If(o.Collected > 0, Text(FormatTZS(o.Collected))),
+									If(o.Collected == 0, Span(Class("text-gray-400"), Text("prepaid"))),
+								),
+							)
+						}),
+					),
+				),
+			),
+
+			If(len(orders) > 0,
+				Form(
+					Method("POST"),
+					Action("/riders/"+rider.ID+"/settle"),
+					Class("bg-white border border-gray-200 rounded-lg p-4 space-y-3"),
+					Div(Class("flex justify-between text-lg font-semibold text-gray-800"),
+						Span(Text("Cash expected")),
+						Span(Text(FormatTZS(expected))),
+					),
+					Div(Class("flex items-center gap-3"),
+						Label(For("counted"), Class("text-gray-700 whitespace-nowrap"), Text("Cash handed in")),
+						Input(Type("number"), ID("counted"), Name("counted"), Min("0"), Step("1"), Required(),
+							Value(strconv.FormatFloat(expected, 'f', -1, 64)),
+							Class("flex-grow border border-gray-300 rounded p-2"),
+						),
+					),
+					Input(Type("text"), Name("note"), Placeholder("Note, e.g. why cash is short (optional)"),
+						Class("w-full border border-gray-300 rounded p-2"),
+					),
+					Button(Type("submit"),
+						Class("w-full bg-indigo-600 text-white font-semibold py-3 rounded-lg hover:bg-indigo-700"),
+						Text("Settle"),
+					),
+				),
+			),
+
+			If(len(settlements) > 0, H3(Class("text-lg font-semibold text-gray-700"), Text("Past settlements"))),
+			If(len(settlements) > 0,
+				Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+					THead(Class("bg-gray-100 text-gray-700 text-left"),
+						Tr(
+							Th(Class("px-4 py-2"), Text("Settled")),
+							Th(Class("px-4 py-2 text-right"), Text("Deliveries")),
+							Th(Class("px-4 py-2 text-right"), Text("Expected")),
+							Th(Class("px-4 py-2 text-right"), Text("Handed in")),
+							Th(Class("px-4 py-2 text-right"), Text("Difference")),
+						),
+					),
+					TBody(
+						Map(settlements, func(s model.RiderSettlement) Node {
+							return Tr(Class("border-t"),
+								Td(Class("px-4 py-2 text-gray-600"),
+									Text(formatTimestamp(s.Created)),
+									If(s.Note != "", P(Class("text-sm italic"), Text(s.Note))),
+								),
+								Td(Class("px-4 py-2 text-right"), Text(strconv.Itoa(len(s.Orders)))),
+								Td(Class("px-4 py-2 text-right"), Text(FormatTZS(s.Expected))),
+								Td(Class("px-4 py-2 text-right"), Text(FormatTZS(s.Counted))),
+								Td(
+									Classes{
+										"px-4 py-2 text-right font-semibold": true,
+										"text-red-700":                       s.Difference < 0,
+										"text-green-700":                     s.Difference >= 0,
+									},
+									Text(FormatTZS(s.Difference)),
+								),
+							)
+						}),
+					),
+				),
+			),
+		),
+	)
+}
+
+// ZonesPage renders /zones, where delivery zones and their fees are added,
+// edited in place and deleted.
+func ZonesPage(zones []model.Zone, errorMsg string) Node {
+	return Layout("/dispatch", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Delivery Zones")),
+				reportLink("/dispatch", "Dispatch"),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			P(Class("text-gray-600"), Text("Delivery orders are charged the fee of their zone, or nothing once the order reaches the zone's free delivery amount.")),
+
+			Div(Class("space-y-3"),
+				If(len(zones) == 0, P(Class("text-gray-500"), Text("No zones yet; delivery fees are entered by hand."))),
+				Map(zones, func(z model.Zone) Node {
+					return Div(Class("flex items-center gap-3 bg-white border border-gray-200 rounded-lg p-3"),
+						Form(
+							Method("POST"),
+							Action("/zones/"+z.ID),
+							Class("flex flex-grow items-center gap-3"),
+							zoneFields(z),
+							Button(Type("submit"), Class("text-sm bg-indigo-600 text-white px-3 py-2 rounded hover:bg-indigo-700"), Text("Save")),
+						),
+						Form(
+							Method("POST"),
+							Action("/zones/"+z.ID+"/delete"),
+							Attr("onsubmit", "return confirm('Delete this zone?')"),
+							Button(Type("submit"), Class("text-sm border border-red-400 text-red-700 px-3 py-2 rounded hover:bg-red-50"), Text("Delete")),
+						),
+					)
+				}),
+			),
+
+			H3(Class("text-lg font-semibold text-gray-700"), Text("Add Zone")),
+			Form(
+				Method("POST"),
+				Action("/zones"),
+				Class("flex items-center gap-3 bg-white border border-gray-200 rounded-lg p-3"),
+				zoneFields(model.Zone{}),
+				Button(Type("submit"), Class("text-sm bg-green-600 text-white px-3 py-2 rounded hover:bg-green-700"), Text("Add")),
+			),
+		),
+	)
+}
+
+// zoneFields are the inputs shared by the add and edit forms.
+func zoneFields(z model.Zone) Node {
+	freeFrom := ""
+	if z.FreeFrom > 0 {
+		freeFrom = strconv.FormatFloat(z.FreeFrom, 'f', -1, 64)
+	}
+	return Group([]Node{
+		Input(Type("text"), Name("name"), Value(z.Name), Placeholder("Name, e.g. Msasani"), Required(),
+			Class("flex-grow border border-gray-300 rounded p-2"),
+		),
+		Input(Type("number"), Name("fee"), Value(strconv.FormatFloat(z.Fee, 'f', -1, 64)), Min("0"), Step("1"), Required(),
+			Attr("title", "Delivery fee (TZS)"),
+			Class("w-32 border border-gray-300 rounded p-2"),
+		),
+		Input(Type("number"), Name("free_from"), Value(freeFrom), Min("0"), Step("1"), Placeholder("Free from (optional)"),
+			Class("w-48 border border-gray-300 rounded p-2"),
+		),
+	})
+}
+
+// riderNames maps rider IDs to their names.
+func riderNames(riders []model.User) map[string]string {
+	names := map[string]string{}
+	for _, u := range riders {
+		names[u.ID] = u.Username
+	}
+	return names
+}
+
+// since returns how long ago a PocketBase datetime was, or zero if it
+// cannot be read.
+func since(s string, now time.Time) time.Duration {
+	t, err := model.ParseTime(s)
+	if err != nil {
+		return 0
+	}
+	return now.Sub(t)
+}
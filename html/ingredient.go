@@ -0,0 +1,145 @@
+package html
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// IngredientsPage renders the /ingredients stock page.
+// Each ingredient can be renamed or restocked in place; out-of-stock
+// ingredients are highlighted together with the items they make unavailable.
+//
+// Parameters:
+//   - ingredients: all ingredients by name.
+//   - usedBy: names of the items whose recipe uses each ingredient, by ingredient ID.
+//   - errorMsg: optional error message to display above the list.
+func IngredientsPage(ingredients []model.Ingredient, usedBy map[string][]string, errorMsg string) Node {
+	return Layout("/ingredients", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			H2(Class("text-2xl font-bold text-gray-800"), Text("Ingredients")),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Div(Class("space-y-3"),
+				If(len(ingredients) == 0, P(Class("text-gray-500"), Text("No ingredients yet."))),
+				Map(ingredients, func(ing model.Ingredient) Node {
+					out := ing.Quantity <= 0
+					return Div(
+						Classes{
+							"bg-white border rounded-lg p-3 space-y-2": true,
+							"border-gray-200":                          !out,
+							"border-red-400":                           out,
+						},
+						Div(Class("flex items-center gap-3"),
+							Form(
+								Method("POST"),
+								Action("/ingredients/"+ing.ID),
+								Class("flex flex-grow items-center gap-3"),
+								ingredientFields(ing),
+								Button(Type("submit"), Class("text-sm bg-indigo-600 text-white px-3 py-2 rounded hover:bg-indigo-700"), Text("Save")),
+							),
+							Form(
+								Method("POST"),
+								Action("/ingredients/"+ing.ID+"/delete"),
+								Attr("onsubmit", "return confirm('Delete this ingredient?')"),
+								Button(Type("submit"), Class("text-sm border border-red-400 text-red-700 px-3 py-2 rounded hover:bg-red-50"), Text("Delete")),
+							),
+						),
+						P(Class("text-sm text-gray-500"),
+							If(out, Span(Class("text-red-600 font-semibold mr-2"), Text("Out of stock"))),
+							If(len(usedBy[ing.ID]) == 0, Text("Not used in any recipe")),
+							If(len(usedBy[ing.ID]) > 0, Text("Used by "+strings.Join(usedBy[ing.ID], ", "))),
+						),
+					)
+				}),
+			),
+
+			H3(Class("text-lg font-semibold text-gray-700"), Text("Add Ingredient")),
+			Form(
+				Method("POST"),
+				Action("/ingredients"),
+				Class("flex items-center gap-3 bg-white border border-gray-200 rounded-lg p-3"),
+				ingredientFields(model.Ingredient{Unit: model.UnitGram}),
+				Button(Type("submit"), Class("text-sm bg-green-600 text-white px-3 py-2 rounded hover:bg-green-700"), Text("Add")),
+			),
+		),
+	)
+}
+
+// ingredientFields are the inputs shared by the add and edit forms.
+func ingredientFields(ing model.Ingredient) Node {
+	return Group([]Node{
+		Input(Type("text"), Name("name"), Value(ing.Name), Placeholder("Name"), Required(),
+			Class("flex-grow border border-gray-300 rounded p-2"),
+		),
+		Input(Type("number"), Name("quantity"), Value(formatAmount(ing.Quantity)), Min("0"), Step("any"), Title("In stock"),
+			Class("w-28 border border-gray-300 rounded p-2"),
+		),
+		unitSelect(ing.Unit),
+	})
+}
+
+func unitSelect(selected string) Node {
+	return Select(Name("unit"), Class("border border-gray-300 rounded p-2"),
+		Map(model.Units, func(u string) Node {
+			return Option(Value(u), Text(u), If(u == selected, Selected()))
+		}),
+	)
+}
+
+// formatAmount formats an ingredient quantity without trailing zeros.
+func formatAmount(q float64) string {
+	return strconv.FormatFloat(q, 'f', -1, 64)
+}
+
+// recipeFields renders the recipe rows of the item edit form: the item's
+// ingredients plus a few empty rows to add more.
+func recipeFields(recipe []model.RecipeLine, ingredients []model.Ingredient) Node {
+	units := map[string]string{}
+	for _, ing := range ingredients {
+		units[ing.ID] = ing.Unit
+	}
+
+	rows := append([]model.RecipeLine{}, recipe...)
+	for range 3 {
+		rows = append(rows, model.RecipeLine{})
+	}
+
+	return Div(
+		Span(Class("block font-medium text-gray-700 mb-1"), Text("Recipe (per portion)")),
+		P(Class("text-sm text-gray-500 mb-2"),
+			Text("Items with a recipe are available while their ingredients last; their own quantity is not used."),
+		),
+		Div(Class("space-y-2"),
+			Map(rows, func(line model.RecipeLine) Node {
+				opts := []Node{Option(Value(""), Text("—"))}
+				for _, ing := range ingredients {
+					opts = append(opts, Option(Value(ing.ID), Textf("%s (%s)", ing.Name, ing.Unit), If(ing.ID == line.Ingredient, Selected())))
+				}
+				qty := ""
+				if line.Quantity > 0 {
+					qty = formatAmount(line.Quantity)
+				}
+				return Div(Class("flex gap-2"),
+					Select(append([]Node{Name("recipe_ingredient"), Class("flex-grow border border-gray-300 rounded p-2")}, opts...)...),
+					Input(Type("number"), Name("recipe_quantity"), Value(qty), Min("0"), Step("any"), Placeholder("Qty"),
+						Class("w-28 border border-gray-300 rounded p-2"),
+					),
+				)
+			}),
+		),
+		P(Class("mt-1 text-sm text-gray-500"),
+			A(Href("/ingredients"), Class("text-indigo-600 hover:underline"), Text("Manage ingredients")),
+		),
+	)
+}
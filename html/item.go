@@ -1,6 +1,11 @@
 package html
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/internal/ordertype"
+	"github.com/rustacean-dev/possystem/model"
 	. "maragu.dev/gomponents"
 	. "maragu.dev/gomponents/html"
 )
@@ -11,14 +16,28 @@ import (
 //
 // Parameters:
 //   - errorMessage: optional error message to display at the top of the form.
+//   - categories: menu categories to choose from.
+//   - draft: values to prefill, e.g. a scanned barcode or the previous submission.
+
+func NewItemPage(errorMessage string, categories []model.Category, draft model.Item) Node {
+	price := ""
+	if draft.Price > 0 {
+		price = fmt.Sprint(draft.Price)
+	}
+	quantity := "1"
+	if draft.Quantity > 0 {
+		quantity = fmt.Sprint(draft.Quantity)
+	}
 
-func NewItemPage(errorMessage string) Node {
 	return Layout("/items/new", true,
 		Div(
 			ID("main"),
 			Class("max-w-md mx-auto mt-12"),
 
-			H2(Class("text-3xl font-bold mb-6 text-gray-800"), Text("Add New Menu Item")),
+			Div(Class("flex items-center justify-between mb-6"),
+				H2(Class("text-3xl font-bold text-gray-800"), Text("Add New Menu Item")),
+				A(Href("/items/import"), Class("text-sm text-indigo-600 hover:underline"), Text("Import CSV")),
+			),
 
 			If(errorMessage != "",
 				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded mb-6"),
@@ -36,7 +55,7 @@ func NewItemPage(errorMessage string) Node {
 
 				Div(
 					Label(For("name"), Class("block font-medium text-gray-700 mb-1"), Text("Item Name")),
-					Input(Type("text"), ID("name"), Name("name"),
+					Input(Type("text"), ID("name"), Name("name"), Value(draft.Name),
 						Class("w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"),
 						Required(),
 					),
@@ -44,27 +63,35 @@ func NewItemPage(errorMessage string) Node {
 
 				Div(
 					Label(For("price"), Class("block font-medium text-gray-700 mb-1"), Text("Price (TZS)")),
-					Input(Type("number"), ID("price"), Name("price"),
+					Input(Type("number"), ID("price"), Name("price"), Value(price),
 						Class("w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"),
 						Step("0.01"),
 						Required(),
 					),
 				),
 
+				costField(draft.Cost),
+
+				typePriceFields(draft),
+
 				Div(
 					Label(For("description"), Class("block font-medium text-gray-700 mb-1"), Text("Description (optional)")),
 					Textarea(Name("description"), ID("description"),
 						Class("w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"),
-						Text(""),
+						Text(draft.Description),
 					),
 				),
 
+				categorySelect(categories, draft.Category),
+
+				codeFields(draft),
+
 				Div(
 					Label(For("quantity"), Class("block font-medium text-gray-700 mb-1"), Text("Available Quantity")),
 					Input(Type("number"), ID("quantity"), Name("quantity"),
 						Class("w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"),
 						Min("0"),
-						Value("1"),
+						Value(quantity),
 					),
 				),
 
@@ -76,3 +103,82 @@ func NewItemPage(errorMessage string) Node {
 		),
 	)
 }
+
+// categorySelect renders the category dropdown of the item forms.
+func categorySelect(categories []model.Category, selected string) Node {
+	opts := []Node{Option(Value(""), Text("No category"))}
+	for _, c := range categories {
+		opts = append(opts, Option(Value(c.ID), Text(c.Name), If(c.ID == selected, Selected())))
+	}
+
+	return Div(
+		Label(For("category"), Class("block font-medium text-gray-700 mb-1"), Text("Category")),
+		Select(
+			append([]Node{
+				ID("category"),
+				Name("category"),
+				Class("w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"),
+			}, opts...)...,
+		),
+		P(Class("mt-1 text-sm text-gray-500"),
+			A(Href("/categories"), Class("text-indigo-600 hover:underline"), Text("Manage categories")),
+		),
+	)
+}
+
+// costField renders the unit cost input of the item forms.
+func costField(cost float64) Node {
+	value := ""
+	if cost > 0 {
+		value = fmt.Sprint(cost)
+	}
+	return Div(
+		Label(For("cost"), Class("block font-medium text-gray-700 mb-1"), Text("Cost (TZS, optional)")),
+		Input(Type("number"), ID("cost"), Name("cost"), Value(value), Step("0.01"), Min("0"),
+			Class("w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"),
+		),
+		P(Class("mt-1 text-sm text-gray-500"), Text("What one unit costs to buy or make; used to value stock.")),
+	)
+}
+
+// typePriceFields renders the optional prices of an item for each order
+// type, e.g. a higher price for delivery. Blank fields use the normal price.
+func typePriceFields(item model.Item) Node {
+	return Div(
+		P(Class("block font-medium text-gray-700 mb-1"), Text("Prices by order type (TZS, optional)")),
+		Div(Class("grid grid-cols-3 gap-3"),
+			Map(ordertype.Types, func(t string) Node {
+				value := ""
+				if p, ok := item.TypePrices[t]; ok {
+					value = fmt.Sprint(p)
+				}
+				return Div(
+					Label(For("price_"+t), Class("block text-sm text-gray-600 mb-1"), Text(ordertype.Label(t))),
+					Input(Type("number"), ID("price_"+t), Name("price_"+t), Value(value), Step("0.01"), Min("0"),
+						Class("w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"),
+					),
+				)
+			}),
+		),
+		P(Class("mt-1 text-sm text-gray-500"), Text("Leave blank to sell at the normal price.")),
+	)
+}
+
+// codeFields renders the SKU and barcode inputs of the item forms.
+func codeFields(item model.Item) Node {
+	return Div(Class("grid grid-cols-1 md:grid-cols-2 gap-4"),
+		Div(
+			Label(For("sku"), Class("block font-medium text-gray-700 mb-1"), Text("SKU (optional)")),
+			Input(Type("text"), ID("sku"), Name("sku"), Value(item.SKU),
+				Class("w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"),
+			),
+		),
+		Div(
+			Label(For("barcodes"), Class("block font-medium text-gray-700 mb-1"), Text("Barcodes (optional)")),
+			Input(Type("text"), ID("barcodes"), Name("barcodes"), Value(strings.Join(item.Barcodes, ", ")),
+				Placeholder("Separate several with commas"),
+				Class("w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"),
+			),
+		),
+	)
+}
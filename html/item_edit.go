@@ -0,0 +1,183 @@
+package html
+
+import (
+	"fmt"
+
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// EditItemPage renders the /items/{id}/edit page.
+// It allows editing every field of an item, archiving or restoring it,
+// and shows the item's price history.
+//
+// Parameters:
+//   - item: the item being edited.
+//   - categories: menu categories to choose from.
+//   - groups: modifier groups that can be offered with the item.
+//   - ingredients: ingredients the item's recipe can use.
+//   - suppliers: suppliers the item can be reordered from.
+//   - history: recorded price changes, newest first.
+//   - errorMessage: optional error message to display at the top of the form.
+func EditItemPage(item model.Item, categories []model.Category, groups []model.ModifierGroup, ingredients []model.Ingredient, suppliers []model.Supplier, history []model.PriceChange, errorMessage string) Node {
+	inputClass := "w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"
+
+	return Layout("/items/edit", true,
+		Div(
+			ID("main"),
+			Class("max-w-2xl mx-auto mt-12 mb-12 space-y-8"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-3xl font-bold text-gray-800 capitalize"), Text("Edit "+item.Name)),
+				Div(Class("flex items-center gap-3"),
+					If(item.Archived,
+						Span(Class("px-3 py-1 rounded-full bg-gray-200 text-gray-700 text-sm"), Text("Archived")),
+					),
+					A(Href("/items/"+item.ID+"/stock"), Class("text-sm text-indigo-600 hover:underline"), Text("Stock history")),
+				),
+			),
+
+			If(errorMessage != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMessage)),
+			),
+
+			Form(
+				Method("POST"),
+				Action("/items/"+item.ID+"/edit"),
+				Class("space-y-6"),
+
+				Div(
+					Label(For("name"), Class("block font-medium text-gray-700 mb-1"), Text("Item Name")),
+					Input(Type("text"), ID("name"), Name("name"), Value(item.Name), Class(inputClass), Required()),
+				),
+
+				Div(
+					Label(For("price"), Class("block font-medium text-gray-700 mb-1"), Text("Price (TZS)")),
+					Input(Type("number"), ID("price"), Name("price"), Value(fmt.Sprint(item.Price)), Step("0.01"), Class(inputClass), Required()),
+				),
+
+				costField(item.Cost),
+
+				typePriceFields(item),
+
+				Div(
+					Label(For("description"), Class("block font-medium text-gray-700 mb-1"), Text("Description (optional)")),
+					Textarea(Name("description"), ID("description"), Class(inputClass), Text(item.Description)),
+				),
+
+				categorySelect(categories, item.Category),
+
+				codeFields(item),
+
+				modifierGroupChecks(groups, item.ModifierGroups),
+
+				recipeFields(item.Recipe, ingredients),
+
+				Div(
+					Label(For("quantity"), Class("block font-medium text-gray-700 mb-1"), Text("Available Quantity")),
+					Input(Type("number"), ID("quantity"), Name("quantity"), Value(fmt.Sprint(item.Quantity)), Min("0"), Class(inputClass)),
+				),
+
+				reorderFields(item, suppliers),
+
+				Button(Type("submit"),
+					Class("w-full bg-indigo-600 text-white font-semibold py-2 px-4 rounded hover:bg-indigo-700 transition"),
+					Text("Save Changes"),
+				),
+			),
+
+			// Archiving keeps the record so past orders still resolve
+			Form(
+				Method("POST"),
+				If(item.Archived, Action("/items/"+item.ID+"/restore")),
+				If(!item.Archived, Action("/items/"+item.ID+"/archive")),
+				If(!item.Archived, Attr("onsubmit", "return confirm('Archive this item? It will no longer be sold.')")),
+				Button(Type("submit"),
+					If(item.Archived, Class("w-full font-semibold py-2 px-4 rounded transition bg-green-600 text-white hover:bg-green-700")),
+					If(!item.Archived, Class("w-full font-semibold py-2 px-4 rounded transition bg-white border border-red-400 text-red-700 hover:bg-red-50")),
+					If(item.Archived, Text("Restore Item")),
+					If(!item.Archived, Text("Archive Item")),
+				),
+			),
+
+			priceHistoryTable(history),
+		),
+	)
+}
+
+// reorderFields renders the reorder point, par level, supplier and
+// perishable inputs.
+func reorderFields(item model.Item, suppliers []model.Supplier) Node {
+	level := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return fmt.Sprint(n)
+	}
+	inputClass := "w-full border border-gray-300 rounded p-2 focus:ring focus:border-indigo-500"
+
+	return Div(Class("space-y-2"),
+		Div(Class("grid grid-cols-3 gap-3"),
+			Div(
+				Label(For("reorder_point"), Class("block font-medium text-gray-700 mb-1"), Text("Reorder point")),
+				Input(Type("number"), ID("reorder_point"), Name("reorder_point"), Value(level(item.ReorderPoint)), Min("0"), Step("1"), Class(inputClass)),
+			),
+			Div(
+				Label(For("par_level"), Class("block font-medium text-gray-700 mb-1"), Text("Par level")),
+				Input(Type("number"), ID("par_level"), Name("par_level"), Value(level(item.ParLevel)), Min("0"), Step("1"), Class(inputClass)),
+			),
+			Div(
+				Label(For("supplier"), Class("block font-medium text-gray-700 mb-1"), Text("Supplier")),
+				Select(ID("supplier"), Name("supplier"), Class(inputClass),
+					Option(Value(""), Text("None")),
+					Map(suppliers, func(s model.Supplier) Node {
+						return Option(Value(s.ID), Text(s.Name), If(s.ID == item.Supplier, Selected()))
+					}),
+				),
+			),
+		),
+		Label(Class("flex items-center gap-2 text-gray-700"),
+			Input(Type("checkbox"), Name("perishable"), Value("true"), If(item.Perishable, Checked())),
+			Text("Perishable — track deliveries in lots with an expiry date"),
+		),
+		P(Class("text-sm text-gray-500"),
+			Text("An alert is raised when stock drops to the reorder point; the reorder list orders back up to the par level. "),
+			A(Href("/suppliers"), Class("text-indigo-600 hover:underline"), Text("Manage suppliers")),
+		),
+	)
+}
+
+// priceHistoryTable lists an item's price changes with their date and author.
+func priceHistoryTable(history []model.PriceChange) Node {
+	return Div(
+		H3(Class("text-lg font-semibold text-gray-700 mb-2"), Text("Price History")),
+		If(len(history) == 0, P(Class("text-gray-500"), Text("The price has not been changed yet."))),
+		If(len(history) > 0,
+			Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+				THead(Class("bg-gray-100 text-gray-700"),
+					Tr(
+						Th(Class("px-4 py-2 text-left"), Text("Effective")),
+						Th(Class("px-4 py-2 text-left"), Text("Old Price")),
+						Th(Class("px-4 py-2 text-left"), Text("New Price")),
+						Th(Class("px-4 py-2 text-left"), Text("Changed By")),
+					),
+				),
+				TBody(
+					Map(history, func(pc model.PriceChange) Node {
+						author := pc.Expand.Author.Username
+						if author == "" {
+							author = pc.Author
+						}
+						return Tr(
+							Td(Class("px-4 py-2 border-t"), Text(formatTimestamp(pc.EffectiveAt))),
+							Td(Class("px-4 py-2 border-t"), Text(FormatTZS(pc.OldPrice))),
+							Td(Class("px-4 py-2 border-t"), Text(FormatTZS(pc.NewPrice))),
+							Td(Class("px-4 py-2 border-t"), Text(author)),
+						)
+					}),
+				),
+			),
+		),
+	)
+}
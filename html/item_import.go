@@ -0,0 +1,178 @@
+package html
+
+import (
+	"fmt"
+
+	"github.com/rustacean-dev/possystem/internal/catalog"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// ImportItemsPage renders the bulk item import upload form.
+// The CSV needs the columns name, price, description, quantity and category,
+// either in that order or named in a header row.
+//
+// Parameters:
+//   - errorMessage: optional error message to display at the top of the form.
+func ImportItemsPage(errorMessage string) Node {
+	return Layout("/items/import", true,
+		Div(
+			ID("main"),
+			Class("max-w-2xl mx-auto mt-12 space-y-6"),
+
+			H2(Class("text-3xl font-bold text-gray-800"), Text("Import Items from CSV")),
+
+			P(Class("text-gray-600"),
+				Text("Columns: name, price, description, quantity, category. The category must match the name of an existing category. Items that already exist get the quantity added to their stock; everything else is created. You will see a preview before anything is saved."),
+			),
+
+			If(errorMessage != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMessage)),
+			),
+
+			Form(
+				Method("POST"),
+				Action("/items/import"),
+				EncType("multipart/form-data"),
+				Class("space-y-6"),
+
+				Div(
+					Label(For("file"), Class("block font-medium text-gray-700 mb-1"), Text("CSV file")),
+					Input(Type("file"), ID("file"), Name("file"), Accept(".csv,text/csv"),
+						Class("w-full border border-gray-300 rounded p-2"),
+					),
+				),
+
+				Div(
+					Label(For("csv"), Class("block font-medium text-gray-700 mb-1"), Text("…or paste CSV")),
+					Textarea(ID("csv"), Name("csv"), Rows("8"),
+						Placeholder("name,price,description,quantity,category\nchai,1500,Spiced tea,50,Drinks"),
+						Class("w-full border border-gray-300 rounded p-2 font-mono text-sm"),
+					),
+				),
+
+				Button(Type("submit"),
+					Class("w-full bg-indigo-600 text-white font-semibold py-2 px-4 rounded hover:bg-indigo-700 transition"),
+					Text("Preview Import"),
+				),
+			),
+		),
+	)
+}
+
+// ImportPreviewPage shows what an import will do before it is applied.
+// The original CSV is carried in a hidden field so the confirmation
+// re-validates it against the current stock.
+func ImportPreviewPage(plan catalog.Plan, csvText string) Node {
+	return Layout("/items/import", true,
+		Div(
+			ID("main"),
+			Class("max-w-5xl mx-auto mt-12 mb-12 space-y-6"),
+
+			H2(Class("text-3xl font-bold text-gray-800"), Text("Import Preview")),
+
+			P(Class("text-gray-600"),
+				Textf("%d new items, %d stock updates, %d rows with errors.", len(plan.Creates), len(plan.Updates), len(plan.Errors)),
+			),
+
+			importErrors(plan.Errors),
+			importChanges("New Items", plan.Creates),
+			importChanges("Stock Updates", plan.Updates),
+
+			Form(
+				Method("POST"),
+				Action("/items/import/confirm"),
+				Class("flex gap-4"),
+				Textarea(Name("csv"), Class("hidden"), Text(csvText)),
+				Button(Type("submit"),
+					If(len(plan.Creates)+len(plan.Updates) == 0, Disabled()),
+					Class("bg-green-600 text-white font-semibold py-2 px-4 rounded hover:bg-green-700 transition disabled:opacity-50"),
+					Text("Confirm Import"),
+				),
+				A(Href("/items/import"), Class("py-2 px-4 rounded border border-gray-300 text-gray-700 hover:bg-gray-100"), Text("Cancel")),
+			),
+		),
+	)
+}
+
+// ImportResultPage reports what an applied import did, row by row.
+func ImportResultPage(created, updated []catalog.Change, errs []catalog.RowError) Node {
+	return Layout("/items/import", true,
+		Div(
+			ID("main"),
+			Class("max-w-5xl mx-auto mt-12 mb-12 space-y-6"),
+
+			H2(Class("text-3xl font-bold text-gray-800"), Text("Import Finished")),
+
+			P(Class("text-gray-600"),
+				Textf("Created %d items and updated stock of %d. %d rows failed.", len(created), len(updated), len(errs)),
+			),
+
+			importErrors(errs),
+			importChanges("Created", created),
+			importChanges("Stock Updated", updated),
+
+			Div(Class("flex gap-4"),
+				A(Href("/items/import"), Class("text-indigo-600 hover:underline"), Text("Import another file")),
+				A(Href("/orders/new"), Class("text-indigo-600 hover:underline"), Text("Go to New Order")),
+			),
+		),
+	)
+}
+
+func importChanges(title string, changes []catalog.Change) Node {
+	if len(changes) == 0 {
+		return nil
+	}
+	return Div(
+		H3(Class("text-lg font-semibold text-gray-700 mb-2"), Textf("%s (%d)", title, len(changes))),
+		Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+			THead(Class("bg-gray-100 text-gray-700"),
+				Tr(
+					Th(Class("px-4 py-2 text-left"), Text("Line")),
+					Th(Class("px-4 py-2 text-left"), Text("Name")),
+					Th(Class("px-4 py-2 text-left"), Text("Price")),
+					Th(Class("px-4 py-2 text-left"), Text("Category")),
+					Th(Class("px-4 py-2 text-left"), Text("Stock")),
+				),
+			),
+			TBody(
+				Map(changes, func(c catalog.Change) Node {
+					price := FormatTZS(c.Row.Price)
+					if !c.IsCreate() && c.Existing.Price != c.Row.Price {
+						price = fmt.Sprintf("%s (kept, file says %s)", FormatTZS(c.Existing.Price), FormatTZS(c.Row.Price))
+					}
+					stock := fmt.Sprintf("%d", c.Row.Quantity)
+					if !c.IsCreate() {
+						stock = fmt.Sprintf("%d → %d", c.Existing.Quantity, c.NewQuantity())
+					}
+					return Tr(
+						Td(Class("px-4 py-2 border-t"), Textf("%d", c.Row.Line)),
+						Td(Class("px-4 py-2 border-t capitalize"), Text(c.Row.Name)),
+						Td(Class("px-4 py-2 border-t"), Text(price)),
+						Td(Class("px-4 py-2 border-t"), Text(c.Row.Category)),
+						Td(Class("px-4 py-2 border-t"), Text(stock)),
+					)
+				}),
+			),
+		),
+	)
+}
+
+func importErrors(errs []catalog.RowError) Node {
+	if len(errs) == 0 {
+		return nil
+	}
+	return Div(Class("bg-red-50 border border-red-300 rounded p-4"),
+		H3(Class("text-lg font-semibold text-red-700 mb-2"), Textf("Errors (%d)", len(errs))),
+		Ul(Class("list-disc pl-6 text-red-700 space-y-1"),
+			Map(errs, func(e catalog.RowError) Node {
+				label := fmt.Sprintf("Line %d", e.Line)
+				if e.Name != "" {
+					label += " (" + e.Name + ")"
+				}
+				return Li(Text(label + ": " + e.Err))
+			}),
+		),
+	)
+}
@@ -0,0 +1,230 @@
+package html
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/internal/analytics"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// ItemListPage renders the /items catalogue.
+// It shows a searchable, sortable, paginated table of items with their
+// price, stock and last update. Clicking a quantity edits it in place.
+//
+// Parameters:
+//   - page: the current page of items.
+//   - q: the query that produced the page, used to build sort, filter and page links.
+//   - categories: used to show category names.
+//   - errorMsg: optional error message to display above the table.
+func ItemListPage(page model.ItemPage, q model.ItemQuery, categories []model.Category, errorMsg string) Node {
+	return Layout("/items", true,
+		Div(
+			ID("main"),
+			Class("max-w-6xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Items")),
+				Div(Class("flex gap-2"),
+					reportLink("/items/new", "Add Item"),
+					reportLink("/items/import", "Import CSV"),
+					reportLink("/stocktakes", "Stocktake"),
+					reportLink("/reorder", "Reorder"),
+					reportLink("/purchase-orders", "Purchasing"),
+					reportLink("/lots/expiring", "Expiring"),
+					reportLink("/items/export.csv", "Export CSV"),
+					reportLink("/items/export.xlsx", "Export Excel"),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Form(
+				Method("GET"),
+				Action("/items"),
+				Attr("hx-get", "/items"),
+				Attr("hx-target", "#item-table"),
+				Attr("hx-select", "#item-table"),
+				Attr("hx-swap", "outerHTML"),
+				Attr("hx-push-url", "true"),
+				Attr("hx-trigger", "input changed delay:300ms from:#search, change from:#stock, submit"),
+				Class("flex flex-wrap items-end gap-4"),
+
+				Input(Type("hidden"), Name("sort"), Value(q.Sort)),
+				Div(Class("flex-grow"),
+					Label(For("search"), Class("block text-sm text-gray-600 mb-1"), Text("Search")),
+					Input(Type("search"), ID("search"), Name("q"), Value(q.Search), Placeholder("Name or description"),
+						Class("w-full border border-gray-300 p-2 rounded"),
+					),
+				),
+				Div(
+					Label(For("stock"), Class("block text-sm text-gray-600 mb-1"), Text("Stock")),
+					Select(ID("stock"), Name("stock"), Class("border border-gray-300 p-2 rounded"),
+						Option(Value(""), Text("All items"), If(q.Stock == "", Selected())),
+						Option(Value("low"), Text("Low stock"), If(q.Stock == "low", Selected())),
+						Option(Value("out"), Text("Out of stock"), If(q.Stock == "out", Selected())),
+					),
+				),
+				Button(Type("submit"),
+					Class("bg-indigo-600 text-white px-4 py-2 rounded hover:bg-indigo-700 transition"),
+					Text("Search"),
+				),
+			),
+
+			itemTable(page, q, categoryNames(categories)),
+		),
+	)
+}
+
+// itemTable is the part of the item list that is swapped on search.
+func itemTable(page model.ItemPage, q model.ItemQuery, categoryNames map[string]string) Node {
+	return Div(ID("item-table"), Class("space-y-4"),
+		Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+			THead(Class("bg-indigo-700 text-white"),
+				Tr(
+					sortHeader("Name", "name", q),
+					sortHeader("Price", "price", q),
+					sortHeader("Stock", "quantity", q),
+					sortHeader("Last Updated", "updated", q),
+					Th(Class("px-4 py-2 text-left"), Text("")),
+				),
+			),
+			TBody(
+				If(len(page.Items) == 0,
+					Tr(Td(ColSpan("5"), Class("px-4 py-6 text-center text-gray-500"), Text("No items found."))),
+				),
+				Map(page.Items, func(it model.Item) Node {
+					return Tr(
+						Td(Class("px-4 py-2 border-t capitalize"),
+							Text(it.Name),
+							If(it.Category != "", Span(Class("ml-2 text-xs text-gray-500 normal-case"), Text(categoryNames[it.Category]))),
+						),
+						Td(Class("px-4 py-2 border-t"), Text(FormatTZS(it.Price))),
+						QuantityCell(it),
+						Td(Class("px-4 py-2 border-t text-sm text-gray-500"), Text(formatTimestamp(it.UpdatedAt))),
+						Td(Class("px-4 py-2 border-t text-right"),
+							A(Href("/items/"+it.ID+"/edit"), Class("text-indigo-600 hover:underline text-sm"), Text("Edit")),
+						),
+					)
+				}),
+			),
+		),
+		pagination(page, q),
+	)
+}
+
+// categoryNames maps category IDs to names.
+func categoryNames(categories []model.Category) map[string]string {
+	names := map[string]string{}
+	for _, c := range categories {
+		names[c.ID] = c.Name
+	}
+	return names
+}
+
+// QuantityCell shows an item's stock, highlighted once it is low; clicking
+// it loads [QuantityEditor] in its place.
+func QuantityCell(it model.Item) Node {
+	return Td(
+		ID("qty-"+it.ID),
+		Attr("hx-get", "/items/"+it.ID+"/quantity"),
+		Attr("hx-trigger", "click"),
+		Attr("hx-swap", "outerHTML"),
+		Title("Click to edit"),
+		Classes{
+			"px-4 py-2 border-t cursor-pointer hover:bg-indigo-50": true,
+			"text-red-600 font-semibold":                           it.Quantity <= 0,
+			"text-yellow-700 font-semibold":                        it.Quantity > 0 && it.Quantity <= analytics.LowStockAt(it),
+		},
+		Textf("%d", it.Quantity),
+	)
+}
+
+// QuantityEditor is the inline form that replaces a [QuantityCell].
+func QuantityEditor(it model.Item, errorMsg string) Node {
+	return Td(
+		ID("qty-"+it.ID),
+		Class("px-4 py-2 border-t"),
+		Form(
+			Attr("hx-post", "/items/"+it.ID+"/quantity"),
+			Attr("hx-target", "#qty-"+it.ID),
+			Attr("hx-swap", "outerHTML"),
+			Class("flex items-center gap-2"),
+			Input(Type("number"), Name("quantity"), Value(strconv.Itoa(it.Quantity)), Min("0"), AutoFocus(),
+				Class("w-24 border border-gray-300 p-1 rounded"),
+			),
+			Button(Type("submit"), Class("text-sm bg-green-600 text-white px-2 py-1 rounded"), Text("Save")),
+			If(errorMsg != "", Span(Class("text-sm text-red-600"), Text(errorMsg))),
+		),
+	)
+}
+
+// sortHeader renders a column header that sorts by field, toggling the
+// direction when the list is already sorted by it.
+func sortHeader(label, field string, q model.ItemQuery) Node {
+	next := field
+	arrow := ""
+	switch q.Sort {
+	case field:
+		next = "-" + field
+		arrow = " ▲"
+	case "-" + field:
+		arrow = " ▼"
+	}
+
+	nq := q
+	nq.Sort = next
+	nq.Page = 1
+	return Th(Class("px-4 py-2 text-left"),
+		A(Href("/items?"+itemQueryValues(nq)), Class("hover:underline"), Text(label+arrow)),
+	)
+}
+
+// pagination renders previous/next links and the page position.
+func pagination(page model.ItemPage, q model.ItemQuery) Node {
+	if page.TotalPages <= 1 {
+		return nil
+	}
+
+	link := func(p int, label string, enabled bool) Node {
+		if !enabled {
+			return Span(Class("px-3 py-1 rounded border border-gray-200 text-gray-400"), Text(label))
+		}
+		nq := q
+		nq.Page = p
+		return A(Href("/items?"+itemQueryValues(nq)), Class("px-3 py-1 rounded border border-gray-300 hover:bg-gray-100"), Text(label))
+	}
+
+	return Div(Class("flex items-center justify-between text-sm text-gray-600"),
+		Span(Textf("Page %d of %d · %d items", page.Page, page.TotalPages, page.TotalItems)),
+		Div(Class("flex gap-2"),
+			link(page.Page-1, "← Previous", page.Page > 1),
+			link(page.Page+1, "Next →", page.Page < page.TotalPages),
+		),
+	)
+}
+
+// itemQueryValues encodes an item query as URL query parameters.
+func itemQueryValues(q model.ItemQuery) string {
+	v := url.Values{}
+	if s := strings.TrimSpace(q.Search); s != "" {
+		v.Set("q", s)
+	}
+	if q.Sort != "" {
+		v.Set("sort", q.Sort)
+	}
+	if q.Stock != "" {
+		v.Set("stock", q.Stock)
+	}
+	if q.Page > 1 {
+		v.Set("page", fmt.Sprint(q.Page))
+	}
+	return v.Encode()
+}
@@ -0,0 +1,171 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/kitchen"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// KitchenPage renders /kitchen, the kitchen display. Open tickets are shown
+// as cards coloured by age, and the board reloads whenever the server
+// pushes a ticket change over /kitchen/stream.
+//
+// Parameters:
+//   - station: the station shown, or empty for every station.
+//   - tickets: open tickets, oldest first.
+//   - now: the time ticket ages are measured against.
+//   - errorMsg: optional error message to display on the board.
+func KitchenPage(station string, tickets []model.Ticket, now time.Time, errorMsg string) Node {
+	return Layout("/kitchen", true,
+		Div(
+			Class("max-w-7xl mx-auto mt-8 mb-12 px-4 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Kitchen")),
+				Div(Class("flex gap-2 text-sm"),
+					stationTab("", station),
+					Map(model.Stations, func(s string) Node { return stationTab(s, station) }),
+				),
+			),
+
+			KitchenBoard(station, tickets, now, errorMsg),
+
+			// Any pushed change reloads the board; the server also sends a
+			// message every so often so ticket ages stay current
+			liveUpdates("/kitchen/stream?station="+station, "kitchen"),
+		),
+	)
+}
+
+// KitchenBoard renders the ticket cards of the kitchen display. It replaces
+// itself when the page receives a kitchen event.
+func KitchenBoard(station string, tickets []model.Ticket, now time.Time, errorMsg string) Node {
+	return Div(
+		ID("board"),
+		Attr("hx-get", "/kitchen/board?station="+station),
+		Attr("hx-trigger", "kitchen from:body"),
+		Attr("hx-swap", "outerHTML"),
+		Class("grid gap-4 sm:grid-cols-2 lg:grid-cols-3 xl:grid-cols-4"),
+
+		If(errorMsg != "",
+			Div(Class("col-span-full bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+		),
+		If(len(tickets) == 0 && errorMsg == "", P(Class("text-gray-500"), Text("No open tickets."))),
+		Map(tickets, func(t model.Ticket) Node { return ticketCard(t, station, now) }),
+	)
+}
+
+// ticketCard renders one ticket with its lines and a bump button that moves
+// it to the next status.
+func ticketCard(t model.Ticket, station string, now time.Time) Node {
+	urgency := kitchen.Urgency(t, now)
+	next, _ := kitchen.Next(t.Status)
+
+	return Div(
+		Classes{
+			"bg-white rounded-lg shadow border-t-8 p-4 flex flex-col gap-3": true,
+			"border-green-500":  urgency == kitchen.OnTime,
+			"border-yellow-400": urgency == kitchen.Late,
+			"border-red-600":    urgency == kitchen.Overdue,
+		},
+		Div(Class("flex items-center justify-between"),
+			A(Href("/orders/"+t.Order), Class("font-bold text-gray-800 hover:underline"), Text("#"+shortID(t.Order))),
+			Span(
+				Classes{
+					"font-mono text-sm":          true,
+					"text-red-700 font-semibold": urgency == kitchen.Overdue,
+				},
+				Text(formatElapsed(kitchen.Elapsed(t, now))),
+			),
+		),
+		Div(Class("flex items-center gap-2 text-xs uppercase tracking-wide text-gray-500"),
+			Span(Text(stationLabel(t.Station))),
+			Span(Text("·")),
+			Span(Text(t.Status)),
+			If(t.Round > 1, Group{Span(Text("·")), Span(Textf("round %d", t.Round))}),
+		),
+		Ul(Class("space-y-2 flex-grow"),
+			Map(t.Lines, func(l model.Item) Node {
+				return Li(
+					Div(Class("font-semibold text-gray-800"), Textf("%d × %s", l.Quantity, l.Name)),
+					OrderLineDetails(l),
+				)
+			}),
+		),
+		Button(
+			Type("button"),
+			Attr("hx-post", "/kitchen/tickets/"+t.ID+"/bump"),
+			Attr("hx-vals", fmt.Sprintf(`{"station": %q}`, station)),
+			Attr("hx-target", "#board"),
+			Attr("hx-swap", "outerHTML"),
+			Classes{
+				"w-full py-3 rounded font-semibold text-white": true,
+				"bg-indigo-600 hover:bg-indigo-700":            next != model.TicketServed,
+				"bg-green-600 hover:bg-green-700":              next == model.TicketServed,
+			},
+			Text(bumpLabel(next)),
+		),
+	)
+}
+
+// stationTab links to the kitchen display of one station, or of all
+// stations if station is empty.
+func stationTab(station, current string) Node {
+	label := "All"
+	href := "/kitchen"
+	if station != "" {
+		label = stationLabel(station)
+		href += "?station=" + station
+	}
+	return A(Href(href),
+		Classes{
+			"px-3 py-1 rounded-full border":                   true,
+			"bg-indigo-600 text-white border-indigo-600":      station == current,
+			"border-gray-300 text-gray-700 hover:bg-gray-100": station != current,
+		},
+		Text(label),
+	)
+}
+
+// stationLabel turns e.g. "bar" into "Bar".
+func stationLabel(station string) string {
+	if station == "" {
+		return station
+	}
+	return strings.ToUpper(station[:1]) + station[1:]
+}
+
+// bumpLabel names the action of moving a ticket to the next status.
+func bumpLabel(next string) string {
+	switch next {
+	case model.TicketPreparing:
+		return "Start"
+	case model.TicketReady:
+		return "Ready"
+	case model.TicketServed:
+		return "Served"
+	default:
+		return "Bump"
+	}
+}
+
+// formatElapsed formats a ticket age as minutes and seconds.
+func formatElapsed(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// shortID returns the last six characters of a record ID, enough to tell
+// tickets apart on screen.
+func shortID(id string) string {
+	if len(id) <= 6 {
+		return id
+	}
+	return id[len(id)-6:]
+}
@@ -0,0 +1,111 @@
+package html
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/lot"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// ExpiringPage renders /lots/expiring: lots of perishable items that expire
+// within the next days, and those already expired, which can be written off
+// as waste one by one or all at once.
+//
+// Parameters:
+//   - lots: the lots, first-expiry-first-out, with their items expanded.
+//   - days: how many days ahead the report looks.
+//   - now: the current time, to tell expired lots apart.
+//   - errorMsg: optional error message to display above the report.
+func ExpiringPage(lots []model.Lot, days int, now time.Time, errorMsg string) Node {
+	expired := 0
+	for _, l := range lots {
+		if lot.Expired(l, now) {
+			expired++
+		}
+	}
+
+	return Layout("/lots/expiring", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Expiring Soon")),
+				Div(Class("flex items-center gap-2 print:hidden"),
+					printButton(),
+					Form(Method("GET"), Action("/lots/expiring"), Class("flex items-center gap-2"),
+						Label(For("days"), Class("text-sm text-gray-600"), Text("Days ahead")),
+						Input(Type("number"), ID("days"), Name("days"), Value(fmt.Sprint(days)), Min("0"), Class("w-20 border border-gray-300 rounded p-2")),
+						Button(Type("submit"), Class("bg-indigo-600 text-white px-3 py-2 rounded text-sm hover:bg-indigo-700"), Text("Show")),
+					),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			If(expired > 0,
+				Form(Method("POST"), Action("/lots/write-off-expired"),
+					Attr("onsubmit", "return confirm('Write off every expired lot as waste?')"),
+					Class("bg-red-50 border border-red-300 text-red-800 px-4 py-3 rounded flex items-center justify-between gap-4 print:hidden"),
+					Span(Textf("%d lots are past their expiry date.", expired)),
+					Button(Type("submit"), Class("px-3 py-2 rounded bg-red-600 text-white text-sm hover:bg-red-700 whitespace-nowrap"), Text("Write off all expired")),
+				),
+			),
+
+			If(len(lots) == 0 && errorMsg == "",
+				P(Class("text-gray-500"), Textf("Nothing expires in the next %d days.", days)),
+			),
+			If(len(lots) > 0, lotTable(lots, now, true, "/lots/expiring")),
+		),
+	)
+}
+
+// lotTable lists lots with their expiry and a write-off button. withItem
+// adds a column with the item's name; back is where a write-off returns to.
+func lotTable(lots []model.Lot, now time.Time, withItem bool, back string) Node {
+	return Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+		THead(Class("bg-gray-100 text-gray-700"),
+			Tr(
+				If(withItem, Th(Class("px-4 py-2 text-left"), Text("Item"))),
+				Th(Class("px-4 py-2 text-left"), Text("Expires")),
+				Th(Class("px-4 py-2 text-left"), Text("Received")),
+				Th(Class("px-4 py-2 text-right"), Text("Left")),
+				Th(Class("px-4 py-2 print:hidden")),
+			),
+		),
+		TBody(
+			Map(lots, func(l model.Lot) Node {
+				gone := lot.Expired(l, now)
+				expiry := lot.Expiry(l)
+				if expiry == "" {
+					expiry = "—"
+				}
+				return Tr(
+					Classes{"bg-red-50": gone},
+					If(withItem, Td(Class("px-4 py-2 border-t capitalize"),
+						A(Href("/items/"+l.Item+"/stock"), Class("hover:underline"), Text(l.Expand.Item.Name)),
+					)),
+					Td(Class("px-4 py-2 border-t"),
+						Text(expiry),
+						If(gone, Span(Class("ml-2 text-xs font-semibold text-red-700"), Text("EXPIRED"))),
+					),
+					Td(Class("px-4 py-2 border-t"), Text(formatTimestamp(l.Received))),
+					Td(Class("px-4 py-2 border-t text-right"), Text(fmt.Sprint(l.Quantity))),
+					Td(Class("px-4 py-2 border-t text-right print:hidden"),
+						Form(Method("POST"), Action("/lots/"+l.ID+"/write-off"),
+							Attr("onsubmit", "return confirm('Write off what is left of this lot as waste?')"),
+							Input(Type("hidden"), Name("back"), Value(back)),
+							Button(Type("submit"), Class("text-sm border border-red-400 text-red-700 px-3 py-1 rounded hover:bg-red-50"), Text("Write off")),
+						),
+					),
+				)
+			}),
+		),
+	)
+}
@@ -0,0 +1,109 @@
+package html
+
+import (
+	"fmt"
+
+	"github.com/rustacean-dev/possystem/internal/margin"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// MarginReportPage renders /reports/margin: revenue, cost and gross margin
+// of the orders in a date range, by item, category and day.
+//
+// Parameters:
+//   - r: the margin report of the orders in the range.
+//   - filter: the date range, as from/to dates.
+//   - errorMsg: optional error message to display above the report.
+func MarginReportPage(r margin.Report, filter model.OrderFilter, errorMsg string) Node {
+	return Layout("/reports/margin", true,
+		Div(
+			ID("main"),
+			Class("max-w-5xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Gross Margin")),
+				Div(Class("flex gap-2 print:hidden"),
+					printButton(),
+					reportLink("/reports/x", "X-Report"),
+				),
+			),
+
+			Form(
+				Method("GET"),
+				Action("/reports/margin"),
+				Class("flex flex-wrap items-end gap-4 print:hidden"),
+				Div(
+					Label(For("from"), Class("block text-sm text-gray-600 mb-1"), Text("From")),
+					Input(Type("date"), ID("from"), Name("from"), Value(filter.From), Class("border border-gray-300 p-2 rounded")),
+				),
+				Div(
+					Label(For("to"), Class("block text-sm text-gray-600 mb-1"), Text("To")),
+					Input(Type("date"), ID("to"), Name("to"), Value(filter.To), Class("border border-gray-300 p-2 rounded")),
+				),
+				Button(Type("submit"), Class("bg-indigo-600 text-white px-4 py-2 rounded hover:bg-indigo-700 transition"), Text("Show")),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Div(Class("grid grid-cols-2 sm:grid-cols-4 gap-4"),
+				statCard("Revenue", FormatTZS(r.Total.Revenue)),
+				statCard("Cost", FormatTZS(r.Total.Cost)),
+				statCard("Gross margin", FormatTZS(r.Total.Margin())),
+				statCard("Margin %", formatPercent(r.Total.Percent())),
+			),
+
+			P(Class("text-sm text-gray-500"),
+				Text("Revenue is taken from line prices before order discounts; cancelled and refunded orders are left out. Items without a cost show a 100% margin."),
+			),
+
+			marginTable("By item", r.ByItem),
+			marginTable("By category", r.ByCategory),
+			marginTable("By day", r.ByDay),
+		),
+	)
+}
+
+// marginTable lists margin rows; rows that lose money are highlighted.
+func marginTable(title string, rows []margin.Row) Node {
+	return Div(
+		H3(Class("text-lg font-semibold text-gray-700 mb-2"), Text(title)),
+		If(len(rows) == 0, P(Class("text-gray-500"), Text("No sales in this period."))),
+		If(len(rows) > 0,
+			Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+				THead(Class("bg-gray-100 text-gray-700"),
+					Tr(
+						Th(Class("px-4 py-2 text-left"), Text("Name")),
+						Th(Class("px-4 py-2 text-right"), Text("Qty")),
+						Th(Class("px-4 py-2 text-right"), Text("Revenue")),
+						Th(Class("px-4 py-2 text-right"), Text("Cost")),
+						Th(Class("px-4 py-2 text-right"), Text("Margin")),
+						Th(Class("px-4 py-2 text-right"), Text("%")),
+					),
+				),
+				TBody(
+					Map(rows, func(row margin.Row) Node {
+						return Tr(
+							Classes{"bg-red-50": row.Margin() < 0},
+							Td(Class("px-4 py-2 border-t capitalize"), Text(row.Name)),
+							Td(Class("px-4 py-2 border-t text-right"), Text(fmt.Sprint(row.Quantity))),
+							Td(Class("px-4 py-2 border-t text-right"), Text(FormatTZS(row.Revenue))),
+							Td(Class("px-4 py-2 border-t text-right"), Text(FormatTZS(row.Cost))),
+							Td(Class("px-4 py-2 border-t text-right font-semibold"), Text(FormatTZS(row.Margin()))),
+							Td(Class("px-4 py-2 border-t text-right"), Text(formatPercent(row.Percent()))),
+						)
+					}),
+				),
+			),
+		),
+	)
+}
+
+// formatPercent formats a percentage with one decimal, e.g. "62.5%".
+func formatPercent(p float64) string {
+	return fmt.Sprintf("%.1f%%", p)
+}
@@ -0,0 +1,122 @@
+package html
+
+import (
+	"fmt"
+
+	"github.com/rustacean-dev/possystem/internal/modifier"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// ModifierGroupsPage renders the /modifiers admin page.
+// A modifier group is a set of options such as sizes or extras, each with a
+// price delta, and limits on how many can be chosen. Groups are attached to
+// items on the item edit page.
+//
+// Parameters:
+//   - groups: all modifier groups by name.
+//   - errorMsg: optional error message to display above the list.
+func ModifierGroupsPage(groups []model.ModifierGroup, errorMsg string) Node {
+	return Layout("/modifiers", true,
+		Div(
+			ID("main"),
+			Class("max-w-3xl mx-auto mt-12 mb-12 space-y-6"),
+
+			H2(Class("text-2xl font-bold text-gray-800"), Text("Modifier Groups")),
+			P(Class("text-gray-600"),
+				Text("Options are entered one per line, with an optional price change after a comma, e.g. "),
+				Code(Text("Oat milk, 500")), Text(" or "), Code(Text("Small, -500")), Text("."),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Div(Class("space-y-4"),
+				If(len(groups) == 0, P(Class("text-gray-500"), Text("No modifier groups yet."))),
+				Map(groups, func(g model.ModifierGroup) Node {
+					return Div(Class("bg-white border border-gray-200 rounded-lg p-4 space-y-3"),
+						Form(
+							Method("POST"),
+							Action("/modifiers/"+g.ID),
+							Class("space-y-3"),
+							modifierGroupFields(g),
+							Button(Type("submit"), Class("text-sm bg-indigo-600 text-white px-3 py-2 rounded hover:bg-indigo-700"), Text("Save")),
+						),
+						Form(
+							Method("POST"),
+							Action("/modifiers/"+g.ID+"/delete"),
+							Attr("onsubmit", "return confirm('Delete this modifier group?')"),
+							Button(Type("submit"), Class("text-sm border border-red-400 text-red-700 px-3 py-2 rounded hover:bg-red-50"), Text("Delete")),
+						),
+					)
+				}),
+			),
+
+			H3(Class("text-lg font-semibold text-gray-700"), Text("Add Modifier Group")),
+			Form(
+				Method("POST"),
+				Action("/modifiers"),
+				Class("bg-white border border-gray-200 rounded-lg p-4 space-y-3"),
+				modifierGroupFields(model.ModifierGroup{}),
+				Button(Type("submit"), Class("text-sm bg-green-600 text-white px-3 py-2 rounded hover:bg-green-700"), Text("Add")),
+			),
+		),
+	)
+}
+
+// modifierGroupFields are the inputs shared by the add and edit forms.
+func modifierGroupFields(g model.ModifierGroup) Node {
+	return Group([]Node{
+		Div(Class("flex flex-wrap items-center gap-3"),
+			Input(Type("text"), Name("name"), Value(g.Name), Placeholder("Name, e.g. Size"), Required(),
+				Class("flex-grow border border-gray-300 rounded p-2"),
+			),
+			Label(Class("flex items-center gap-1 text-sm text-gray-700"),
+				Input(Type("checkbox"), Name("required"), Value("true"), If(g.Required, Checked())),
+				Text("Required"),
+			),
+			Label(Class("flex items-center gap-1 text-sm text-gray-700"),
+				Text("Min"),
+				Input(Type("number"), Name("min_select"), Min("0"), Value(fmt.Sprint(g.MinSelect)),
+					Class("w-16 border border-gray-300 rounded p-2"),
+				),
+			),
+			Label(Class("flex items-center gap-1 text-sm text-gray-700"), Title("0 means no limit"),
+				Text("Max"),
+				Input(Type("number"), Name("max_select"), Min("0"), Value(fmt.Sprint(g.MaxSelect)),
+					Class("w-16 border border-gray-300 rounded p-2"),
+				),
+			),
+		),
+		Textarea(Name("options"), Rows("4"), Placeholder("Small, -500\nMedium\nLarge, 1000"), Required(),
+			Class("w-full border border-gray-300 rounded p-2 font-mono text-sm"),
+			Text(modifier.FormatOptions(g.Options)),
+		),
+	})
+}
+
+// modifierGroupChecks renders the modifier group checkboxes of the item edit form.
+func modifierGroupChecks(groups []model.ModifierGroup, selected []string) Node {
+	on := map[string]bool{}
+	for _, id := range selected {
+		on[id] = true
+	}
+
+	return Div(
+		Span(Class("block font-medium text-gray-700 mb-1"), Text("Modifier groups")),
+		If(len(groups) == 0, P(Class("text-sm text-gray-500"), Text("No modifier groups yet."))),
+		Div(Class("flex flex-wrap gap-4"),
+			Map(groups, func(g model.ModifierGroup) Node {
+				return Label(Class("flex items-center gap-2 text-gray-700"),
+					Input(Type("checkbox"), Name("modifier_groups"), Value(g.ID), If(on[g.ID], Checked())),
+					Text(g.Name),
+				)
+			}),
+		),
+		P(Class("mt-1 text-sm text-gray-500"),
+			A(Href("/modifiers"), Class("text-indigo-600 hover:underline"), Text("Manage modifier groups")),
+		),
+	)
+}
@@ -3,10 +3,13 @@ package html
 import (
 	"fmt"
 	"math"
+	"net/url"
 
 	"github.com/dustin/go-humanize"
+	"github.com/rustacean-dev/possystem/internal/ordertype"
 	"github.com/rustacean-dev/possystem/model"
 	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
 	. "maragu.dev/gomponents/html"
 )
 
@@ -17,14 +20,28 @@ import (
 // - Order creation date and time
 // - Total cost (formatted in TSh)
 // - Order status (e.g., pending, completed)
+//
+// Tabs above the table switch between order types. The filter form
+// narrows the list by date range and status; the export links carry the
+// same filter so spreadsheets match what is on screen.
 
-func OrderHistoryPage(orders []model.Order) Node {
+func OrderHistoryPage(orders []model.Order, filter model.OrderFilter) Node {
 	return Layout("/orders", true,
 		Div(
 			ID("main"),
 			Class("max-w-6xl mx-auto mt-12"),
 
-			H2(Class("text-2xl font-bold mb-4 text-gray-800"), Text("Order History")),
+			Div(Class("flex items-center justify-between mb-4"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Order History")),
+				Div(Class("flex gap-2"),
+					reportLink("/orders/export.csv?"+orderFilterQuery(filter), "Export CSV"),
+					reportLink("/orders/export.xlsx?"+orderFilterQuery(filter), "Export Excel"),
+				),
+			),
+
+			orderTypeTabs(filter),
+
+			orderFilterForm(filter),
 
 			Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
 				THead(Class("bg-indigo-700 text-white"),
@@ -33,6 +50,7 @@ func OrderHistoryPage(orders []model.Order) Node {
 						Th(Class("px-4 py-2 text-left"), Text("Customer")),
 						Th(Class("px-4 py-2 text-left"), Text("Date")),
 						Th(Class("px-4 py-2 text-left"), Text("Time")),
+						Th(Class("px-4 py-2 text-left"), Text("Type")),
 						Th(Class("px-4 py-2 text-left"), Text("Total")),
 						Th(Class("px-4 py-2 text-left"), Text("Status")),
 					),
@@ -63,12 +81,15 @@ func OrderHistoryPage(orders []model.Order) Node {
 							}
 
 							rows = append(rows, Tr(
-								Td(Class("px-4 py-2 border-t"), Text(o.ID)),
+								Td(Class("px-4 py-2 border-t"),
+									A(Href("/orders/"+o.ID), Class("text-indigo-600 hover:underline"), Text(o.ID)),
+								),
 								Td(Class("px-4 py-2 border-t"), Text(customer)),
 								Td(Class("px-4 py-2 border-t"), Text(date)),
 								Td(Class("px-4 py-2 border-t"), Text(time)),
+								Td(Class("px-4 py-2 border-t"), Text(ordertype.Label(o.Type))),
 								Td(Class("px-4 py-2 border-t"), Text(FormatTZS(o.TotalCost))),
-								Td(Class("px-4 py-2 border-t capitalize"), Text(o.Status)),
+								Td(Class("px-4 py-2 border-t"), Text(ordertype.StepLabel(o.Type, o.Status))),
 							))
 						}
 						return rows
@@ -79,6 +100,82 @@ func OrderHistoryPage(orders []model.Order) Node {
 	)
 }
 
+// orderTypeTabs switches the order history between order types, keeping
+// the rest of the filter.
+func orderTypeTabs(filter model.OrderFilter) Node {
+	types := append([]string{"", ordertype.Counter}, ordertype.Types...)
+
+	return Div(Class("flex gap-2 mb-4 border-b border-gray-200"),
+		Map(types, func(t string) Node {
+			f := filter
+			f.Type = t
+			label := "All"
+			if t != "" {
+				label = ordertype.Label(t)
+			}
+			return A(Href("/orders?"+orderFilterQuery(f)),
+				Classes{
+					"px-4 py-2 -mb-px border-b-2 font-medium":              true,
+					"border-indigo-600 text-indigo-700":                    t == filter.Type,
+					"border-transparent text-gray-600 hover:text-gray-800": t != filter.Type,
+				},
+				Text(label),
+			)
+		}),
+	)
+}
+
+// orderFilterForm renders the date range and status filter above the order
+// table. The order type is kept in a hidden field.
+func orderFilterForm(filter model.OrderFilter) Node {
+	statusOpts := []Node{Option(Value(""), Text("All statuses"))}
+	for _, st := range model.OrderStatuses {
+		statusOpts = append(statusOpts, Option(Value(st), Text(ordertype.StepLabel("", st)), If(st == filter.Status, Selected())))
+	}
+
+	return Form(
+		Method("GET"),
+		Action("/orders"),
+		Class("flex flex-wrap items-end gap-4 mb-6"),
+		If(filter.Type != "", Input(Type("hidden"), Name("type"), Value(filter.Type))),
+
+		Div(
+			Label(For("from"), Class("block text-sm text-gray-600 mb-1"), Text("From")),
+			Input(Type("date"), ID("from"), Name("from"), Value(filter.From), Class("border border-gray-300 p-2 rounded")),
+		),
+		Div(
+			Label(For("to"), Class("block text-sm text-gray-600 mb-1"), Text("To")),
+			Input(Type("date"), ID("to"), Name("to"), Value(filter.To), Class("border border-gray-300 p-2 rounded")),
+		),
+		Div(
+			Label(For("status"), Class("block text-sm text-gray-600 mb-1"), Text("Status")),
+			Select(append([]Node{ID("status"), Name("status"), Class("border border-gray-300 p-2 rounded")}, statusOpts...)...),
+		),
+		Button(Type("submit"),
+			Class("bg-indigo-600 text-white px-4 py-2 rounded hover:bg-indigo-700 transition"),
+			Text("Filter"),
+		),
+	)
+}
+
+// orderFilterQuery encodes an order filter as URL query parameters.
+func orderFilterQuery(filter model.OrderFilter) string {
+	q := url.Values{}
+	if filter.From != "" {
+		q.Set("from", filter.From)
+	}
+	if filter.To != "" {
+		q.Set("to", filter.To)
+	}
+	if filter.Status != "" {
+		q.Set("status", filter.Status)
+	}
+	if filter.Type != "" {
+		q.Set("type", filter.Type)
+	}
+	return q.Encode()
+}
+
 func CreateOrderForm(errorMsg string, items []model.Item) Node {
 	// Build <option> nodes with item IDs and display prices
 	opts := []Node{}
@@ -139,6 +236,19 @@ func CreateOrderForm(errorMsg string, items []model.Item) Node {
 					),
 				),
 
+				// Tender type, used for the by-tender breakdown on X/Z-reports
+				Div(
+					Label(For("tender"), Class("block mb-1 font-medium text-gray-700"), Text("Payment")),
+					Select(
+						Name("tender"),
+						ID("tender"),
+						Class("w-full border border-gray-300 p-2 rounded"),
+						Option(Value(model.TenderCash), Text("Cash")),
+						Option(Value(model.TenderCard), Text("Card")),
+						Option(Value(model.TenderMobile), Text("Mobile Money")),
+					),
+				),
+
 				//  Live total cost display (HTMX target)
 				Div(
 					ID("total-display"),
@@ -0,0 +1,178 @@
+package html
+
+import (
+	"slices"
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/internal/dispatch"
+	"github.com/rustacean-dev/possystem/internal/ordertype"
+	"github.com/rustacean-dev/possystem/internal/report"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// OrderDetailPage renders /orders/{id}: every line of an order with its
+// chosen options and notes, and the order totals. It doubles as the
+// customer receipt when printed. Below the receipt the order can be moved
+// to the next step of its type, or to any status its type allows;
+// deliveries go out and come back through the dispatch board.
+func OrderDetailPage(o model.Order, errorMsg string) Node {
+	subtotal := compute.Subtotal(o.Items)
+	next, hasNext := ordertype.Next(o)
+	// Only tabs are named after the customer; takeaways and deliveries are for one
+	customerLabel := "Tab"
+	if o.Type == model.OrderTakeaway || o.Type == model.OrderDelivery {
+		customerLabel = "Customer"
+	}
+
+	return Layout("/orders", true,
+		Div(
+			ID("main"),
+			Class("max-w-2xl mx-auto mt-12 mb-12 space-y-6 print:mt-0 print:max-w-xs print:text-sm"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Receipt")),
+				Div(Class("flex gap-2 print:hidden"),
+					printButton(),
+					reportLink("/orders", "All Orders"),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded print:hidden"), Text(errorMsg)),
+			),
+
+			If(o.Status == model.OrderStatusOpen,
+				Div(Class("bg-amber-50 border border-amber-200 text-amber-800 px-4 py-3 rounded print:hidden"),
+					Text("This order is an open tab. "),
+					A(Href("/tabs/"+o.ID), Class("font-semibold text-indigo-700 hover:underline"), Text("Add a round or close it")),
+				),
+			),
+
+			Dl(Class("grid grid-cols-2 gap-x-4 gap-y-1 text-gray-700"),
+				Dt(Text("Order")), Dd(Class("text-right font-mono"), Text(o.ID)),
+				If(o.Customer != "", Group{Dt(Text(customerLabel)), Dd(Class("text-right"), Text(o.Customer))}),
+				Dt(Text("Date")), Dd(Class("text-right"), Text(formatTimestamp(o.CreatedAt))),
+				Dt(Text("Cashier")), Dd(Class("text-right"), Text(report.CashierOf(o))),
+				Dt(Text("Type")), Dd(Class("text-right"), Text(ordertype.Label(o.Type))),
+				If(o.PickupAt != "", Group{Dt(Text("Pickup")), Dd(Class("text-right"), Text(formatTimestamp(o.PickupAt)))}),
+				If(o.Address != "", Group{Dt(Text("Deliver to")), Dd(Class("text-right"), Text(o.Address))}),
+				If(o.Phone != "", Group{Dt(Text("Phone")), Dd(Class("text-right"), A(Href("tel:"+o.Phone), Text(o.Phone)))}),
+				If(o.DispatchedAt != "", Group{Dt(Text("Dispatched")), Dd(Class("text-right"), Text(formatTimestamp(o.DispatchedAt)))}),
+				If(o.DeliveredAt != "", Group{
+					Dt(Text("Delivered")),
+					Dd(Class("text-right"), Textf("%s (%s)", formatTimestamp(o.DeliveredAt), formatSeated(dispatch.Duration(o, time.Time{})))),
+				}),
+				Dt(Text("Status")), Dd(Class("text-right"), Text(ordertype.StepLabel(o.Type, o.Status))),
+				If(o.Tender != "" && o.Tender != model.TenderSplit, Group{Dt(Text("Paid by")), Dd(Class("text-right capitalize"), Text(o.Tender))}),
+				If(o.Tender == model.TenderSplit, Group{
+					Dt(Text("Paid by")),
+					Dd(Class("text-right"), A(Href("/orders/"+o.ID+"/split"), Class("text-indigo-600 hover:underline"), Text("Split bill"))),
+				}),
+			),
+
+			Table(Class("w-full bg-white border border-gray-200 rounded-md overflow-hidden print:border-0"),
+				THead(Class("bg-gray-100 text-gray-700 text-left"),
+					Tr(
+						Th(Class("px-3 py-2"), Text("Item")),
+						Th(Class("px-3 py-2 text-right"), Text("Qty")),
+						Th(Class("px-3 py-2 text-right"), Text("Amount")),
+					),
+				),
+				TBody(
+					Map(o.Items, func(line model.Item) Node {
+						return Tr(Class("border-t align-top"),
+							Td(Class("px-3 py-2"),
+								P(Class("font-medium capitalize"), Text(line.Name)),
+								OrderLineDetails(line),
+							),
+							Td(Class("px-3 py-2 text-right"), Textf("%d × %s", line.Quantity, FormatTZS(compute.UnitPrice(line)))),
+							Td(Class("px-3 py-2 text-right"), Text(FormatTZS(compute.LineTotal(line)))),
+						)
+					}),
+				),
+			),
+
+			Dl(Class("grid grid-cols-2 gap-x-4 gap-y-1 text-gray-700"),
+				Dt(Text("Subtotal")), Dd(Class("text-right"), Text(FormatTZS(subtotal))),
+				If(o.DeliveryFee != 0, Group{Dt(Text("Delivery fee")), Dd(Class("text-right"), Text(FormatTZS(o.DeliveryFee)))}),
+				Dt(Class("text-lg font-semibold text-gray-800 border-t pt-2"), Text("Total")),
+				Dd(Class("text-lg font-semibold text-gray-800 border-t pt-2 text-right"), Text(FormatTZS(o.TotalCost))),
+			),
+
+			orderSteps(o),
+
+			Div(Class("flex flex-wrap items-center justify-end gap-4 print:hidden"),
+				If(hasNext && dispatch.ViaBoard(o, next),
+					A(Href("/dispatch"), Class("bg-green-600 text-white font-semibold px-4 py-2 rounded hover:bg-green-700"),
+						Text(ordertype.StepLabel(o.Type, next)+" on the dispatch board"),
+					),
+				),
+				If(hasNext && !dispatch.ViaBoard(o, next),
+					Form(
+						Method("POST"),
+						Action("/orders/"+o.ID+"/status"),
+						Input(Type("hidden"), Name("status"), Value(next)),
+						Button(Type("submit"), Class("bg-green-600 text-white font-semibold px-4 py-2 rounded hover:bg-green-700"),
+							Text("Mark "+ordertype.StepLabel(o.Type, next)),
+						),
+					),
+				),
+				Form(
+					Method("POST"),
+					Action("/orders/"+o.ID+"/status"),
+					Class("flex items-center gap-2"),
+					Label(For("status"), Class("text-gray-700"), Text("Status")),
+					Select(ID("status"), Name("status"), Class("border border-gray-300 rounded p-2"),
+						Map(ordertype.Statuses(o), func(s string) Node {
+							return Option(Value(s), Text(ordertype.StepLabel(o.Type, s)), If(s == o.Status, Selected()))
+						}),
+					),
+					Button(Type("submit"), Class("bg-indigo-600 text-white px-4 py-2 rounded hover:bg-indigo-700"), Text("Update")),
+				),
+			),
+		),
+	)
+}
+
+// orderSteps shows the status steps of the order's type, up to and
+// including the current one highlighted. Cancelled and refunded orders
+// and open tabs are off the steps, so nothing is highlighted for them.
+func orderSteps(o model.Order) Node {
+	steps := ordertype.Steps(o.Type)
+	current := slices.Index(steps, o.Status)
+
+	return Ol(Class("flex flex-wrap gap-2 text-sm print:hidden"),
+		Map(indexed(steps), func(i int) Node {
+			return Li(
+				Classes{
+					"px-3 py-1 rounded-full border":            true,
+					"bg-green-600 text-white border-green-600": i <= current,
+					"text-gray-500 border-gray-300 bg-white":   i > current,
+				},
+				Text(ordertype.StepLabel(o.Type, steps[i])),
+			)
+		}),
+	)
+}
+
+// OrderLineDetails lists the chosen options and the note of an order line,
+// e.g. for receipts and kitchen tickets. It renders nothing for plain lines.
+func OrderLineDetails(line model.Item) Node {
+	if len(line.Modifiers) == 0 && line.Note == "" {
+		return nil
+	}
+
+	return Ul(Class("text-sm text-gray-600"),
+		Map(line.Modifiers, func(m model.Modifier) Node {
+			return Li(
+				Text("+ "+m.Name),
+				If(m.PriceDelta != 0, Span(Class("text-gray-400"), Text(" ("+formatDelta(m.PriceDelta)+")"))),
+			)
+		}),
+		If(line.Note != "", Li(Class("italic"), Text("Note: "+line.Note))),
+	)
+}
@@ -0,0 +1,444 @@
+package html
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/internal/modifier"
+	"github.com/rustacean-dev/possystem/internal/ordertype"
+	"github.com/rustacean-dev/possystem/internal/tab"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// POSPage renders the touch-friendly order entry screen at /orders/pos.
+// Category tabs filter a grid of large item tiles; tapping a tile adds one
+// of the item to the cart on the right, which is kept server-side rendered
+// through HTMX and submitted to POST /orders.
+//
+// Parameters:
+//   - categories: tabs, in display order.
+//   - items: all items that can be sold.
+//   - active: ID of the selected category, or empty for all items.
+//   - cart: the current cart lines, with names and prices.
+//   - tender: the selected tender type.
+//   - table: the table the order is for; zero for counter sales.
+//   - open: the open tab the cart is a new round for; zero for a new order.
+//   - details: the order type and its details as entered, with PickupAt
+//     as typed.
+//   - zones: the delivery zones to charge delivery orders by; if there are
+//     none the delivery fee is entered by hand.
+//   - errorMsg: optional error message to display in the cart.
+func POSPage(categories []model.Category, items []model.Item, active string, cart []model.Item, tender string, table model.Table, open model.Order, details model.Order, zones []model.Zone, errorMsg string) Node {
+	return Layout("/orders/pos", true,
+		Div(
+			ID("main"),
+			Class("max-w-7xl mx-auto mt-6 mb-6 px-4 grid lg:grid-cols-3 gap-6"),
+
+			Div(Class("lg:col-span-2 space-y-4"),
+				If(open.ID != "", roundBanner(open, table)),
+				If(open.ID == "" && table.ID != "", tableBanner(table)),
+				scanForm(),
+				Div(ID("scan-result")),
+				POSGrid(categories, items, active),
+			),
+
+			Div(Class("space-y-4 h-fit lg:sticky lg:top-20"),
+				Cart(cart, tender, open.ID, errorMsg),
+				If(open.ID == "", OrderTypePanel(details, table, zones, false)),
+			),
+			Div(ID("modifier-panel")),
+		),
+	)
+}
+
+// tableBanner names the table an order is for. Its hidden input sits
+// outside the cart, which is swapped on every change, and is submitted
+// with the cart form.
+func tableBanner(t model.Table) Node {
+	return Div(Class("bg-indigo-50 border border-indigo-200 text-indigo-800 px-4 py-3 rounded flex items-center justify-between"),
+		Span(Class("font-semibold"), Text("Order for table "+tableLabel(t))),
+		A(Href("/tables/"+t.ID), Class("text-indigo-600 hover:underline"), Text("Back to table")),
+		Input(Type("hidden"), Name("table"), Value(t.ID), Attr("form", "cart-form")),
+	)
+}
+
+// roundBanner names the tab the cart is a new round for. The round is
+// priced for the tab's order type, carried in a hidden input.
+func roundBanner(open model.Order, t model.Table) Node {
+	return Div(Class("bg-amber-50 border border-amber-200 text-amber-800 px-4 py-3 rounded flex items-center justify-between"),
+		Span(Class("font-semibold"), Textf("Round %d for %s", tab.Next(open), tab.Label(open, map[string]model.Table{t.ID: t}))),
+		A(Href("/tabs/"+open.ID), Class("text-indigo-600 hover:underline"), Text("Back to tab")),
+		If(open.Type != "", Input(Type("hidden"), Name("type"), Value(open.Type), Attr("form", "cart-form"))),
+	)
+}
+
+// OrderTypePanel picks how the order is served and asks for the details
+// its type needs. Like the table banner, its fields sit outside the cart
+// and are submitted with the cart form. Picking a type reprices the cart,
+// and the panel comes back out-of-band with the fields of the new type.
+// Orders for a table are always dine-in; dine-in orders are started from
+// the floor plan. Deliveries pick a zone, whose fee is charged, when zones
+// are set up.
+func OrderTypePanel(details model.Order, table model.Table, zones []model.Zone, oob bool) Node {
+	inputClass := "w-full border border-gray-300 rounded p-2"
+	if table.ID != "" {
+		details.Type = model.OrderDineIn
+	}
+
+	return Div(ID("order-type"), If(oob, Attr("hx-swap-oob", "true")), Class("space-y-4"),
+		Div(Class("bg-white border border-gray-200 rounded-xl shadow p-4 space-y-3"),
+			If(table.ID != "", Group{
+				P(Class("font-semibold text-gray-800"), Text("Dine-in at "+tableLabel(table))),
+				Input(Type("hidden"), Name("type"), Value(model.OrderDineIn), Attr("form", "cart-form")),
+			}),
+			If(table.ID == "",
+				Div(Class("flex flex-wrap gap-2"),
+					orderTypeOption("", details.Type),
+					A(Href("/tables"), Class("px-4 py-2 rounded-full border border-gray-300 text-gray-700 hover:bg-gray-100"), Text(ordertype.Label(model.OrderDineIn))),
+					orderTypeOption(model.OrderTakeaway, details.Type),
+					orderTypeOption(model.OrderDelivery, details.Type),
+				),
+			),
+
+			If(details.Type == model.OrderTakeaway || details.Type == model.OrderDelivery,
+				Input(Type("text"), Name("customer"), Value(details.Customer), Attr("form", "cart-form"),
+					If(details.Type == model.OrderTakeaway, Group{Placeholder("Customer name"), Required()}),
+					If(details.Type == model.OrderDelivery, Placeholder("Customer name (optional)")),
+					Class(inputClass),
+				),
+			),
+			If(details.Type == model.OrderTakeaway,
+				Div(Class("flex items-center gap-2"),
+					Label(For("pickup_at"), Class("text-gray-700 whitespace-nowrap"), Text("Pickup at")),
+					Input(Type("time"), ID("pickup_at"), Name("pickup_at"), Value(details.PickupAt), Attr("form", "cart-form"), Required(), Class(inputClass)),
+				),
+			),
+			If(details.Type == model.OrderDelivery, Group{
+				Input(Type("tel"), Name("phone"), Value(details.Phone), Attr("form", "cart-form"), Placeholder("Phone"), Required(), Class(inputClass)),
+				Textarea(Name("address"), Attr("form", "cart-form"), Placeholder("Delivery address"), Rows("2"), Required(), Class(inputClass), Text(details.Address)),
+				If(len(zones) > 0,
+					Select(Name("zone"), Attr("form", "cart-form"), Required(), Class(inputClass),
+						Option(Value(""), Text("Delivery zone")),
+						Map(zones, func(z model.Zone) Node {
+							return Option(Value(z.ID), Text(z.Name+" – "+zoneFee(z)), If(z.ID == details.Zone, Selected()))
+						}),
+					),
+				),
+				If(len(zones) == 0,
+					Div(Class("flex items-center gap-2"),
+						Label(For("delivery_fee"), Class("text-gray-700 whitespace-nowrap"), Text("Delivery fee")),
+						Input(Type("number"), ID("delivery_fee"), Name("delivery_fee"), Value(formatFee(details.DeliveryFee)), Min("0"), Step("1"),
+							Attr("form", "cart-form"), Required(), Class(inputClass),
+						),
+					),
+				),
+				P(Class("text-sm text-gray-500"), Text("The fee is added to the total when the order is placed.")),
+			}),
+		),
+
+		// Only counter and dine-in orders are kept open as tabs
+		If(details.Type == "" || details.Type == model.OrderDineIn, openTabForm()),
+	)
+}
+
+// orderTypeOption is one choice of order type. Picking it posts the cart
+// with type_changed set, so the cart is repriced and the panel redrawn.
+func orderTypeOption(t, active string) Node {
+	return Label(
+		Classes{
+			"px-4 py-2 rounded-full border cursor-pointer": true,
+			"bg-indigo-600 text-white border-indigo-600":   t == active,
+			"text-gray-700 border-gray-300":                t != active,
+		},
+		Input(Type("radio"), Name("type"), Value(t), Class("sr-only"), If(t == active, Checked()),
+			Attr("form", "cart-form"),
+			Attr("hx-post", "/orders/cart"),
+			Attr("hx-vals", jsonVals("type_changed", "1")),
+			Attr("hx-include", "#cart-form"),
+			Attr("hx-target", "#cart"),
+			Attr("hx-swap", "outerHTML"),
+		),
+		Text(ordertype.Label(t)),
+	)
+}
+
+// formatFee shows a delivery fee in its input, blank until one is entered.
+func formatFee(fee float64) string {
+	if fee == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(fee, 'f', -1, 64)
+}
+
+// zoneFee describes the fee of a delivery zone, e.g. "3,000 TZS, free from
+// 50,000 TZS".
+func zoneFee(z model.Zone) string {
+	if z.FreeFrom > 0 {
+		return FormatTZS(z.Fee) + ", free from " + FormatTZS(z.FreeFrom)
+	}
+	return FormatTZS(z.Fee)
+}
+
+// openTabForm keeps the cart open as a tab instead of taking payment now.
+// It sits outside the cart, so the name survives cart updates, and submits
+// the cart form.
+func openTabForm() Node {
+	return Div(Class("bg-white border border-gray-200 rounded-xl shadow p-4 space-y-2"),
+		Input(Type("text"), Name("customer"), Attr("form", "cart-form"), Placeholder("Name on the tab (optional)"),
+			Class("w-full border border-gray-300 rounded p-2"),
+		),
+		Button(Type("submit"), Attr("form", "cart-form"), Name("open_tab"), Value("1"),
+			Class("w-full border-2 border-amber-500 text-amber-700 font-semibold py-3 rounded-lg hover:bg-amber-50"),
+			Text("Open Tab"),
+		),
+	)
+}
+
+// scanForm renders the barcode/SKU input. Scanners type the code and press
+// Enter, which adds the item to the cart and clears the input for the next scan.
+func scanForm() Node {
+	return Form(
+		Attr("hx-post", "/orders/cart/scan"),
+		Attr("hx-include", "#cart-form"),
+		Attr("hx-target", "#cart"),
+		Attr("hx-swap", "outerHTML"),
+		Attr("hx-on::after-request", "this.reset(); this.code.focus()"),
+		Class("flex gap-2"),
+		Input(Type("text"), Name("code"), AutoFocus(), AutoComplete("off"),
+			Placeholder("Scan barcode or type SKU"),
+			Class("flex-1 border border-gray-300 rounded p-3 text-lg focus:ring focus:border-indigo-500"),
+		),
+		Button(Type("submit"),
+			Class("bg-gray-800 text-white px-4 rounded hover:bg-gray-900"),
+			Text("Add"),
+		),
+	)
+}
+
+// ScanResult renders feedback for the last scan. For an unknown code it
+// offers to create an item with that barcode. It is swapped out-of-band
+// alongside the cart; an empty message clears it.
+func ScanResult(code, msg string) Node {
+	return Div(ID("scan-result"), Attr("hx-swap-oob", "true"),
+		If(msg != "",
+			Div(Class("bg-yellow-50 border border-yellow-300 text-yellow-800 px-4 py-3 rounded flex items-center justify-between gap-4"),
+				Span(Text(msg)),
+				If(code != "",
+					A(Href("/items/new?barcode="+url.QueryEscape(code)), Class("text-indigo-600 font-semibold hover:underline whitespace-nowrap"),
+						Textf("Create item with barcode %s", code),
+					),
+				),
+			),
+		),
+	)
+}
+
+// POSGrid renders the category tabs and item tiles. It is swapped as a
+// whole when a tab is selected.
+func POSGrid(categories []model.Category, items []model.Item, active string) Node {
+	colours := map[string]string{}
+	for _, c := range categories {
+		colours[c.ID] = safeColour(c.Colour)
+	}
+
+	var tiles []Node
+	for _, it := range items {
+		if active != "" && it.Category != active {
+			continue
+		}
+		tiles = append(tiles, itemTile(it, colours[it.Category]))
+	}
+
+	return Div(ID("pos-grid"), Class("space-y-4"),
+		Div(Class("flex gap-2 overflow-x-auto pb-2"),
+			categoryTab("", "All", "#4b5563", active == ""),
+			Map(categories, func(c model.Category) Node {
+				return categoryTab(c.ID, c.Name, safeColour(c.Colour), active == c.ID)
+			}),
+		),
+
+		If(len(tiles) == 0, P(Class("text-gray-500 py-12 text-center"), Text("No items in this category."))),
+		Div(Class("grid grid-cols-2 sm:grid-cols-3 xl:grid-cols-4 gap-4"), Group(tiles)),
+	)
+}
+
+// Cart renders the order being rung up. Every line is carried as a hidden
+// JSON "line" field, so the cart lives entirely in the page. With the ID
+// of an open tab the cart is added to it as a new round, to be paid when
+// the tab is closed.
+func Cart(lines []model.Item, tender, tabID, errorMsg string) Node {
+	return Div(ID("cart"), Class("bg-white border border-gray-200 rounded-xl shadow p-4 space-y-4"),
+		H2(Class("text-xl font-semibold text-gray-800"), Text("Current Order")),
+
+		If(errorMsg != "",
+			Div(Class("bg-red-100 border border-red-400 text-red-700 px-3 py-2 rounded text-sm"), Text(errorMsg)),
+		),
+
+		Form(
+			ID("cart-form"),
+			Method("POST"),
+			Action("/orders"),
+			Class("space-y-4"),
+			Input(Type("hidden"), Name("from"), Value("pos")),
+			If(tabID != "", Input(Type("hidden"), Name("tab"), Value(tabID))),
+
+			If(len(lines) == 0, P(Class("text-gray-500"), Text("Tap an item to add it."))),
+			Ul(Class("divide-y"),
+				Group(cartLines(lines)),
+			),
+
+			Div(Class("flex justify-between text-lg font-semibold text-gray-800 border-t pt-3"),
+				Span(Text("Total")),
+				Span(Text(FormatTZS(compute.Subtotal(lines)))),
+			),
+
+			If(tabID == "", tenderSelect(tender)),
+
+			Button(Type("submit"),
+				If(len(lines) == 0, Disabled()),
+				Class("w-full bg-green-600 text-white text-lg font-semibold py-4 rounded-lg hover:bg-green-700 transition disabled:opacity-50"),
+				If(tabID == "", Text("Place Order")),
+				If(tabID != "", Text("Add Round")),
+			),
+		),
+	)
+}
+
+// tenderSelect lets the cashier pick how an order is paid.
+func tenderSelect(tender string) Node {
+	if tender == "" {
+		tender = model.TenderCash
+	}
+	return Select(
+		Name("tender"),
+		Class("w-full border border-gray-300 p-3 rounded text-lg"),
+		Option(Value(model.TenderCash), Text("Cash"), If(tender == model.TenderCash, Selected())),
+		Option(Value(model.TenderCard), Text("Card"), If(tender == model.TenderCard, Selected())),
+		Option(Value(model.TenderMobile), Text("Mobile Money"), If(tender == model.TenderMobile, Selected())),
+	)
+}
+
+func cartLines(lines []model.Item) []Node {
+	var nodes []Node
+	for i, line := range lines {
+		// The note travels in its own editable field, not in the JSON
+		encoded, _ := json.Marshal(model.CartLine{ItemID: line.ID, Quantity: line.Quantity, Modifiers: line.Modifiers})
+		idx := strconv.Itoa(i)
+		nodes = append(nodes, Li(Class("py-2 space-y-1"),
+			Input(Type("hidden"), Name("line"), Value(string(encoded))),
+			Div(Class("flex items-center justify-between gap-2"),
+				Div(
+					P(Class("font-medium capitalize"), Text(line.Name)),
+					If(len(line.Modifiers) > 0, P(Class("text-sm text-gray-600"), Text(modifier.Describe(line.Modifiers)))),
+					P(Class("text-sm text-gray-500"), Text(FormatTZS(compute.UnitPrice(line)))),
+				),
+				Div(Class("flex items-center gap-2"),
+					cartButton("dec", idx, "−"),
+					Span(Class("w-6 text-center"), Text(strconv.Itoa(line.Quantity))),
+					cartButton("inc", idx, "+"),
+				),
+			),
+			Input(Type("text"), Name("line_note"), Value(line.Note), Placeholder("Note, e.g. no onions"),
+				Class("w-full border border-gray-200 rounded px-2 py-1 text-sm"),
+			),
+		))
+	}
+	return nodes
+}
+
+// cartButton posts the cart with one extra action field, e.g. inc=2.
+func cartButton(action, value, label string) Node {
+	return Button(Type("button"),
+		Attr("hx-post", "/orders/cart"),
+		Attr("hx-vals", jsonVals(action, value)),
+		Attr("hx-include", "#cart-form"),
+		Attr("hx-target", "#cart"),
+		Attr("hx-swap", "outerHTML"),
+		Class("w-10 h-10 rounded-full bg-gray-100 text-xl hover:bg-gray-200"),
+		Text(label),
+	)
+}
+
+func itemTile(it model.Item, colour string) Node {
+	if colour == "" {
+		colour = "#9ca3af"
+	}
+	soldOut := it.Quantity <= 0
+
+	// Items with options open the modifier panel instead of adding straight away
+	add := Group{
+		Attr("hx-post", "/orders/cart"),
+		Attr("hx-vals", jsonVals("add", it.ID)),
+		Attr("hx-include", "#cart-form"),
+		Attr("hx-target", "#cart"),
+		Attr("hx-swap", "outerHTML"),
+	}
+	if len(it.ModifierGroups) > 0 {
+		add = Group{
+			Attr("hx-get", "/orders/pos/items/"+it.ID+"/options"),
+			Attr("hx-swap", "none"),
+		}
+	}
+
+	return Button(Type("button"),
+		add,
+		If(soldOut, Disabled()),
+		StyleAttr("border-left-color: "+colour),
+		Classes{
+			"h-32 p-4 rounded-xl bg-white border border-gray-200 border-l-8 shadow text-left flex flex-col justify-between transition active:scale-95": true,
+			"hover:shadow-lg":               !soldOut,
+			"opacity-40 cursor-not-allowed": soldOut,
+		},
+		Span(Class("text-lg font-semibold text-gray-800 capitalize"), Text(it.Name)),
+		Span(Class("flex justify-between text-sm text-gray-600"),
+			Span(Text(FormatTZS(it.Price))),
+			Span(Textf("%d left", it.Quantity)),
+		),
+	)
+}
+
+func categoryTab(id, name, colour string, active bool) Node {
+	if colour == "" {
+		colour = "#4f46e5"
+	}
+	href := "/orders/pos"
+	if id != "" {
+		href += "?category=" + id
+	}
+	return A(Href(href),
+		Attr("hx-get", href),
+		Attr("hx-target", "#pos-grid"),
+		Attr("hx-select", "#pos-grid"),
+		Attr("hx-swap", "outerHTML"),
+		If(active, StyleAttr("background-color: "+colour)),
+		If(!active, StyleAttr("border-color: "+colour)),
+		Classes{
+			"px-5 py-3 rounded-full text-base font-medium whitespace-nowrap border-2": true,
+			"text-white":             active,
+			"text-gray-700 bg-white": !active,
+		},
+		Text(name),
+	)
+}
+
+// jsonVals encodes a single hx-vals key/value pair.
+func jsonVals(key, value string) string {
+	b, _ := json.Marshal(map[string]string{key: value})
+	return string(b)
+}
+
+var hexColour = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// safeColour only lets through "#rrggbb" colours, since they end up in style attributes.
+func safeColour(c string) string {
+	if !hexColour.MatchString(c) {
+		return ""
+	}
+	return c
+}
@@ -0,0 +1,115 @@
+package html
+
+import (
+	"fmt"
+
+	"github.com/rustacean-dev/possystem/internal/modifier"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// ModifierPanel renders the options dialog of the POS screen for an item
+// with modifier groups, e.g. size and extras for a coffee, plus a note for
+// the kitchen. Submitting it adds one of the item to the cart.
+//
+// The panel is always swapped out-of-band into #modifier-panel, so it can be
+// opened from an item tile as well as from a scan.
+func ModifierPanel(item model.Item, groups []model.ModifierGroup) Node {
+	return Div(ID("modifier-panel"), Attr("hx-swap-oob", "true"),
+		Div(Class("fixed inset-0 z-50 bg-black/40 flex items-center justify-center p-4"),
+			Form(
+				Attr("hx-post", "/orders/cart"),
+				Attr("hx-include", "#cart-form"),
+				Attr("hx-target", "#cart"),
+				Attr("hx-swap", "outerHTML"),
+				Class("bg-white rounded-xl shadow-xl w-full max-w-lg max-h-full overflow-y-auto p-6 space-y-5"),
+
+				Input(Type("hidden"), Name("add"), Value(item.ID)),
+
+				Div(Class("flex justify-between items-baseline"),
+					H3(Class("text-xl font-semibold text-gray-800 capitalize"), Text(item.Name)),
+					Span(Class("text-gray-600"), Text(FormatTZS(item.Price))),
+				),
+
+				Map(groups, modifierFieldset),
+
+				Div(
+					Label(For("modifier-note"), Class("block font-medium text-gray-700 mb-1"), Text("Note")),
+					Textarea(ID("modifier-note"), Name("note"), Rows("2"), Placeholder("e.g. no onions"),
+						Class("w-full border border-gray-300 rounded p-2"),
+					),
+				),
+
+				Div(Class("flex gap-3"),
+					Button(Type("button"), Attr("onclick", "document.getElementById('modifier-panel').replaceChildren()"),
+						Class("flex-1 border border-gray-300 py-3 rounded-lg hover:bg-gray-50"),
+						Text("Cancel"),
+					),
+					Button(Type("submit"),
+						Class("flex-1 bg-green-600 text-white font-semibold py-3 rounded-lg hover:bg-green-700"),
+						Text("Add to order"),
+					),
+				),
+			),
+		),
+	)
+}
+
+// ClosedModifierPanel empties the options dialog after its item was added.
+func ClosedModifierPanel() Node {
+	return Div(ID("modifier-panel"), Attr("hx-swap-oob", "true"))
+}
+
+// modifierFieldset renders one group of the options dialog: radio buttons
+// when exactly one option can be chosen, checkboxes otherwise.
+func modifierFieldset(g model.ModifierGroup) Node {
+	least := modifier.Min(g)
+	single := g.MaxSelect == 1
+
+	var hint string
+	switch {
+	case single && least == 1:
+		hint = "Choose 1"
+	case single:
+		hint = "Optional"
+	case g.MaxSelect > 0 && least > 0:
+		hint = fmt.Sprintf("Choose %d to %d", least, g.MaxSelect)
+	case g.MaxSelect > 0:
+		hint = fmt.Sprintf("Up to %d", g.MaxSelect)
+	case least > 0:
+		hint = fmt.Sprintf("At least %d", least)
+	default:
+		hint = "Optional"
+	}
+
+	inputType := "checkbox"
+	if single {
+		inputType = "radio"
+	}
+
+	return FieldSet(Class("space-y-2"),
+		Legend(Class("font-medium text-gray-700"),
+			Text(g.Name), Span(Class("ml-2 text-sm text-gray-500 font-normal"), Text(hint)),
+		),
+		Div(Class("grid grid-cols-2 gap-2"),
+			Map(g.Options, func(o model.ModifierOption) Node {
+				return Label(Class("flex items-center justify-between gap-2 border border-gray-200 rounded-lg p-3 cursor-pointer has-[:checked]:border-indigo-500 has-[:checked]:bg-indigo-50"),
+					Span(Class("flex items-center gap-2"),
+						Input(Type(inputType), Name("mod-"+g.ID), Value(o.Name), If(single && least == 1, Required())),
+						Text(o.Name),
+					),
+					If(o.PriceDelta != 0, Span(Class("text-sm text-gray-500"), Text(formatDelta(o.PriceDelta)))),
+				)
+			}),
+		),
+	)
+}
+
+// formatDelta formats a modifier price change with its sign, e.g. "+500 TZS".
+func formatDelta(d float64) string {
+	if d < 0 {
+		return "−" + FormatTZS(-d)
+	}
+	return "+" + FormatTZS(d)
+}
@@ -0,0 +1,401 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/internal/purchasing"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// PurchaseOrderListPage renders /purchase-orders: purchase orders newest
+// first, filterable by status, with a form to start a new one.
+//
+// Parameters:
+//   - orders: the purchase orders to list.
+//   - suppliers: suppliers a new order can be placed with.
+//   - status: the status filter, or empty for all.
+//   - errorMsg: optional error message to display above the list.
+func PurchaseOrderListPage(orders []model.PurchaseOrder, suppliers []model.Supplier, status, errorMsg string) Node {
+	return Layout("/purchase-orders", true,
+		Div(
+			ID("main"),
+			Class("max-w-5xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Purchase Orders")),
+				Div(Class("flex items-center gap-2"),
+					reportLink("/reorder", "Reorder list"),
+					reportLink("/suppliers", "Suppliers"),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Form(
+				Method("POST"),
+				Action("/purchase-orders"),
+				Class("flex flex-wrap items-end gap-3 bg-white border border-gray-200 rounded-lg p-4"),
+				Div(
+					Label(For("supplier"), Class("block text-sm text-gray-600 mb-1"), Text("Supplier")),
+					supplierSelect(suppliers, ""),
+				),
+				Div(Class("flex-grow"),
+					Label(For("note"), Class("block text-sm text-gray-600 mb-1"), Text("Note")),
+					Input(Type("text"), ID("note"), Name("note"), Class("w-full border border-gray-300 rounded p-2")),
+				),
+				Button(Type("submit"), Class("bg-indigo-600 text-white px-4 py-2 rounded hover:bg-indigo-700"), Text("New purchase order")),
+			),
+
+			Div(Class("flex flex-wrap gap-2 text-sm"),
+				statusTab("", status),
+				Map(model.PurchaseStatuses, func(s string) Node { return statusTab(s, status) }),
+			),
+
+			If(len(orders) == 0, P(Class("text-gray-500"), Text("No purchase orders."))),
+			If(len(orders) > 0,
+				Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+					THead(Class("bg-indigo-700 text-white"),
+						Tr(
+							Th(Class("px-4 py-2 text-left"), Text("Created")),
+							Th(Class("px-4 py-2 text-left"), Text("Supplier")),
+							Th(Class("px-4 py-2 text-left"), Text("Status")),
+							Th(Class("px-4 py-2 text-right"), Text("Lines")),
+							Th(Class("px-4 py-2 text-right"), Text("Total")),
+							Th(Class("px-4 py-2 text-right"), Text("Received")),
+						),
+					),
+					TBody(
+						Map(orders, func(po model.PurchaseOrder) Node {
+							return Tr(
+								Td(Class("px-4 py-2 border-t"),
+									A(Href("/purchase-orders/"+po.ID), Class("text-indigo-600 hover:underline"), Text(formatTimestamp(po.Created))),
+								),
+								Td(Class("px-4 py-2 border-t"), Text(po.Expand.Supplier.Name)),
+								Td(Class("px-4 py-2 border-t"), purchaseStatus(po.Status)),
+								Td(Class("px-4 py-2 border-t text-right"), Text(fmt.Sprint(len(po.Lines)))),
+								Td(Class("px-4 py-2 border-t text-right"), Text(FormatTZS(purchasing.Total(po)))),
+								Td(Class("px-4 py-2 border-t text-right"), Text(FormatTZS(purchasing.ReceivedValue(po)))),
+							)
+						}),
+					),
+				),
+			),
+		),
+	)
+}
+
+// PurchaseOrderPage renders /purchase-orders/{id}. Drafts can be edited,
+// sent or cancelled; sent orders show what is outstanding, link to the
+// receiving screen and list the deliveries received so far.
+//
+// Parameters:
+//   - po: the purchase order, with its supplier expanded.
+//   - items: items that can be added to a draft.
+//   - suppliers: suppliers a draft can be placed with.
+//   - deliveries: restock movements received against the order, newest first.
+//   - errorMsg: optional error message to display at the top of the page.
+func PurchaseOrderPage(po model.PurchaseOrder, items []model.Item, suppliers []model.Supplier, deliveries []model.StockMovement, errorMsg string) Node {
+	return Layout("/purchase-orders", true,
+		Div(
+			ID("main"),
+			Class("max-w-5xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between print:hidden"),
+				Div(Class("flex items-center gap-3"),
+					H2(Class("text-2xl font-bold text-gray-800"), Text("Purchase Order")),
+					purchaseStatus(po.Status),
+				),
+				Div(Class("flex items-center gap-2"),
+					If(po.Status != model.PurchaseDraft, printButton()),
+					If(purchasing.CanReceive(po), reportLink("/purchase-orders/"+po.ID+"/receive", "Receive goods")),
+					A(Href("/purchase-orders"), Class("text-sm text-indigo-600 hover:underline"), Text("All purchase orders")),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			If(po.Status == model.PurchaseDraft, purchaseDraftForm(po, items, suppliers)),
+			If(po.Status != model.PurchaseDraft, purchaseSummary(po)),
+
+			Div(Class("flex gap-3 print:hidden"),
+				If(po.Status == model.PurchaseDraft,
+					Form(Method("POST"), Action("/purchase-orders/"+po.ID+"/send"), Class("flex-grow"),
+						Button(Type("submit"), If(!purchasing.CanSend(po), Disabled()),
+							Class("w-full bg-green-600 text-white font-semibold py-2 px-4 rounded hover:bg-green-700 disabled:opacity-50"),
+							Text("Mark as sent"),
+						),
+					),
+				),
+				If(purchasing.CanCancel(po),
+					Form(Method("POST"), Action("/purchase-orders/"+po.ID+"/cancel"),
+						Attr("onsubmit", "return confirm('Cancel this purchase order? Goods received so far stay in stock.')"),
+						Button(Type("submit"), Class("border border-red-400 text-red-700 font-semibold py-2 px-4 rounded hover:bg-red-50"),
+							Text("Cancel order"),
+						),
+					),
+				),
+			),
+
+			If(len(deliveries) > 0,
+				Div(
+					H3(Class("text-lg font-semibold text-gray-700 mb-2"), Text("Deliveries")),
+					Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+						THead(Class("bg-gray-100 text-gray-700"),
+							Tr(
+								Th(Class("px-4 py-2 text-left"), Text("Received")),
+								Th(Class("px-4 py-2 text-left"), Text("Item")),
+								Th(Class("px-4 py-2 text-right"), Text("Quantity")),
+								Th(Class("px-4 py-2 text-left"), Text("Note")),
+								Th(Class("px-4 py-2 text-left"), Text("By")),
+							),
+						),
+						TBody(
+							Map(deliveries, func(m model.StockMovement) Node {
+								name := m.Item
+								for _, l := range po.Lines {
+									if l.Item == m.Item {
+										name = l.Name
+									}
+								}
+								return Tr(
+									Td(Class("px-4 py-2 border-t"), Text(formatTimestamp(m.Created))),
+									Td(Class("px-4 py-2 border-t capitalize"), Text(name)),
+									Td(Class("px-4 py-2 border-t text-right"), Text(formatAmount(m.Quantity))),
+									Td(Class("px-4 py-2 border-t"), Text(m.Reason)),
+									Td(Class("px-4 py-2 border-t"), Text(m.Expand.User.Username)),
+								)
+							}),
+						),
+					),
+				),
+			),
+		),
+	)
+}
+
+// purchaseDraftForm edits the supplier, note and lines of a draft. Lines
+// get a few empty rows to add more; a blank cost uses the item's cost.
+func purchaseDraftForm(po model.PurchaseOrder, items []model.Item, suppliers []model.Supplier) Node {
+	rows := append([]model.PurchaseLine{}, po.Lines...)
+	for range 3 {
+		rows = append(rows, model.PurchaseLine{})
+	}
+
+	return Form(
+		Method("POST"),
+		Action("/purchase-orders/"+po.ID),
+		Class("space-y-4 bg-white border border-gray-200 rounded-lg p-4"),
+		Div(Class("flex flex-wrap gap-3"),
+			Div(
+				Label(For("supplier"), Class("block text-sm text-gray-600 mb-1"), Text("Supplier")),
+				supplierSelect(suppliers, po.Supplier),
+			),
+			Div(Class("flex-grow"),
+				Label(For("note"), Class("block text-sm text-gray-600 mb-1"), Text("Note")),
+				Input(Type("text"), ID("note"), Name("note"), Value(po.Note), Class("w-full border border-gray-300 rounded p-2")),
+			),
+		),
+		Div(Class("space-y-2"),
+			Div(Class("flex gap-2 text-sm text-gray-600"),
+				Span(Class("flex-grow"), Text("Item")),
+				Span(Class("w-24"), Text("Quantity")),
+				Span(Class("w-32"), Text("Unit cost (TZS)")),
+			),
+			Map(rows, func(l model.PurchaseLine) Node {
+				opts := []Node{Option(Value(""), Text("—"))}
+				for _, it := range items {
+					opts = append(opts, Option(Value(it.ID), Text(it.Name), If(it.ID == l.Item, Selected())))
+				}
+				qty, cost := "", ""
+				if l.Quantity > 0 {
+					qty = fmt.Sprint(l.Quantity)
+				}
+				if l.Item != "" {
+					cost = fmt.Sprint(l.Cost)
+				}
+				return Div(Class("flex gap-2"),
+					Select(append([]Node{Name("line_item"), Class("flex-grow border border-gray-300 rounded p-2 capitalize")}, opts...)...),
+					Input(Type("number"), Name("line_quantity"), Value(qty), Min("1"), Step("1"), Class("w-24 border border-gray-300 rounded p-2")),
+					Input(Type("number"), Name("line_cost"), Value(cost), Min("0"), Step("0.01"), Placeholder("Item cost"), Class("w-32 border border-gray-300 rounded p-2")),
+				)
+			}),
+		),
+		Div(Class("flex items-center justify-between"),
+			P(Class("text-gray-700"), Text("Total: "+FormatTZS(purchasing.Total(po)))),
+			Button(Type("submit"), Class("bg-indigo-600 text-white px-4 py-2 rounded hover:bg-indigo-700"), Text("Save draft")),
+		),
+	)
+}
+
+// purchaseSummary shows a sent order as it went to the supplier, with what
+// has been received against each line.
+func purchaseSummary(po model.PurchaseOrder) Node {
+	s := po.Expand.Supplier
+	return Div(Class("space-y-4 bg-white border border-gray-200 rounded-lg p-4"),
+		Div(Class("flex justify-between gap-4"),
+			Div(
+				P(Class("text-lg font-semibold text-gray-800"), Text(s.Name)),
+				If(s.Phone != "", P(Class("text-sm text-gray-600"), Text(s.Phone))),
+				If(s.Email != "", P(Class("text-sm text-gray-600"), Text(s.Email))),
+			),
+			Div(Class("text-sm text-gray-600 text-right"),
+				P(Text("Created "+formatTimestamp(po.Created))),
+				If(po.SentAt != "", P(Text("Sent "+formatTimestamp(po.SentAt)))),
+				If(po.Expand.CreatedBy.Username != "", P(Text("By "+po.Expand.CreatedBy.Username))),
+			),
+		),
+		If(po.Note != "", P(Class("text-gray-600"), Text(po.Note))),
+		Table(Class("min-w-full"),
+			THead(Class("text-gray-700"),
+				Tr(
+					Th(Class("px-2 py-1 text-left"), Text("Item")),
+					Th(Class("px-2 py-1 text-right"), Text("Ordered")),
+					Th(Class("px-2 py-1 text-right"), Text("Received")),
+					Th(Class("px-2 py-1 text-right"), Text("Outstanding")),
+					Th(Class("px-2 py-1 text-right"), Text("Unit cost")),
+					Th(Class("px-2 py-1 text-right"), Text("Total")),
+				),
+			),
+			TBody(
+				Map(po.Lines, func(l model.PurchaseLine) Node {
+					return Tr(
+						Td(Class("px-2 py-1 border-t capitalize"), Text(l.Name)),
+						Td(Class("px-2 py-1 border-t text-right"), Text(fmt.Sprint(l.Quantity))),
+						Td(Class("px-2 py-1 border-t text-right"), Text(fmt.Sprint(l.Received))),
+						Td(Class("px-2 py-1 border-t text-right"), Text(fmt.Sprint(purchasing.Outstanding(l)))),
+						Td(Class("px-2 py-1 border-t text-right"), Text(FormatTZS(l.Cost))),
+						Td(Class("px-2 py-1 border-t text-right"), Text(FormatTZS(float64(l.Quantity)*l.Cost))),
+					)
+				}),
+			),
+		),
+		P(Class("text-right font-semibold text-gray-800"), Text("Total: "+FormatTZS(purchasing.Total(po)))),
+	)
+}
+
+// ReceivePage renders /purchase-orders/{id}/receive, where a delivery is
+// booked in. Each outstanding line is prefilled with what is still due;
+// lines of perishable items, by item ID in perishable, also ask for an
+// expiry date.
+func ReceivePage(po model.PurchaseOrder, perishable map[string]bool, errorMsg string) Node {
+	return Layout("/purchase-orders", true,
+		Div(
+			ID("main"),
+			Class("max-w-3xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Receive from "+po.Expand.Supplier.Name)),
+				A(Href("/purchase-orders/"+po.ID), Class("text-sm text-indigo-600 hover:underline"), Text("Back to order")),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Form(
+				Method("POST"),
+				Action("/purchase-orders/"+po.ID+"/receive"),
+				Class("space-y-4 bg-white border border-gray-200 rounded-lg p-4"),
+				P(Class("text-sm text-gray-600"), Text("Enter what arrived in this delivery. Anything not delivered stays outstanding. "+
+					"Item costs become the weighted average of the stock on hand and this delivery.")),
+				Table(Class("min-w-full"),
+					THead(Class("text-gray-700"),
+						Tr(
+							Th(Class("px-2 py-1 text-left"), Text("Item")),
+							Th(Class("px-2 py-1 text-right"), Text("Outstanding")),
+							Th(Class("px-2 py-1 text-right"), Text("Received now")),
+							Th(Class("px-2 py-1 text-right"), Text("Expires")),
+						),
+					),
+					TBody(
+						Map(po.Lines, func(l model.PurchaseLine) Node {
+							out := purchasing.Outstanding(l)
+							return If(out > 0, Tr(
+								Td(Class("px-2 py-1 border-t capitalize"), Text(l.Name)),
+								Td(Class("px-2 py-1 border-t text-right"), Text(fmt.Sprint(out))),
+								Td(Class("px-2 py-1 border-t text-right"),
+									Input(Type("number"), Name("receive-"+l.Item), Value(fmt.Sprint(out)), Min("0"), Max(fmt.Sprint(out)), Step("1"),
+										Class("w-24 border border-gray-300 rounded p-1 text-right"),
+									),
+								),
+								Td(Class("px-2 py-1 border-t text-right"),
+									If(perishable[l.Item],
+										Input(Type("date"), Name("expires-"+l.Item), Class("border border-gray-300 rounded p-1")),
+									),
+								),
+							))
+						}),
+					),
+				),
+				Div(
+					Label(For("reference"), Class("block text-sm text-gray-600 mb-1"), Text("Delivery note (optional)")),
+					Input(Type("text"), ID("reference"), Name("reference"), Placeholder("e.g. delivery note number"),
+						Class("w-full border border-gray-300 rounded p-2"),
+					),
+				),
+				Button(Type("submit"), Class("w-full bg-green-600 text-white font-semibold py-2 px-4 rounded hover:bg-green-700"),
+					Text("Book delivery into stock"),
+				),
+			),
+		),
+	)
+}
+
+// supplierSelect renders a required supplier dropdown.
+func supplierSelect(suppliers []model.Supplier, selected string) Node {
+	return Select(ID("supplier"), Name("supplier"), Required(), Class("border border-gray-300 rounded p-2"),
+		Option(Value(""), Text("Choose a supplier")),
+		Map(suppliers, func(s model.Supplier) Node {
+			return Option(Value(s.ID), Text(s.Name), If(s.ID == selected, Selected()))
+		}),
+	)
+}
+
+// statusTab links to the purchase order list filtered by status.
+func statusTab(status, current string) Node {
+	label := "All"
+	href := "/purchase-orders"
+	if status != "" {
+		label = purchaseStatusLabel(status)
+		href += "?status=" + status
+	}
+	return A(Href(href),
+		Classes{
+			"px-3 py-1 rounded-full border":                   true,
+			"bg-indigo-600 text-white border-indigo-600":      status == current,
+			"border-gray-300 text-gray-700 hover:bg-gray-100": status != current,
+		},
+		Text(label),
+	)
+}
+
+// purchaseStatus renders a purchase order status as a coloured pill.
+func purchaseStatus(status string) Node {
+	return Span(
+		Classes{
+			"px-2 py-0.5 rounded-full text-sm": true,
+			"bg-gray-200 text-gray-700":        status == model.PurchaseDraft || status == model.PurchaseCancelled,
+			"bg-blue-100 text-blue-800":        status == model.PurchaseSent,
+			"bg-yellow-100 text-yellow-800":    status == model.PurchasePartial,
+			"bg-green-100 text-green-800":      status == model.PurchaseReceived,
+		},
+		Text(purchaseStatusLabel(status)),
+	)
+}
+
+// purchaseStatusLabel turns e.g. "partially_received" into "Partially received".
+func purchaseStatusLabel(status string) string {
+	s := strings.ReplaceAll(status, "_", " ")
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,109 @@
+package html
+
+import (
+	"fmt"
+
+	"github.com/rustacean-dev/possystem/internal/reorder"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// ReorderPage renders /reorder: the items at or below their reorder point,
+// grouped by supplier with the amount to order back up to par. It is meant
+// to be printed or read out to suppliers.
+//
+// Parameters:
+//   - groups: the reorder list, one group per supplier.
+//   - errorMsg: optional error message to display above the list.
+func ReorderPage(groups []reorder.Group, errorMsg string) Node {
+	return Layout("/reorder", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between print:hidden"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Reorder List")),
+				Div(Class("flex items-center gap-2"),
+					printButton(),
+					A(Href("/suppliers"), Class("text-sm text-indigo-600 hover:underline"), Text("Suppliers")),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			If(len(groups) == 0 && errorMsg == "",
+				P(Class("text-gray-500"), Text("Nothing to reorder: every item is above its reorder point.")),
+			),
+
+			Map(groups, func(g reorder.Group) Node {
+				name := g.Supplier.Name
+				if g.Supplier.ID == "" {
+					name = "No supplier"
+				}
+				return Div(Class("bg-white border border-gray-200 rounded-lg p-4 space-y-3 break-inside-avoid"),
+					Div(Class("flex items-baseline justify-between"),
+						H3(Class("text-lg font-semibold text-gray-800"), Text(name)),
+						P(Class("text-sm text-gray-500"),
+							If(g.Supplier.Phone != "", Span(Class("mr-3"), Text(g.Supplier.Phone))),
+							If(g.Supplier.Email != "", A(Href("mailto:"+g.Supplier.Email), Class("text-indigo-600 hover:underline"), Text(g.Supplier.Email))),
+						),
+					),
+					Table(Class("min-w-full"),
+						THead(Class("text-gray-700"),
+							Tr(
+								Th(Class("px-2 py-1 text-left"), Text("Item")),
+								Th(Class("px-2 py-1 text-right"), Text("In stock")),
+								Th(Class("px-2 py-1 text-right"), Text("Reorder point")),
+								Th(Class("px-2 py-1 text-right"), Text("Par")),
+								Th(Class("px-2 py-1 text-right"), Text("Order")),
+								Th(Class("px-2 py-1 text-right"), Text("Est. cost")),
+							),
+						),
+						TBody(
+							Map(g.Lines, func(l reorder.Line) Node {
+								par := "-"
+								if l.Item.ParLevel > 0 {
+									par = fmt.Sprint(l.Item.ParLevel)
+								}
+								return Tr(
+									Td(Class("px-2 py-1 border-t capitalize"),
+										A(Href("/items/"+l.Item.ID+"/edit"), Class("hover:underline"), Text(l.Item.Name)),
+									),
+									Td(Class("px-2 py-1 border-t text-right"), Text(fmt.Sprint(l.Item.Quantity))),
+									Td(Class("px-2 py-1 border-t text-right"), Text(fmt.Sprint(l.Item.ReorderPoint))),
+									Td(Class("px-2 py-1 border-t text-right"), Text(par)),
+									Td(Class("px-2 py-1 border-t text-right font-semibold"), Text(fmt.Sprint(l.Amount))),
+									Td(Class("px-2 py-1 border-t text-right"), Text(FormatTZS(float64(l.Amount)*l.Item.Cost))),
+								)
+							}),
+						),
+					),
+					Div(Class("flex items-center justify-end gap-4"),
+						P(Class("text-sm text-gray-600"), Text("Estimated total: "+FormatTZS(g.Cost))),
+						If(g.Supplier.ID != "", draftPurchaseForm(g)),
+					),
+				)
+			}),
+		),
+	)
+}
+
+// draftPurchaseForm starts a draft purchase order with the group's lines.
+func draftPurchaseForm(g reorder.Group) Node {
+	return Form(
+		Method("POST"),
+		Action("/purchase-orders"),
+		Class("print:hidden"),
+		Input(Type("hidden"), Name("supplier"), Value(g.Supplier.ID)),
+		Map(g.Lines, func(l reorder.Line) Node {
+			return Group{
+				Input(Type("hidden"), Name("line_item"), Value(l.Item.ID)),
+				Input(Type("hidden"), Name("line_quantity"), Value(fmt.Sprint(l.Amount))),
+				Input(Type("hidden"), Name("line_cost"), Value(fmt.Sprint(l.Item.Cost))),
+			}
+		}),
+		Button(Type("submit"), Class("px-3 py-2 rounded bg-indigo-600 text-white text-sm hover:bg-indigo-700"), Text("Draft purchase order")),
+	)
+}
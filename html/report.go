@@ -0,0 +1,200 @@
+package html
+
+import (
+	"fmt"
+
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// XReportPage renders the /reports/x page.
+// An X-report is a read-only snapshot of sales since the last Z-report;
+// from here the manager can print it, export it, or close the day.
+//
+// Parameters:
+//   - s: the summary of all orders since the last closing.
+//   - errorMsg: optional error message to display above the report.
+func XReportPage(s model.SalesSummary, errorMsg string) Node {
+	return Layout("/reports/x", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("X-Report")),
+				Div(Class("flex gap-2 print:hidden"),
+					printButton(),
+					reportLink("/reports/x/export.csv", "Export CSV"),
+					reportLink("/reports/z", "Z-Reports"),
+					reportLink("/reports/margin", "Margins"),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			P(Class("text-sm text-gray-500"),
+				Text("Snapshot since "+formatTimestamp(s.From)+". Nothing is saved until the day is closed."),
+			),
+
+			summaryView(s),
+
+			Form(
+				Method("POST"),
+				Action("/reports/z"),
+				Attr("hx-post", "/reports/z"),
+				Attr("hx-confirm", "Close the business day? This freezes the numbers and cannot be undone."),
+				Class("print:hidden"),
+				Button(Type("submit"),
+					Class("w-full bg-red-600 text-white font-semibold py-2 px-4 rounded hover:bg-red-700 transition"),
+					Text("Close Day (Z-Report)"),
+				),
+			),
+		),
+	)
+}
+
+// ZReportPage renders a single closed business day from its frozen summary.
+func ZReportPage(z model.ZReport) Node {
+	return Layout("/reports/z", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Textf("Z-Report #%d", z.Sequence)),
+				Div(Class("flex gap-2 print:hidden"),
+					printButton(),
+					reportLink("/reports/z/"+z.ID+"/export.csv", "Export CSV"),
+					reportLink("/reports/z", "All Z-Reports"),
+				),
+			),
+
+			P(Class("text-sm text-gray-500"),
+				Text(fmt.Sprintf("%s – %s", formatTimestamp(z.OpenedAt), formatTimestamp(z.ClosedAt))),
+				If(z.ClosedBy != "", Text(" · closed by "+z.ClosedBy)),
+			),
+
+			summaryView(z.Summary),
+		),
+	)
+}
+
+// ZReportListPage renders the list of closed business days, newest first.
+func ZReportListPage(reports []model.ZReport, errorMsg string) Node {
+	return Layout("/reports/z", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12"),
+
+			Div(Class("flex items-center justify-between mb-4"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Z-Reports")),
+				reportLink("/reports/x", "Current X-Report"),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded mb-4"), Text(errorMsg)),
+			),
+
+			Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+				THead(Class("bg-indigo-700 text-white"),
+					Tr(
+						Th(Class("px-4 py-2 text-left"), Text("#")),
+						Th(Class("px-4 py-2 text-left"), Text("Opened")),
+						Th(Class("px-4 py-2 text-left"), Text("Closed")),
+						Th(Class("px-4 py-2 text-left"), Text("Orders")),
+						Th(Class("px-4 py-2 text-left"), Text("Net Sales")),
+					),
+				),
+				TBody(
+					Map(reports, func(z model.ZReport) Node {
+						return Tr(
+							Td(Class("px-4 py-2 border-t"),
+								A(Href("/reports/z/"+z.ID), Class("text-indigo-600 hover:underline"), Textf("%d", z.Sequence)),
+							),
+							Td(Class("px-4 py-2 border-t"), Text(formatTimestamp(z.OpenedAt))),
+							Td(Class("px-4 py-2 border-t"), Text(formatTimestamp(z.ClosedAt))),
+							Td(Class("px-4 py-2 border-t"), Textf("%d", z.Summary.OrderCount)),
+							Td(Class("px-4 py-2 border-t"), Text(FormatTZS(z.Summary.Net))),
+						)
+					}),
+				),
+			),
+		),
+	)
+}
+
+// summaryView renders the totals and breakdown tables shared by X- and Z-reports.
+func summaryView(s model.SalesSummary) Node {
+	return Div(Class("space-y-6"),
+		Div(Class("grid grid-cols-2 md:grid-cols-4 gap-4"),
+			statCard("Gross Sales", FormatTZS(s.Gross)),
+			statCard("Refunds", FormatTZS(s.Refunds)),
+			statCard("Net Sales", FormatTZS(s.Net)),
+			statCard("Orders", fmt.Sprintf("%d", s.OrderCount)),
+			statCard("Items Sold", fmt.Sprintf("%d", s.ItemCount)),
+		),
+		breakdownTable("By Tender", s.ByTender, true),
+		breakdownTable("By Cashier", s.ByCashier, true),
+		breakdownTable("Orders by Status", s.ByStatus, false),
+	)
+}
+
+// statCard renders a single headline number.
+func statCard(label, value string) Node {
+	return Div(Class("bg-white border border-gray-200 rounded-lg p-4"),
+		P(Class("text-sm text-gray-500"), Text(label)),
+		P(Class("text-xl font-semibold text-gray-800"), Text(value)),
+	)
+}
+
+// breakdownTable renders a grouped total; withAmount hides the amount column
+// for breakdowns where only the count is meaningful.
+func breakdownTable(title string, rows []model.Breakdown, withAmount bool) Node {
+	return Div(
+		H3(Class("text-lg font-semibold text-gray-700 mb-2"), Text(title)),
+		Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+			THead(Class("bg-gray-100 text-gray-700"),
+				Tr(
+					Th(Class("px-4 py-2 text-left"), Text("Name")),
+					Th(Class("px-4 py-2 text-left"), Text("Orders")),
+					If(withAmount, Th(Class("px-4 py-2 text-left"), Text("Amount"))),
+				),
+			),
+			TBody(
+				Map(rows, func(b model.Breakdown) Node {
+					return Tr(
+						Td(Class("px-4 py-2 border-t capitalize"), Text(b.Name)),
+						Td(Class("px-4 py-2 border-t"), Textf("%d", b.Orders)),
+						If(withAmount, Td(Class("px-4 py-2 border-t"), Text(FormatTZS(b.Amount)))),
+					)
+				}),
+			),
+		),
+	)
+}
+
+func printButton() Node {
+	return Button(Type("button"), Attr("onclick", "window.print()"),
+		Class("px-3 py-2 rounded bg-gray-700 text-white text-sm hover:bg-gray-800"),
+		Text("Print"),
+	)
+}
+
+func reportLink(href, label string) Node {
+	return A(Href(href),
+		Class("px-3 py-2 rounded bg-indigo-600 text-white text-sm hover:bg-indigo-700"),
+		Text(label),
+	)
+}
+
+// formatTimestamp shortens a PocketBase datetime to "YYYY-MM-DD HH:MM".
+func formatTimestamp(s string) string {
+	t, err := model.ParseTime(s)
+	if err != nil {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
@@ -0,0 +1,258 @@
+package html
+
+import (
+	"strconv"
+
+	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/internal/split"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// SplitPage renders /orders/{id}/split. Before the bill is split it
+// previews the parts for the chosen way of splitting, updating as lines
+// are assigned; after, it lists the parts with a payment form for each
+// one still due.
+//
+// Parameters:
+//   - o: the order, with seats as entered when splitting by seat.
+//   - mode: [split.ByItem], [split.BySeat] or [split.Evenly].
+//   - n: the number of guests or even parts.
+//   - guests: the guest each line is assigned to when splitting by item.
+//   - parts: the preview; nil if the split is invalid.
+//   - payments: the parts of a bill already split.
+//   - errorMsg: optional error message to display at the top.
+func SplitPage(o model.Order, mode string, n int, guests []int, parts []split.Part, payments []model.Payment, errorMsg string) Node {
+	return Layout("/tabs", true,
+		Div(
+			ID("main"),
+			Class("max-w-3xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Split Bill")),
+				reportLink("/orders/"+o.ID, "Order"),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Div(Class("flex justify-between text-lg font-semibold text-gray-800"),
+				Span(Text("Order total")),
+				Span(Text(FormatTZS(o.TotalCost))),
+			),
+
+			If(len(payments) > 0, splitPayments(o, payments)),
+			If(len(payments) == 0 && o.Status != model.OrderStatusOpen,
+				P(Class("text-gray-500"), Text("Only open tabs can be split.")),
+			),
+			If(len(payments) == 0 && o.Status == model.OrderStatusOpen, splitForm(o, mode, n, guests, parts)),
+		),
+	)
+}
+
+// splitForm picks how to split the bill and previews the parts. Changes
+// reload the preview; the submit button sets the parts up as due payments.
+func splitForm(o model.Order, mode string, n int, guests []int, parts []split.Part) Node {
+	modes := []struct{ mode, label string }{
+		{split.ByItem, "By item"},
+		{split.BySeat, "By seat"},
+		{split.Evenly, "Evenly"},
+	}
+
+	return Form(
+		Method("GET"),
+		Action("/orders/"+o.ID+"/split"),
+		Attr("hx-get", "/orders/"+o.ID+"/split"),
+		Attr("hx-trigger", "change"),
+		Attr("hx-target", "#main"),
+		Attr("hx-select", "#main"),
+		Attr("hx-swap", "outerHTML"),
+		Class("space-y-4"),
+
+		Div(Class("flex gap-2"),
+			Map(modes, func(m struct{ mode, label string }) Node {
+				return Label(
+					Classes{
+						"px-4 py-2 rounded-full border cursor-pointer": true,
+						"bg-indigo-600 text-white border-indigo-600":   m.mode == mode,
+						"text-gray-700 border-gray-300":                m.mode != mode,
+					},
+					Input(Type("radio"), Name("mode"), Value(m.mode), Class("sr-only"), If(m.mode == mode, Checked())),
+					Text(m.label),
+				)
+			}),
+		),
+
+		If(mode != split.BySeat,
+			Div(Class("flex items-center gap-2"),
+				Label(For("n"), Class("text-gray-700"), If(mode == split.Evenly, Text("Parts")), If(mode != split.Evenly, Text("Guests"))),
+				Input(ID("n"), Type("number"), Name("n"), Value(strconv.Itoa(n)), Min("2"), Max(strconv.Itoa(split.MaxParts)),
+					Class("w-20 border border-gray-300 rounded p-2"),
+				),
+			),
+		),
+
+		If(mode != split.Evenly,
+			Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+				TBody(
+					Map(indexed(o.Items), func(i int) Node {
+						l := o.Items[i]
+						return Tr(Class("border-t"),
+							Td(Class("px-4 py-2 capitalize"), Textf("%d × %s", l.Quantity, l.Name)),
+							Td(Class("px-4 py-2 text-right text-gray-600"), Text(FormatTZS(compute.LineTotal(l)))),
+							Td(Class("px-4 py-2 text-right"),
+								If(mode == split.ByItem, guestSelect(n, guests[i])),
+								If(mode == split.BySeat,
+									Input(Type("number"), Name("seat"), Min("1"), Placeholder("Seat"),
+										If(l.Seat > 0, Value(strconv.Itoa(l.Seat))),
+										Class("w-20 border border-gray-300 rounded p-1"),
+									),
+								),
+							),
+						)
+					}),
+				),
+			),
+		),
+
+		If(len(parts) > 0,
+			Div(Class("grid sm:grid-cols-2 gap-3"),
+				Map(parts, func(p split.Part) Node {
+					return Div(Class("bg-white border border-gray-200 rounded-lg p-4 flex justify-between"),
+						Span(Class("font-medium"), Text(p.Label)),
+						Span(Class("font-semibold"), Text(FormatTZS(p.Amount))),
+					)
+				}),
+			),
+		),
+
+		Button(Type("submit"),
+			Attr("formmethod", "post"),
+			If(len(parts) == 0, Disabled()),
+			Class("w-full bg-green-600 text-white text-lg font-semibold py-3 rounded-lg hover:bg-green-700 disabled:opacity-50"),
+			Text("Split Bill"),
+		),
+	)
+}
+
+// guestSelect assigns a line to one of n guests.
+func guestSelect(n, guest int) Node {
+	options := make([]Node, n)
+	for g := 1; g <= n; g++ {
+		options[g-1] = Option(Value(strconv.Itoa(g)), Textf("Guest %d", g), If(g == guest, Selected()))
+	}
+	return Select(Name("guest"), Class("border border-gray-300 rounded p-1"), Group(options))
+}
+
+// splitPayments lists the parts of a split bill with a payment form for
+// each part still due.
+func splitPayments(o model.Order, payments []model.Payment) Node {
+	return Div(Class("space-y-3"),
+		Map(payments, func(p model.Payment) Node {
+			return Div(Class("bg-white border border-gray-200 rounded-lg p-4 flex flex-wrap items-center justify-between gap-3"),
+				Div(
+					P(Class("font-semibold text-gray-800"), Text(p.Label)),
+					If(len(p.Lines) > 0, P(Class("text-sm text-gray-600"), Text(describeLines(p.Lines)))),
+				),
+				Span(Class("text-lg font-semibold"), Text(FormatTZS(p.Amount))),
+				If(p.Status == model.PaymentPaid,
+					A(Href("/payments/"+p.ID), Class("text-green-700 font-semibold hover:underline"), Textf("Paid by %s · Receipt", p.Tender)),
+				),
+				If(p.Status != model.PaymentPaid,
+					Form(
+						Method("POST"),
+						Action("/payments/"+p.ID+"/pay"),
+						Class("flex gap-2"),
+						tenderSelect(""),
+						Button(Type("submit"), Class("bg-green-600 text-white font-semibold px-4 rounded hover:bg-green-700 whitespace-nowrap"), Text("Take Payment")),
+					),
+				),
+			)
+		}),
+		If(!split.Started(payments),
+			Form(
+				Method("POST"),
+				Action("/orders/"+o.ID+"/split/reset"),
+				Button(Type("submit"), Class("text-sm text-gray-600 hover:underline"), Text("Change split")),
+			),
+		),
+	)
+}
+
+// PaymentReceiptPage renders /payments/{id}: the receipt of one part of a
+// split bill, with what it paid for and the order it belongs to.
+func PaymentReceiptPage(p model.Payment, o model.Order) Node {
+	return Layout("/tabs", true,
+		Div(
+			ID("main"),
+			Class("max-w-2xl mx-auto mt-12 mb-12 space-y-6 print:mt-0 print:max-w-xs print:text-sm"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Receipt")),
+				Div(Class("flex gap-2 print:hidden"),
+					printButton(),
+					reportLink("/orders/"+o.ID+"/split", "Split Bill"),
+				),
+			),
+
+			Dl(Class("grid grid-cols-2 gap-x-4 gap-y-1 text-gray-700"),
+				Dt(Text("Order")), Dd(Class("text-right font-mono"), Text(o.ID)),
+				Dt(Text("Part")), Dd(Class("text-right"), Text(p.Label)),
+				Dt(Text("Status")), Dd(Class("text-right capitalize"), Text(p.Status)),
+				If(p.PaidAt != "", Group{Dt(Text("Paid")), Dd(Class("text-right"), Text(formatTimestamp(p.PaidAt)))}),
+				If(p.Tender != "", Group{Dt(Text("Paid by")), Dd(Class("text-right capitalize"), Text(p.Tender))}),
+			),
+
+			If(len(p.Lines) > 0,
+				Table(Class("w-full bg-white border border-gray-200 rounded-md overflow-hidden print:border-0"),
+					TBody(
+						Map(p.Lines, func(line model.Item) Node {
+							return Tr(Class("border-t align-top"),
+								Td(Class("px-3 py-2"),
+									P(Class("font-medium capitalize"), Text(line.Name)),
+									OrderLineDetails(line),
+								),
+								Td(Class("px-3 py-2 text-right"), Textf("%d × %s", line.Quantity, FormatTZS(compute.UnitPrice(line)))),
+								Td(Class("px-3 py-2 text-right"), Text(FormatTZS(compute.LineTotal(line)))),
+							)
+						}),
+					),
+				),
+			),
+			If(len(p.Lines) == 0, P(Class("text-gray-600"), Textf("Share %s of the bill.", p.Label))),
+
+			Dl(Class("grid grid-cols-2 gap-x-4 gap-y-1 text-gray-700"),
+				Dt(Text("Order total")), Dd(Class("text-right"), Text(FormatTZS(o.TotalCost))),
+				Dt(Class("text-lg font-semibold text-gray-800 border-t pt-2"), Text("This part")),
+				Dd(Class("text-lg font-semibold text-gray-800 border-t pt-2 text-right"), Text(FormatTZS(p.Amount))),
+			),
+		),
+	)
+}
+
+// describeLines summarises order lines on one line, e.g. "2 × beer, chips".
+func describeLines(lines []model.Item) string {
+	var s string
+	for i, l := range lines {
+		if i > 0 {
+			s += ", "
+		}
+		if l.Quantity != 1 {
+			s += strconv.Itoa(l.Quantity) + " × "
+		}
+		s += l.Name
+	}
+	return s
+}
+
+// indexed returns the indexes of a slice, to map over lines by position.
+func indexed[T any](s []T) []int {
+	idx := make([]int, len(s))
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
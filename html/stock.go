@@ -0,0 +1,179 @@
+package html
+
+import (
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/stock"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// StockHistoryPage renders /items/{id}/stock: the item's stock ledger with
+// a running balance, a form to record restocks, waste, refund returns and
+// adjustments, and a warning when the stored quantity and the ledger disagree.
+// Perishable items also list their lots.
+//
+// Parameters:
+//   - item: the item, with its stored quantity.
+//   - movements: the item's whole ledger, newest first.
+//   - lots: the item's lots with stock left, first-expiry-first-out.
+//   - errorMsg: optional error message to display above the form.
+func StockHistoryPage(item model.Item, movements []model.StockMovement, lots []model.Lot, errorMsg string) Node {
+	balance := stock.Balance(movements)
+	drift := stock.Drift(float64(item.Quantity), movements)
+
+	return Layout("/items/stock", true,
+		Div(
+			ID("main"),
+			Class("max-w-5xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800 capitalize"), Text("Stock of "+item.Name)),
+				reportLink("/items/"+item.ID+"/edit", "Edit item"),
+			),
+
+			Div(Class("grid grid-cols-1 sm:grid-cols-2 gap-4"),
+				statCard("In stock", formatAmount(float64(item.Quantity))),
+				statCard("Ledger balance", formatAmount(balance)),
+			),
+
+			If(len(item.Recipe) > 0,
+				P(Class("text-gray-600"), Text("This item has a recipe, so sales use up its ingredients rather than this stock.")),
+			),
+
+			If(drift != 0,
+				Div(Class("bg-yellow-50 border border-yellow-300 text-yellow-800 px-4 py-3 rounded flex items-center justify-between gap-4"),
+					Span(Textf("The stored quantity is %s the ledger accounts for.", driftText(drift))),
+					Form(Method("POST"), Action("/items/"+item.ID+"/stock/reconcile"),
+						Button(Type("submit"), Class("px-3 py-2 rounded bg-yellow-600 text-white text-sm hover:bg-yellow-700 whitespace-nowrap"),
+							Text("Record difference as adjustment"),
+						),
+					),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Form(
+				Method("POST"),
+				Action("/items/"+item.ID+"/stock"),
+				Class("flex flex-wrap items-end gap-3 bg-white border border-gray-200 rounded-lg p-4"),
+				Div(
+					Label(For("kind"), Class("block text-sm text-gray-600 mb-1"), Text("Movement")),
+					Select(ID("kind"), Name("kind"), Class("border border-gray-300 rounded p-2 capitalize"),
+						Map(stock.Manual, func(k string) Node { return Option(Value(k), Text(k)) }),
+					),
+				),
+				Div(
+					Label(For("quantity"), Class("block text-sm text-gray-600 mb-1"), Text("Quantity")),
+					Input(Type("number"), ID("quantity"), Name("quantity"), Step("1"), Required(), Class("w-24 border border-gray-300 rounded p-2")),
+				),
+				Div(Class("flex-grow"),
+					Label(For("reason"), Class("block text-sm text-gray-600 mb-1"), Text("Reason")),
+					Input(Type("text"), ID("reason"), Name("reason"), Placeholder("e.g. delivery from supplier, dropped tray"),
+						Class("w-full border border-gray-300 rounded p-2"),
+					),
+				),
+				Div(
+					Label(For("order"), Class("block text-sm text-gray-600 mb-1"), Text("Order (optional)")),
+					Input(Type("text"), ID("order"), Name("order"), Class("w-40 border border-gray-300 rounded p-2 font-mono")),
+				),
+				If(item.Perishable,
+					Div(
+						Label(For("expires"), Class("block text-sm text-gray-600 mb-1"), Text("Expires (restocks)")),
+						Input(Type("date"), ID("expires"), Name("expires"), Class("border border-gray-300 rounded p-2")),
+					),
+				),
+				Button(Type("submit"), Class("bg-indigo-600 text-white px-4 py-2 rounded hover:bg-indigo-700"), Text("Record")),
+			),
+
+			If(item.Perishable, lotSection(item, lots)),
+
+			movementTable(movements, balance),
+		),
+	)
+}
+
+// lotSection lists the lots of a perishable item and how much of its stock
+// they account for.
+func lotSection(item model.Item, lots []model.Lot) Node {
+	inLots := 0
+	for _, l := range lots {
+		inLots += l.Quantity
+	}
+
+	return Div(Class("space-y-2"),
+		H3(Class("text-lg font-semibold text-gray-700"), Text("Lots")),
+		If(len(lots) == 0, P(Class("text-gray-500"), Text("No lots with stock left. Each restock starts a new lot."))),
+		If(len(lots) > 0, lotTable(lots, time.Now(), false, "/items/"+item.ID+"/stock")),
+		If(inLots < item.Quantity,
+			P(Class("text-sm text-gray-500"), Textf("%d in stock are not in any lot, e.g. stock from before the item was perishable.", item.Quantity-inLots)),
+		),
+	)
+}
+
+// movementTable lists the ledger newest first, with the balance after each movement.
+func movementTable(movements []model.StockMovement, balance float64) Node {
+	if len(movements) == 0 {
+		return P(Class("text-gray-500"), Text("No stock movements recorded yet."))
+	}
+
+	var rows []Node
+	after := balance
+	for _, m := range movements {
+		rows = append(rows, Tr(
+			Td(Class("px-4 py-2 border-t"), Text(formatTimestamp(m.Created))),
+			Td(Class("px-4 py-2 border-t capitalize"), Text(m.Kind)),
+			Td(
+				Classes{
+					"px-4 py-2 border-t text-right font-mono": true,
+					"text-green-700": m.Quantity > 0,
+					"text-red-700":   m.Quantity < 0,
+				},
+				Text(signedAmount(m.Quantity)),
+			),
+			Td(Class("px-4 py-2 border-t text-right"), Text(formatAmount(after))),
+			Td(Class("px-4 py-2 border-t"), Text(m.Reason)),
+			Td(Class("px-4 py-2 border-t"),
+				If(m.Order != "", A(Href("/orders/"+m.Order), Class("text-indigo-600 hover:underline font-mono"), Text(m.Order))),
+				If(m.PurchaseOrder != "", A(Href("/purchase-orders/"+m.PurchaseOrder), Class("text-indigo-600 hover:underline"), Text("Purchase order"))),
+			),
+			Td(Class("px-4 py-2 border-t"), Text(m.Expand.User.Username)),
+		))
+		after -= m.Quantity
+	}
+
+	return Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+		THead(Class("bg-indigo-700 text-white"),
+			Tr(
+				Th(Class("px-4 py-2 text-left"), Text("When")),
+				Th(Class("px-4 py-2 text-left"), Text("Movement")),
+				Th(Class("px-4 py-2 text-right"), Text("Change")),
+				Th(Class("px-4 py-2 text-right"), Text("Balance")),
+				Th(Class("px-4 py-2 text-left"), Text("Reason")),
+				Th(Class("px-4 py-2 text-left"), Text("Order")),
+				Th(Class("px-4 py-2 text-left"), Text("By")),
+			),
+		),
+		TBody(Group(rows)),
+	)
+}
+
+// signedAmount formats a stock change with its sign, e.g. "+12" or "-3".
+func signedAmount(q float64) string {
+	if q > 0 {
+		return "+" + formatAmount(q)
+	}
+	return formatAmount(q)
+}
+
+func driftText(d float64) string {
+	if d > 0 {
+		return formatAmount(d) + " more than"
+	}
+	return formatAmount(-d) + " less than"
+}
@@ -0,0 +1,232 @@
+package html
+
+import (
+	"fmt"
+
+	"github.com/rustacean-dev/possystem/internal/stocktake"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// StocktakeListPage renders /stocktakes: past and open stocktake sessions,
+// with a form to start a new one when none is open.
+func StocktakeListPage(sessions []model.Stocktake, errorMsg string) Node {
+	open := false
+	for _, st := range sessions {
+		if st.Status == model.StocktakeOpen {
+			open = true
+		}
+	}
+
+	return Layout("/stocktakes", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			H2(Class("text-2xl font-bold text-gray-800"), Text("Stocktakes")),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			If(!open,
+				Form(
+					Method("POST"),
+					Action("/stocktakes"),
+					Class("flex items-end gap-3 bg-white border border-gray-200 rounded-lg p-4"),
+					Div(Class("flex-grow"),
+						Label(For("note"), Class("block text-sm text-gray-600 mb-1"), Text("Note")),
+						Input(Type("text"), ID("note"), Name("note"), Placeholder("e.g. month end, bar only"),
+							Class("w-full border border-gray-300 rounded p-2"),
+						),
+					),
+					Button(Type("submit"), Class("bg-indigo-600 text-white px-4 py-2 rounded hover:bg-indigo-700"), Text("Start stocktake")),
+				),
+			),
+
+			If(len(sessions) == 0, P(Class("text-gray-500"), Text("No stocktakes yet."))),
+			If(len(sessions) > 0,
+				Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+					THead(Class("bg-indigo-700 text-white"),
+						Tr(
+							Th(Class("px-4 py-2 text-left"), Text("Started")),
+							Th(Class("px-4 py-2 text-left"), Text("Note")),
+							Th(Class("px-4 py-2 text-left"), Text("By")),
+							Th(Class("px-4 py-2 text-left"), Text("Status")),
+							Th(Class("px-4 py-2 text-right"), Text("Net variance")),
+						),
+					),
+					TBody(
+						Map(sessions, func(st model.Stocktake) Node {
+							net := "-"
+							if st.Status == model.StocktakeClosed {
+								net = FormatTZS(stocktake.Summarize(st.Lines).Net)
+							}
+							return Tr(
+								Td(Class("px-4 py-2 border-t"),
+									A(Href("/stocktakes/"+st.ID), Class("text-indigo-600 hover:underline"), Text(formatTimestamp(st.Created))),
+								),
+								Td(Class("px-4 py-2 border-t"), Text(st.Note)),
+								Td(Class("px-4 py-2 border-t"), Text(st.Expand.OpenedBy.Username)),
+								Td(Class("px-4 py-2 border-t capitalize"), Text(st.Status)),
+								Td(Class("px-4 py-2 border-t text-right"), Text(net)),
+							)
+						}),
+					),
+				),
+			),
+		),
+	)
+}
+
+// StocktakePage renders /stocktakes/{id}. While the session is open it shows
+// a counting form and a preview of the variance so far; once closed it shows
+// the variance report that was applied to stock.
+//
+// Parameters:
+//   - st: the stocktake session.
+//   - items: the stocked items that can be counted.
+//   - totals: the counted quantity of each item so far.
+//   - errorMsg: optional error message to display at the top of the page.
+func StocktakePage(st model.Stocktake, items []model.Item, totals map[string]int, errorMsg string) Node {
+	lines := st.Lines
+	if st.Status == model.StocktakeOpen {
+		lines = stocktake.Variance(items, totals)
+	}
+
+	return Layout("/stocktakes", true,
+		Div(
+			ID("main"),
+			Class("max-w-5xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between print:hidden"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Stocktake "+formatTimestamp(st.Created))),
+				Div(Class("flex items-center gap-2"),
+					If(st.Status == model.StocktakeClosed, Group{
+						printButton(),
+						reportLink("/stocktakes/"+st.ID+"/export.csv", "CSV"),
+						reportLink("/stocktakes/"+st.ID+"/export.xlsx", "Excel"),
+					}),
+					A(Href("/stocktakes"), Class("text-sm text-indigo-600 hover:underline"), Text("All stocktakes")),
+				),
+			),
+
+			If(st.Note != "", P(Class("text-gray-600"), Text(st.Note))),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			If(st.Status == model.StocktakeOpen, countForm(st.ID, items, totals)),
+
+			varianceReport(lines, st.Status == model.StocktakeOpen),
+
+			If(st.Status == model.StocktakeOpen,
+				Form(
+					Method("POST"),
+					Action("/stocktakes/"+st.ID+"/close"),
+					Attr("onsubmit", "return confirm('Close the stocktake? Stock of every counted item will be set to its count.')"),
+					Button(Type("submit"), Class("w-full bg-green-600 text-white font-semibold py-2 px-4 rounded hover:bg-green-700"),
+						Text("Close and apply adjustments"),
+					),
+				),
+			),
+			If(st.Status == model.StocktakeClosed,
+				P(Class("text-sm text-gray-500"), Textf("Closed %s. Items that were not counted kept their stock.", formatTimestamp(st.ClosedAt))),
+			),
+		),
+	)
+}
+
+// countForm lists the stocked items with an input per item for one counting
+// pass. Quantities entered add to what was counted before.
+func countForm(id string, items []model.Item, totals map[string]int) Node {
+	return Form(
+		Method("POST"),
+		Action("/stocktakes/"+id+"/counts"),
+		Class("bg-white border border-gray-200 rounded-lg p-4 space-y-3"),
+		P(Class("text-sm text-gray-600"), Text("Enter what you counted in this pass. Leave items you did not count blank; counts of the same item add up.")),
+		Table(Class("min-w-full"),
+			THead(Class("text-gray-700"),
+				Tr(
+					Th(Class("px-2 py-1 text-left"), Text("Item")),
+					Th(Class("px-2 py-1 text-right"), Text("Counted so far")),
+					Th(Class("px-2 py-1 text-right"), Text("This pass")),
+				),
+			),
+			TBody(
+				Map(items, func(it model.Item) Node {
+					counted := "-"
+					if n, ok := totals[it.ID]; ok {
+						counted = fmt.Sprint(n)
+					}
+					return Tr(
+						Td(Class("px-2 py-1 border-t capitalize"), Text(it.Name)),
+						Td(Class("px-2 py-1 border-t text-right"), Text(counted)),
+						Td(Class("px-2 py-1 border-t text-right"),
+							Input(Type("number"), Name("count-"+it.ID), Min("0"), Step("1"), Class("w-24 border border-gray-300 rounded p-1 text-right")),
+						),
+					)
+				}),
+			),
+		),
+		Button(Type("submit"), Class("bg-indigo-600 text-white px-4 py-2 rounded hover:bg-indigo-700"), Text("Save counts")),
+	)
+}
+
+// varianceReport lists counted items against their recorded stock, valued at
+// cost, with the totals above the table.
+func varianceReport(lines []model.VarianceLine, preview bool) Node {
+	sum := stocktake.Summarize(lines)
+
+	title := "Variance"
+	if preview {
+		title = "Variance so far"
+	}
+
+	return Div(Class("space-y-4"),
+		H3(Class("text-lg font-semibold text-gray-700"), Text(title)),
+		Div(Class("grid grid-cols-2 sm:grid-cols-4 gap-4"),
+			statCard("Items counted", fmt.Sprint(sum.Counted)),
+			statCard(fmt.Sprintf("Over (%d)", sum.Over), FormatTZS(sum.Gain)),
+			statCard(fmt.Sprintf("Short (%d)", sum.Short), FormatTZS(sum.Loss)),
+			statCard("Net", FormatTZS(sum.Net)),
+		),
+		If(len(lines) == 0, P(Class("text-gray-500"), Text("Nothing counted yet."))),
+		If(len(lines) > 0,
+			Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+				THead(Class("bg-gray-100 text-gray-700"),
+					Tr(
+						Th(Class("px-4 py-2 text-left"), Text("Item")),
+						Th(Class("px-4 py-2 text-right"), Text("Expected")),
+						Th(Class("px-4 py-2 text-right"), Text("Counted")),
+						Th(Class("px-4 py-2 text-right"), Text("Variance")),
+						Th(Class("px-4 py-2 text-right"), Text("Cost")),
+						Th(Class("px-4 py-2 text-right"), Text("Value")),
+					),
+				),
+				TBody(
+					Map(lines, func(l model.VarianceLine) Node {
+						return Tr(
+							Td(Class("px-4 py-2 border-t capitalize"), Text(l.Name)),
+							Td(Class("px-4 py-2 border-t text-right"), Text(fmt.Sprint(l.Expected))),
+							Td(Class("px-4 py-2 border-t text-right"), Text(fmt.Sprint(l.Counted))),
+							Td(
+								Classes{
+									"px-4 py-2 border-t text-right font-mono": true,
+									"text-green-700": l.Variance > 0,
+									"text-red-700":   l.Variance < 0,
+								},
+								Text(signedAmount(float64(l.Variance))),
+							),
+							Td(Class("px-4 py-2 border-t text-right"), Text(FormatTZS(l.Cost))),
+							Td(Class("px-4 py-2 border-t text-right"), Text(FormatTZS(l.Value))),
+						)
+					}),
+				),
+			),
+		),
+	)
+}
@@ -0,0 +1,76 @@
+package html
+
+import (
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// SuppliersPage renders the /suppliers page, where suppliers are added,
+// edited in place and deleted.
+//
+// Parameters:
+//   - suppliers: all suppliers by name.
+//   - errorMsg: optional error message to display above the list.
+func SuppliersPage(suppliers []model.Supplier, errorMsg string) Node {
+	return Layout("/suppliers", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Suppliers")),
+				reportLink("/reorder", "Reorder list"),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Div(Class("space-y-3"),
+				If(len(suppliers) == 0, P(Class("text-gray-500"), Text("No suppliers yet."))),
+				Map(suppliers, func(s model.Supplier) Node {
+					return Div(Class("flex items-center gap-3 bg-white border border-gray-200 rounded-lg p-3"),
+						Form(
+							Method("POST"),
+							Action("/suppliers/"+s.ID),
+							Class("flex flex-grow items-center gap-3"),
+							supplierFields(s),
+							Button(Type("submit"), Class("text-sm bg-indigo-600 text-white px-3 py-2 rounded hover:bg-indigo-700"), Text("Save")),
+						),
+						Form(
+							Method("POST"),
+							Action("/suppliers/"+s.ID+"/delete"),
+							Attr("onsubmit", "return confirm('Delete this supplier?')"),
+							Button(Type("submit"), Class("text-sm border border-red-400 text-red-700 px-3 py-2 rounded hover:bg-red-50"), Text("Delete")),
+						),
+					)
+				}),
+			),
+
+			H3(Class("text-lg font-semibold text-gray-700"), Text("Add Supplier")),
+			Form(
+				Method("POST"),
+				Action("/suppliers"),
+				Class("flex items-center gap-3 bg-white border border-gray-200 rounded-lg p-3"),
+				supplierFields(model.Supplier{}),
+				Button(Type("submit"), Class("text-sm bg-green-600 text-white px-3 py-2 rounded hover:bg-green-700"), Text("Add")),
+			),
+		),
+	)
+}
+
+// supplierFields are the inputs shared by the add and edit forms.
+func supplierFields(s model.Supplier) Node {
+	return Group([]Node{
+		Input(Type("text"), Name("name"), Value(s.Name), Placeholder("Name"), Required(),
+			Class("flex-grow border border-gray-300 rounded p-2"),
+		),
+		Input(Type("tel"), Name("phone"), Value(s.Phone), Placeholder("Phone"),
+			Class("w-40 border border-gray-300 rounded p-2"),
+		),
+		Input(Type("email"), Name("email"), Value(s.Email), Placeholder("Email"),
+			Class("w-56 border border-gray-300 rounded p-2"),
+		),
+	})
+}
@@ -0,0 +1,162 @@
+package html
+
+import (
+	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/internal/tab"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// TabsPage renders /tabs: the open tabs with their rounds so far and
+// running totals.
+//
+// Parameters:
+//   - tabs: open tabs, already sorted.
+//   - tables: all tables, to name tabs at a table.
+//   - by: how the tabs are listed, [tab.ByTable] or [tab.ByCustomer].
+//   - errorMsg: optional error message to display above the list.
+func TabsPage(tabs []model.Order, tables []model.Table, by, errorMsg string) Node {
+	byID := tablesByID(tables)
+
+	return Layout("/tabs", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Open Tabs")),
+				Div(Class("flex gap-2"),
+					tabSortLink(tab.ByTable, "By table", by),
+					tabSortLink(tab.ByCustomer, "By name", by),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			If(len(tabs) == 0, P(Class("text-gray-500"), Text("No open tabs. Open one from the POS."))),
+
+			If(len(tabs) > 0,
+				Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+					THead(Class("bg-gray-100 text-gray-700 text-left"),
+						Tr(
+							Th(Class("px-4 py-2"), Text("Tab")),
+							Th(Class("px-4 py-2"), Text("Opened")),
+							Th(Class("px-4 py-2 text-right"), Text("Rounds")),
+							Th(Class("px-4 py-2 text-right"), Text("Total")),
+							Th(Class("px-4 py-2")),
+						),
+					),
+					TBody(
+						Map(tabs, func(o model.Order) Node {
+							return Tr(Class("border-t"),
+								Td(Class("px-4 py-2"), A(Href("/tabs/"+o.ID), Class("text-indigo-600 hover:underline font-medium"), Text(tab.Label(o, byID)))),
+								Td(Class("px-4 py-2 text-gray-600"), Text(formatTimestamp(o.CreatedAt))),
+								Td(Class("px-4 py-2 text-right"), Textf("%d", len(tab.Rounds(o)))),
+								Td(Class("px-4 py-2 text-right"), Text(FormatTZS(o.TotalCost))),
+								Td(Class("px-4 py-2 text-right"),
+									A(Href("/orders/pos?tab="+o.ID), Class("text-sm text-green-700 font-semibold hover:underline"), Text("Add round")),
+								),
+							)
+						}),
+					),
+				),
+			),
+		),
+	)
+}
+
+// tabSortLink switches the order tabs are listed in.
+func tabSortLink(by, label, active string) Node {
+	return A(Href("/tabs?by="+by),
+		Classes{
+			"px-3 py-1 rounded-full text-sm border":           true,
+			"bg-indigo-600 text-white border-indigo-600":      by == active,
+			"text-gray-700 border-gray-300 hover:bg-gray-100": by != active,
+		},
+		Text(label),
+	)
+}
+
+// TabPage renders /tabs/{id}: an open tab round by round, with a link to
+// add the next round and a form to close the tab with payment.
+func TabPage(o model.Order, tables []model.Table, errorMsg string) Node {
+	byID := tablesByID(tables)
+
+	return Layout("/tabs", true,
+		Div(
+			ID("main"),
+			Class("max-w-2xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text(tab.Label(o, byID))),
+				reportLink("/tabs", "Open Tabs"),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Dl(Class("grid grid-cols-2 gap-x-4 gap-y-1 text-gray-700"),
+				Dt(Text("Opened")), Dd(Class("text-right"), Text(formatTimestamp(o.CreatedAt))),
+				If(o.Table != "", Group{
+					Dt(Text("Table")),
+					Dd(Class("text-right"), A(Href("/tables/"+o.Table), Class("text-indigo-600 hover:underline"), Text(tab.Label(model.Order{Table: o.Table}, byID)))),
+				}),
+			),
+
+			If(len(o.Items) == 0, P(Class("text-gray-500"), Text("Nothing ordered yet."))),
+			Map(tab.Rounds(o), func(r tab.Round) Node {
+				return Section(Class("bg-white border border-gray-200 rounded-lg p-4 space-y-2"),
+					Div(Class("flex justify-between font-semibold text-gray-800"),
+						H3(Textf("Round %d", r.Number)),
+						Span(Text(FormatTZS(r.Total))),
+					),
+					Ul(Class("divide-y"),
+						Map(r.Lines, func(l model.Item) Node {
+							return Li(Class("py-1 flex justify-between gap-4"),
+								Div(
+									Span(Class("capitalize"), Textf("%d × %s", l.Quantity, l.Name)),
+									OrderLineDetails(l),
+								),
+								Span(Class("text-gray-600"), Text(FormatTZS(compute.LineTotal(l)))),
+							)
+						}),
+					),
+				)
+			}),
+
+			Div(Class("flex justify-between text-xl font-semibold text-gray-800 border-t pt-3"),
+				Span(Text("Total")),
+				Span(Text(FormatTZS(compute.Subtotal(o.Items)))),
+			),
+
+			Div(Class("grid grid-cols-2 gap-3"),
+				A(Href("/orders/pos?tab="+o.ID),
+					Class("block text-center bg-green-600 text-white text-lg font-semibold py-3 rounded-lg hover:bg-green-700"),
+					Text("Add Round"),
+				),
+				A(Href("/orders/"+o.ID+"/split"),
+					Class("block text-center border-2 border-indigo-600 text-indigo-700 text-lg font-semibold py-3 rounded-lg hover:bg-indigo-50"),
+					Text("Split Bill"),
+				),
+			),
+
+			Form(
+				Method("POST"),
+				Action("/tabs/"+o.ID+"/close"),
+				Class("bg-white border border-gray-200 rounded-lg p-4 space-y-3"),
+				H3(Class("font-semibold text-gray-800"), Text("Close Tab")),
+				tenderSelect(""),
+				Button(Type("submit"),
+					If(len(o.Items) == 0, Disabled()),
+					Class("w-full bg-indigo-600 text-white font-semibold py-3 rounded-lg hover:bg-indigo-700 disabled:opacity-50"),
+					Text("Take Payment & Close"),
+				),
+			),
+		),
+	)
+}
@@ -0,0 +1,378 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/floor"
+	"github.com/rustacean-dev/possystem/model"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// FloorPlanPage renders /tables: every area as a grid with its tables in
+// place, coloured by status and showing how long guests have been seated.
+//
+// Parameters:
+//   - tables: all tables.
+//   - now: the time seating durations are measured against.
+//   - errorMsg: optional error message to display above the plan.
+func FloorPlanPage(tables []model.Table, now time.Time, errorMsg string) Node {
+	byID := tablesByID(tables)
+
+	return Layout("/tables", true,
+		Div(
+			ID("main"),
+			Class("max-w-6xl mx-auto mt-12 mb-12 space-y-8"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Floor Plan")),
+				Div(Class("flex gap-2"),
+					reportLink("/tables/layout", "Edit Layout"),
+				),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			If(len(tables) == 0,
+				P(Class("text-gray-500"),
+					Text("No tables yet. "),
+					A(Href("/tables/layout"), Class("text-indigo-600 hover:underline"), Text("Add tables")),
+				),
+			),
+
+			Map(floor.Areas(tables), func(a floor.Area) Node {
+				name := a.Name
+				if name == "" {
+					name = "Main"
+				}
+				return Section(Class("space-y-3"),
+					H3(Class("text-lg font-semibold text-gray-700"), Text(name)),
+					Div(
+						Class("grid gap-3 bg-white border border-gray-200 rounded-lg p-4"),
+						StyleAttr(fmt.Sprintf("grid-template-columns: repeat(%d, minmax(0, 1fr))", a.Cols)),
+						Map(a.Tables, func(t model.Table) Node { return tableTile(t, byID, now) }),
+					),
+				)
+			}),
+
+			Div(Class("flex gap-4 text-sm text-gray-600"),
+				Map(model.TableStatuses, func(s string) Node {
+					return Span(Class("flex items-center gap-1"), tableStatus(s))
+				}),
+			),
+		),
+	)
+}
+
+// tableTile renders one table on the floor plan, linking to its page.
+func tableTile(t model.Table, byID map[string]model.Table, now time.Time) Node {
+	status := floor.Status(t, byID)
+	main := floor.Main(t, byID)
+
+	return A(
+		Href("/tables/"+t.ID),
+		StyleAttr(fmt.Sprintf("grid-column: %d; grid-row: %d", max(t.X, 1), max(t.Y, 1))),
+		Classes{
+			"block rounded-lg border-2 p-3 text-center hover:shadow": true,
+			"bg-green-50 border-green-400":                           status == model.TableFree,
+			"bg-yellow-50 border-yellow-400":                         status == model.TableOccupied,
+			"bg-red-50 border-red-500":                               status == model.TableBillRequested,
+		},
+		P(Class("text-xl font-bold text-gray-800"), Textf("T%d", t.Number)),
+		P(Class("text-xs text-gray-500"), Textf("%d seats", t.Seats)),
+		If(main.ID != t.ID, P(Class("text-xs text-gray-600"), Textf("with T%d", main.Number))),
+		If(main.ID == t.ID && status != model.TableFree,
+			P(Class("text-sm font-mono text-gray-700"), Text(formatSeated(floor.Elapsed(t, now)))),
+		),
+	)
+}
+
+// TablePage renders /tables/{id}: the table's status and the orders of the
+// current sitting, with actions to seat guests, ask for the bill, clear
+// the table, move orders to another table and merge tables.
+//
+// Parameters:
+//   - t: the table shown.
+//   - tables: all tables, to move orders to and merge with.
+//   - orders: orders of the current sitting, oldest first.
+//   - now: the time the seating duration is measured against.
+//   - errorMsg: optional error message to display at the top.
+func TablePage(t model.Table, tables []model.Table, orders []model.Order, now time.Time, errorMsg string) Node {
+	byID := tablesByID(tables)
+	main := floor.Main(t, byID)
+
+	var total float64
+	for _, o := range orders {
+		total += o.TotalCost
+	}
+
+	var merged, others []model.Table
+	for _, other := range tables {
+		switch {
+		case other.MergedInto == t.ID:
+			merged = append(merged, other)
+		case other.ID != t.ID && other.MergedInto == "":
+			others = append(others, other)
+		}
+	}
+
+	return Layout("/tables", true,
+		Div(
+			ID("main"),
+			Class("max-w-3xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Textf("Table %d", t.Number)),
+				reportLink("/tables", "Floor Plan"),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			Dl(Class("grid grid-cols-2 gap-x-4 gap-y-1 text-gray-700"),
+				If(t.Area != "", Group{Dt(Text("Area")), Dd(Class("text-right"), Text(t.Area))}),
+				Dt(Text("Seats")), Dd(Class("text-right"), Textf("%d", t.Seats)),
+				Dt(Text("Status")), Dd(Class("text-right"), tableStatus(floor.Status(t, byID))),
+				If(main.ID == t.ID && t.Status != model.TableFree && t.Status != "",
+					Group{Dt(Text("Seated for")), Dd(Class("text-right font-mono"), Text(formatSeated(floor.Elapsed(t, now))))},
+				),
+			),
+
+			// A merged table is run from its main table
+			If(main.ID != t.ID,
+				Div(Class("bg-indigo-50 border border-indigo-200 rounded p-4 flex items-center justify-between"),
+					Span(Text("Merged into "), A(Href("/tables/"+main.ID), Class("font-semibold text-indigo-700 hover:underline"), Textf("table %d", main.Number))),
+					postButton("/tables/"+t.ID+"/split", "Split off", false),
+				),
+			),
+
+			If(main.ID == t.ID,
+				Group{
+					Div(Class("flex flex-wrap gap-2"),
+						A(Href("/orders/pos?table="+t.ID), Class("bg-green-600 text-white font-semibold px-4 py-2 rounded hover:bg-green-700"), Text("New Order")),
+						If(t.Status == "" || t.Status == model.TableFree, postButton("/tables/"+t.ID+"/seat", "Seat Guests", false)),
+						If(t.Status == model.TableOccupied, postButton("/tables/"+t.ID+"/bill", "Request Bill", false)),
+						If(t.Status == model.TableOccupied || t.Status == model.TableBillRequested, postButton("/tables/"+t.ID+"/clear", "Clear Table", true)),
+					),
+
+					tableOrders(t, orders, others, total),
+
+					If(len(merged) > 0,
+						P(Class("text-gray-700"),
+							Text("Merged with "),
+							Group(joinTables(merged)),
+						),
+					),
+
+					If(len(others) > 0,
+						Form(
+							Method("POST"),
+							Action("/tables/"+t.ID+"/merge"),
+							Class("flex items-center gap-2"),
+							Label(For("merge"), Class("text-gray-700"), Text("Merge in table")),
+							tableSelect("merge", others),
+							Button(Type("submit"), Class("border border-gray-300 px-3 py-2 rounded hover:bg-gray-100"), Text("Merge")),
+						),
+					),
+				},
+			),
+		),
+	)
+}
+
+// tableOrders lists the orders of a sitting, each with a form to move it to
+// another table.
+func tableOrders(t model.Table, orders []model.Order, others []model.Table, total float64) Node {
+	return Div(Class("space-y-2"),
+		H3(Class("text-lg font-semibold text-gray-700"), Text("Orders")),
+		If(len(orders) == 0, P(Class("text-gray-500"), Text("No orders for this sitting yet."))),
+		If(len(orders) > 0,
+			Table(Class("min-w-full bg-white border border-gray-200 rounded-md overflow-hidden"),
+				TBody(
+					Map(orders, func(o model.Order) Node {
+						return Tr(Class("border-t"),
+							Td(Class("px-4 py-2"),
+								A(Href("/orders/"+o.ID), Class("text-indigo-600 hover:underline font-mono"), Text("#"+shortID(o.ID))),
+								If(o.Status == model.OrderStatusOpen,
+									A(Href("/tabs/"+o.ID), Class("ml-2 text-xs px-2 py-0.5 rounded-full bg-amber-100 text-amber-800"), Text("open tab")),
+								),
+							),
+							Td(Class("px-4 py-2 text-gray-600"), Text(formatTimestamp(o.CreatedAt))),
+							Td(Class("px-4 py-2 text-right"), Text(FormatTZS(o.TotalCost))),
+							Td(Class("px-4 py-2 text-right"),
+								If(len(others) > 0,
+									Form(
+										Method("POST"),
+										Action("/orders/"+o.ID+"/table"),
+										Class("flex justify-end gap-2"),
+										tableSelect("table", others),
+										Button(Type("submit"), Class("text-sm border border-gray-300 px-2 py-1 rounded hover:bg-gray-100"), Text("Move")),
+									),
+								),
+							),
+						)
+					}),
+					Tr(Class("border-t font-semibold"),
+						Td(Class("px-4 py-2"), Text("Total")),
+						Td(),
+						Td(Class("px-4 py-2 text-right"), Text(FormatTZS(total))),
+						Td(),
+					),
+				),
+			),
+		),
+	)
+}
+
+// TableLayoutPage renders /tables/layout, where tables are added, placed on
+// the floor plan grid and removed.
+func TableLayoutPage(tables []model.Table, errorMsg string) Node {
+	next := model.Table{Number: 1, Seats: 4, X: 1, Y: 1}
+	for _, t := range tables {
+		next.Number = max(next.Number, t.Number+1)
+	}
+
+	return Layout("/tables", true,
+		Div(
+			ID("main"),
+			Class("max-w-4xl mx-auto mt-12 mb-12 space-y-6"),
+
+			Div(Class("flex items-center justify-between"),
+				H2(Class("text-2xl font-bold text-gray-800"), Text("Table Layout")),
+				reportLink("/tables", "Floor Plan"),
+			),
+
+			If(errorMsg != "",
+				Div(Class("bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded"), Text(errorMsg)),
+			),
+
+			P(Class("text-sm text-gray-500"), Text("Column and row place a table on the grid of its area, counting from 1 at the top left.")),
+
+			Div(Class("space-y-3"),
+				If(len(tables) == 0, P(Class("text-gray-500"), Text("No tables yet."))),
+				Map(tables, func(t model.Table) Node {
+					return Div(Class("flex items-center gap-3 bg-white border border-gray-200 rounded-lg p-3"),
+						Form(
+							Method("POST"),
+							Action("/tables/"+t.ID),
+							Class("flex flex-grow items-center gap-2"),
+							tableFields(t),
+							Button(Type("submit"), Class("text-sm bg-indigo-600 text-white px-3 py-2 rounded hover:bg-indigo-700"), Text("Save")),
+						),
+						Form(
+							Method("POST"),
+							Action("/tables/"+t.ID+"/delete"),
+							Attr("onsubmit", "return confirm('Delete this table?')"),
+							Button(Type("submit"), Class("text-sm border border-red-400 text-red-700 px-3 py-2 rounded hover:bg-red-50"), Text("Delete")),
+						),
+					)
+				}),
+			),
+
+			H3(Class("text-lg font-semibold text-gray-700"), Text("Add Table")),
+			Form(
+				Method("POST"),
+				Action("/tables/layout"),
+				Class("flex items-center gap-2 bg-white border border-gray-200 rounded-lg p-3"),
+				tableFields(next),
+				Button(Type("submit"), Class("text-sm bg-green-600 text-white px-3 py-2 rounded hover:bg-green-700"), Text("Add")),
+			),
+		),
+	)
+}
+
+// tableFields are the inputs shared by the add and edit forms.
+func tableFields(t model.Table) Node {
+	number := func(name, title string, v int) Node {
+		return Input(Type("number"), Name(name), Value(fmt.Sprint(v)), Title(title), Min("1"), Step("1"), Required(),
+			Class("w-20 border border-gray-300 rounded p-2"),
+		)
+	}
+	return Group([]Node{
+		Span(Class("text-gray-500"), Text("T")),
+		number("number", "Number", t.Number),
+		number("seats", "Seats", t.Seats),
+		Input(Type("text"), Name("area"), Value(t.Area), Placeholder("Area, e.g. Terrace"),
+			Class("flex-grow border border-gray-300 rounded p-2"),
+		),
+		number("x", "Column", t.X),
+		number("y", "Row", t.Y),
+	})
+}
+
+// tableSelect lets the user pick one of the tables.
+func tableSelect(name string, tables []model.Table) Node {
+	return Select(Name(name), Class("border border-gray-300 rounded p-1"),
+		Map(tables, func(t model.Table) Node {
+			return Option(Value(t.ID), Textf("T%d", t.Number))
+		}),
+	)
+}
+
+// tableStatus renders a table status as a coloured pill.
+func tableStatus(status string) Node {
+	return Span(
+		Classes{
+			"px-2 py-0.5 rounded-full text-sm": true,
+			"bg-green-100 text-green-800":      status == model.TableFree,
+			"bg-yellow-100 text-yellow-800":    status == model.TableOccupied,
+			"bg-red-100 text-red-800":          status == model.TableBillRequested,
+		},
+		Text(purchaseStatusLabel(status)),
+	)
+}
+
+// postButton renders a form posting to action with a single button.
+func postButton(action, label string, confirm bool) Node {
+	return Form(
+		Method("POST"),
+		Action(action),
+		If(confirm, Attr("onsubmit", "return confirm('"+label+"?')")),
+		Button(Type("submit"), Class("border border-gray-300 bg-white px-4 py-2 rounded hover:bg-gray-100"), Text(label)),
+	)
+}
+
+// joinTables links to each of the tables, comma-separated.
+func joinTables(tables []model.Table) []Node {
+	var nodes []Node
+	for i, t := range tables {
+		if i > 0 {
+			nodes = append(nodes, Text(", "))
+		}
+		nodes = append(nodes, A(Href("/tables/"+t.ID), Class("text-indigo-600 hover:underline"), Textf("T%d", t.Number)))
+	}
+	return nodes
+}
+
+// tablesByID indexes tables by ID.
+func tablesByID(tables []model.Table) map[string]model.Table {
+	byID := make(map[string]model.Table, len(tables))
+	for _, t := range tables {
+		byID[t.ID] = t
+	}
+	return byID
+}
+
+// formatSeated formats how long guests have been seated, e.g. "1h 05m".
+func formatSeated(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	if h == 0 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh %02dm", h, m)
+}
+
+// tableLabel names a table for other pages, e.g. "T5" or "T5 (Terrace)".
+func tableLabel(t model.Table) string {
+	if strings.TrimSpace(t.Area) == "" {
+		return fmt.Sprintf("T%d", t.Number)
+	}
+	return fmt.Sprintf("T%d (%s)", t.Number, t.Area)
+}
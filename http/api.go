@@ -0,0 +1,266 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rustacean-dev/possystem/internal/api"
+	"github.com/rustacean-dev/possystem/internal/apikey"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// APIRoutes registers the versioned JSON API under /api/v1, for the mobile
+// app, back-office scripts and third-party integrations. Every response is
+// an [api.Response] envelope. Clients send a PocketBase token from logging
+// in, or an API key, as "Authorization: Bearer <token>"; keys may only call
+// the routes their scopes allow. The spec is served at /swagger.
+func APIRoutes(r chi.Router) {
+	r.Route("/api/v1", func(r chi.Router) {
+		r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+			writeAPIError(w, http.StatusNotFound, "No such endpoint")
+		})
+		r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+			writeAPIError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		})
+
+		r.Post("/auth/login", apiLogin)
+
+		r.Group(func(r chi.Router) {
+			r.Use(apiAuth)
+
+			r.Post("/auth/refresh", apiRefresh)
+
+			r.With(apiScope(apikey.ItemsRead)).Get("/items", apiListItems)
+			r.With(apiScope(apikey.ItemsWrite)).Post("/items", apiCreateItem)
+			r.With(apiScope(apikey.ItemsRead)).Get("/items/{id}", apiGetItem)
+			r.With(apiScope(apikey.ItemsWrite)).Put("/items/{id}", apiUpdateItem)
+			r.With(apiScope(apikey.ItemsWrite)).Delete("/items/{id}", apiArchiveItem)
+
+			r.With(apiScope(apikey.OrdersRead)).Get("/orders", apiListOrders)
+			r.With(apiScope(apikey.OrdersWrite)).Post("/orders", apiCreateOrder)
+			r.With(apiScope(apikey.OrdersRead)).Get("/orders/{id}", apiGetOrder)
+			r.With(apiScope(apikey.OrdersWrite)).Put("/orders/{id}/status", apiSetOrderStatus)
+			r.With(apiScope(apikey.PaymentsRead)).Get("/orders/{id}/payments", apiListPayments)
+
+			r.With(apiScope(apikey.PaymentsRead)).Get("/payments/{id}", apiGetPayment)
+			r.With(apiScope(apikey.PaymentsWrite)).Post("/payments/{id}/pay", apiPayPayment)
+		})
+	})
+}
+
+// serviceToken logs in as the service user API keys are looked up as, and
+// keyUserToken as the user requests made with them act as. That user is
+// not an admin, so a key cannot reach what only admins may. Either is nil
+// when its user is not set up, and API keys are refused.
+var serviceToken, keyUserToken func(ctx context.Context) (string, error)
+
+// apiTokenKey is the request context key of the caller's PocketBase token.
+type apiTokenKey struct{}
+
+// apiKeyKey is the request context key of the API key a request was made
+// with, if any.
+type apiKeyKey struct{}
+
+// apiAuth lets requests with a PocketBase token or an API key in the
+// Authorization header through, and hands the token on in the request
+// context. Requests with an API key go on with the key user's token.
+// Token signatures are left for PocketBase to check on every call the
+// token is used for.
+func apiAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeAPIError(w, http.StatusUnauthorized, "Send a token or API key as \"Authorization: Bearer <token>\"")
+			return
+		}
+
+		ctx := r.Context()
+		if apikey.IsKey(token) {
+			k, service, status, msg := checkAPIKey(ctx, token)
+			if msg != "" {
+				writeAPIError(w, status, msg)
+				return
+			}
+			ctx = context.WithValue(ctx, apiKeyKey{}, k)
+			token = service
+		} else if _, err := repository.TokenUserID(token); err != nil || repository.TokenExpired(token, time.Now()) {
+			writeAPIError(w, http.StatusUnauthorized, "The token is invalid or has expired")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, apiTokenKey{}, token)))
+	})
+}
+
+// checkAPIKey looks up and checks an API key, and records its use. It
+// returns the key and the key user's token, or the status and message to
+// refuse the request with.
+func checkAPIKey(ctx context.Context, key string) (model.APIKey, string, int, string) {
+	if serviceToken == nil || keyUserToken == nil {
+		return model.APIKey{}, "", http.StatusUnauthorized, "API keys are not enabled on this server"
+	}
+	prefix, err := apikey.ID(key)
+	if err != nil {
+		return model.APIKey{}, "", http.StatusUnauthorized, "The API key is invalid"
+	}
+	token, err := serviceToken(ctx)
+	if err != nil {
+		log.Println("Failed to log in as the service user:", err)
+		return model.APIKey{}, "", http.StatusBadGateway, "Failed to check the API key"
+	}
+
+	k, err := repository.GetAPIKeyByPrefix(prefix, token)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.APIKey{}, "", http.StatusUnauthorized, "The API key is invalid"
+	}
+	if err != nil {
+		return model.APIKey{}, "", http.StatusBadGateway, "Failed to check the API key"
+	}
+	now := time.Now()
+	if err := apikey.Check(k, key, now); err != nil {
+		if errors.Is(err, apikey.ErrMalformed) {
+			return model.APIKey{}, "", http.StatusUnauthorized, "The API key is invalid"
+		}
+		return model.APIKey{}, "", http.StatusUnauthorized, "The API key has been revoked or has expired"
+	}
+
+	if apikey.TouchDue(k, now) {
+		if err := repository.TouchAPIKey(k.ID, now, token); err != nil {
+			log.Println("Failed to record API key use:", err)
+		}
+	}
+
+	userToken, err := keyUserToken(ctx)
+	if err != nil {
+		log.Println("Failed to log in as the API key user:", err)
+		return model.APIKey{}, "", http.StatusBadGateway, "Failed to check the API key"
+	}
+	return k, userToken, 0, ""
+}
+
+// apiScope refuses requests made with an API key that lacks scope. Logged
+// in users may call every route.
+func apiScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if k, ok := apiKey(r); ok && !apikey.Allows(k, scope) {
+				writeAPIError(w, http.StatusForbidden, "The API key lacks the "+scope+" scope")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiToken returns the token [apiAuth] let the request through with.
+func apiToken(r *http.Request) string {
+	token, _ := r.Context().Value(apiTokenKey{}).(string)
+	return token
+}
+
+// apiKey returns the API key the request was made with, if any.
+func apiKey(r *http.Request) (model.APIKey, bool) {
+	k, ok := r.Context().Value(apiKeyKey{}).(model.APIKey)
+	return k, ok
+}
+
+// writeAPI writes an API response as JSON.
+func writeAPI(w http.ResponseWriter, status int, res api.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Println("Failed to write API response:", err)
+	}
+}
+
+// writeAPIError writes a failed API response.
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPI(w, status, api.Fail(status, message))
+}
+
+// writeLookupError writes the failure of looking up what the URL names: not
+// found, or PocketBase failing.
+func writeLookupError(w http.ResponseWriter, err error, what string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		writeAPIError(w, http.StatusNotFound, what+" not found")
+		return
+	}
+	writeAPIError(w, http.StatusBadGateway, "Failed to fetch "+strings.ToLower(what))
+}
+
+// readAPI decodes a JSON request body into v. Unknown fields are refused,
+// so typos do not pass silently. It writes a failed response and returns
+// false if the body is invalid.
+func readAPI(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(io.LimitReader(r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "Invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// apiLogin godoc
+//
+//	@Summary		Log in
+//	@Description	Exchanges a username or email and password for a token to send as "Authorization: Bearer <token>".
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		model.LoginRequest	true	"Credentials"
+//	@Success		200		{object}	api.Response{data=model.LoginResponse}
+//	@Failure		400		{object}	api.Response{error=api.Error}
+//	@Failure		401		{object}	api.Response{error=api.Error}
+//	@Router			/auth/login [post]
+func apiLogin(w http.ResponseWriter, r *http.Request) {
+	var login model.LoginRequest
+	if !readAPI(w, r, &login) {
+		return
+	}
+	if login.Identity == "" || login.Password == "" {
+		writeAPIError(w, http.StatusBadRequest, "identity and password are required")
+		return
+	}
+
+	res, err := repository.LoginUser(login)
+	if err != nil {
+		writeAPIError(w, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+	writeAPI(w, http.StatusOK, api.OK(res))
+}
+
+// apiRefresh godoc
+//
+//	@Summary		Refresh token
+//	@Description	Exchanges a valid token for a new one with a later expiry. API keys have no refresh; they last until they expire or are revoked.
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	api.Response{data=model.LoginResponse}
+//	@Failure		401	{object}	api.Response{error=api.Error}
+//	@Failure		403	{object}	api.Response{error=api.Error}
+//	@Security		BearerAuth
+//	@Router			/auth/refresh [post]
+func apiRefresh(w http.ResponseWriter, r *http.Request) {
+	// The token of a key request is the key user's, not the caller's
+	if _, ok := apiKey(r); ok {
+		writeAPIError(w, http.StatusForbidden, "API keys cannot be refreshed")
+		return
+	}
+	res, err := repository.RefreshAuth(apiToken(r))
+	if err != nil {
+		writeAPIError(w, http.StatusUnauthorized, "The token cannot be refreshed; log in again")
+		return
+	}
+	writeAPI(w, http.StatusOK, api.OK(res))
+}
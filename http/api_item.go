@@ -0,0 +1,241 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rustacean-dev/possystem/internal/analytics"
+	"github.com/rustacean-dev/possystem/internal/api"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// apiListItems godoc
+//
+//	@Summary		List items
+//	@Description	One page of the items on sale, by name unless sorted otherwise. Archived items are left out.
+//	@Tags			items
+//	@Produce		json
+//	@Param			q			query		string	false	"Search name, description and category"
+//	@Param			sort		query		string	false	"name, price, quantity or updated; prefix - for descending"
+//	@Param			stock		query		string	false	"Only items out of stock or low on stock"	Enums(out, low)
+//	@Param			page		query		int		false	"Page, from 1"								default(1)
+//	@Param			per_page	query		int		false	"Items per page, up to 200"					default(50)
+//	@Success		200			{object}	api.Response{data=[]model.Item,meta=api.Meta}
+//	@Failure		400			{object}	api.Response{error=api.Error}
+//	@Failure		401			{object}	api.Response{error=api.Error}
+//	@Failure		403			{object}	api.Response{error=api.Error}
+//	@Failure		502			{object}	api.Response{error=api.Error}
+//	@Security		BearerAuth
+//	@Router			/items [get]
+func apiListItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, perPage, err := api.ParsePage(query)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := repository.ListItems(model.ItemQuery{
+		Search:     strings.TrimSpace(query.Get("q")),
+		Sort:       query.Get("sort"),
+		Stock:      query.Get("stock"),
+		LowStockAt: analytics.LowStockThreshold,
+		Page:       page,
+		PerPage:    perPage,
+	}, apiToken(r))
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, "Failed to fetch items")
+		return
+	}
+
+	if items.Items == nil {
+		items.Items = []model.Item{}
+	}
+	writeAPI(w, http.StatusOK, api.List(items.Items, api.NewMeta(page, perPage, items.TotalItems)))
+}
+
+// apiGetItem godoc
+//
+//	@Summary	Get item
+//	@Tags		items
+//	@Produce	json
+//	@Param		id	path		string	true	"Item ID"
+//	@Success	200	{object}	api.Response{data=model.Item}
+//	@Failure	401	{object}	api.Response{error=api.Error}
+//	@Failure	403	{object}	api.Response{error=api.Error}
+//	@Failure	404	{object}	api.Response{error=api.Error}
+//	@Failure	502	{object}	api.Response{error=api.Error}
+//	@Security	BearerAuth
+//	@Router		/items/{id} [get]
+func apiGetItem(w http.ResponseWriter, r *http.Request) {
+	item, err := repository.GetItemByID(chi.URLParam(r, "id"), apiToken(r))
+	if err != nil {
+		writeLookupError(w, err, "Item")
+		return
+	}
+	writeAPI(w, http.StatusOK, api.OK(item))
+}
+
+// apiCreateItem godoc
+//
+//	@Summary		Create item
+//	@Description	Adds an item to the menu. Its quantity is recorded as opening stock. Names, SKUs and barcodes must be unique.
+//	@Tags			items
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		api.ItemInput	true	"Item"
+//	@Success		201		{object}	api.Response{data=model.Item}
+//	@Failure		400		{object}	api.Response{error=api.Error}
+//	@Failure		401		{object}	api.Response{error=api.Error}
+//	@Failure		403		{object}	api.Response{error=api.Error}
+//	@Failure		409		{object}	api.Response{error=api.Error}
+//	@Failure		422		{object}	api.Response{error=api.Error}
+//	@Failure		502		{object}	api.Response{error=api.Error}
+//	@Security		BearerAuth
+//	@Router			/items [post]
+func apiCreateItem(w http.ResponseWriter, r *http.Request) {
+	var in api.ItemInput
+	if !readAPI(w, r, &in) {
+		return
+	}
+	item, err := in.Item()
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	token := apiToken(r)
+	if _, err := repository.GetItemByName(item.Name, token); err == nil {
+		writeAPIError(w, http.StatusConflict, fmt.Sprintf("An item is already called '%s'", item.Name))
+		return
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		writeAPIError(w, http.StatusBadGateway, "Failed to check for an existing item")
+		return
+	}
+	if msg := checkCodesUnique(item, token); msg != "" {
+		writeAPIError(w, http.StatusConflict, msg)
+		return
+	}
+
+	saved, err := repository.CreateItem(item, token)
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, "Failed to create item")
+		return
+	}
+	recordOpeningStock(saved, token)
+
+	writeAPI(w, http.StatusCreated, api.OK(saved))
+}
+
+// apiUpdateItem godoc
+//
+//	@Summary		Replace item
+//	@Description	Saves the fields in the body; options, recipe and reorder settings are kept, and so are the type prices unless type_prices is sent ({} clears them). Price changes go into the price history and quantity changes into the stock ledger. The quantity of a perishable item cannot be raised here, since its new lot needs an expiry date.
+//	@Tags			items
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Item ID"
+//	@Param			body	body		api.ItemInput	true	"Item"
+//	@Success		200		{object}	api.Response{data=model.Item}
+//	@Failure		400		{object}	api.Response{error=api.Error}
+//	@Failure		401		{object}	api.Response{error=api.Error}
+//	@Failure		403		{object}	api.Response{error=api.Error}
+//	@Failure		404		{object}	api.Response{error=api.Error}
+//	@Failure		409		{object}	api.Response{error=api.Error}
+//	@Failure		422		{object}	api.Response{error=api.Error}
+//	@Failure		502		{object}	api.Response{error=api.Error}
+//	@Security		BearerAuth
+//	@Router			/items/{id} [put]
+func apiUpdateItem(w http.ResponseWriter, r *http.Request) {
+	token := apiToken(r)
+	item, err := repository.GetItemByID(chi.URLParam(r, "id"), token)
+	if err != nil {
+		writeLookupError(w, err, "Item")
+		return
+	}
+
+	var in api.ItemInput
+	if !readAPI(w, r, &in) {
+		return
+	}
+	edit, err := in.Item()
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	// Names are unique, since new stock is merged into items by name
+	if edit.Name != item.Name {
+		if other, err := repository.GetItemByName(edit.Name, token); err == nil && other.ID != item.ID {
+			writeAPIError(w, http.StatusConflict, fmt.Sprintf("Another item is already called '%s'", edit.Name))
+			return
+		}
+	}
+
+	oldPrice, oldQuantity := item.Price, item.Quantity
+	item.Name = edit.Name
+	item.Description = edit.Description
+	item.Category = edit.Category
+	item.Price = edit.Price
+	item.Cost = edit.Cost
+	item.Quantity = edit.Quantity
+	item.SKU = edit.SKU
+	item.Barcodes = edit.Barcodes
+	if in.TypePrices != nil {
+		item.TypePrices = edit.TypePrices
+	}
+
+	if msg := perishableIncrease(item, item.Quantity-oldQuantity); msg != "" {
+		writeAPIError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+	if msg := checkCodesUnique(item, token); msg != "" {
+		writeAPIError(w, http.StatusConflict, msg)
+		return
+	}
+	if err := repository.UpdateItem(item, token); err != nil {
+		writeAPIError(w, http.StatusBadGateway, "Failed to save item")
+		return
+	}
+	if msg := recordItemEdit(item, oldPrice, oldQuantity, "Edited through the API", token); msg != "" {
+		writeAPIError(w, http.StatusBadGateway, msg)
+		return
+	}
+
+	writeAPI(w, http.StatusOK, api.OK(item))
+}
+
+// apiArchiveItem godoc
+//
+//	@Summary		Archive item
+//	@Description	Takes an item off sale. Past orders keep their lines; it can be restored from the item page.
+//	@Tags			items
+//	@Produce		json
+//	@Param			id	path		string	true	"Item ID"
+//	@Success		200	{object}	api.Response{data=model.Item}
+//	@Failure		401	{object}	api.Response{error=api.Error}
+//	@Failure		403	{object}	api.Response{error=api.Error}
+//	@Failure		404	{object}	api.Response{error=api.Error}
+//	@Failure		502	{object}	api.Response{error=api.Error}
+//	@Security		BearerAuth
+//	@Router			/items/{id} [delete]
+func apiArchiveItem(w http.ResponseWriter, r *http.Request) {
+	token := apiToken(r)
+	item, err := repository.GetItemByID(chi.URLParam(r, "id"), token)
+	if err != nil {
+		writeLookupError(w, err, "Item")
+		return
+	}
+	if err := repository.SetItemArchived(item.ID, true, token); err != nil {
+		writeAPIError(w, http.StatusBadGateway, "Failed to archive item")
+		return
+	}
+
+	item.Archived = true
+	writeAPI(w, http.StatusOK, api.OK(item))
+}
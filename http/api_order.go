@@ -0,0 +1,339 @@
+package http
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rustacean-dev/possystem/internal/api"
+	"github.com/rustacean-dev/possystem/internal/ordertype"
+	"github.com/rustacean-dev/possystem/internal/split"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// apiListOrders godoc
+//
+//	@Summary		List orders
+//	@Description	One page of orders, newest first. Days are in the server's time zone and both inclusive.
+//	@Tags			orders
+//	@Produce		json
+//	@Param			from		query		string	false	"First day, YYYY-MM-DD"
+//	@Param			to			query		string	false	"Last day, YYYY-MM-DD"
+//	@Param			status		query		string	false	"Order status"	Enums(open, pending, preparing, served, ready, out_for_delivery, completed, cancelled, refunded)
+//	@Param			type		query		string	false	"Order type"	Enums(counter, dine_in, takeaway, delivery)
+//	@Param			page		query		int		false	"Page, from 1"				default(1)
+//	@Param			per_page	query		int		false	"Orders per page, up to 200"	default(50)
+//	@Success		200			{object}	api.Response{data=[]model.Order,meta=api.Meta}
+//	@Failure		400			{object}	api.Response{error=api.Error}
+//	@Failure		401			{object}	api.Response{error=api.Error}
+//	@Failure		403			{object}	api.Response{error=api.Error}
+//	@Failure		502			{object}	api.Response{error=api.Error}
+//	@Security		BearerAuth
+//	@Router			/orders [get]
+func apiListOrders(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := api.ParsePage(r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	f := orderFilterFromRequest(r)
+	if err := repository.CheckOrderFilter(f); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if f.Status != "" && !slices.Contains(model.OrderStatuses, f.Status) {
+		writeAPIError(w, http.StatusBadRequest, "Unknown status")
+		return
+	}
+	if f.Type != ordertype.Counter && !ordertype.Valid(f.Type) {
+		writeAPIError(w, http.StatusBadRequest, "Unknown order type")
+		return
+	}
+
+	orders, err := repository.ListOrders(f, page, perPage, apiToken(r))
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, "Failed to fetch orders")
+		return
+	}
+
+	if orders.Items == nil {
+		orders.Items = []model.Order{}
+	}
+	writeAPI(w, http.StatusOK, api.List(orders.Items, api.NewMeta(page, perPage, orders.TotalItems)))
+}
+
+// apiGetOrder godoc
+//
+//	@Summary	Get order
+//	@Tags		orders
+//	@Produce	json
+//	@Param		id	path		string	true	"Order ID"
+//	@Success	200	{object}	api.Response{data=model.Order}
+//	@Failure	401	{object}	api.Response{error=api.Error}
+//	@Failure	403	{object}	api.Response{error=api.Error}
+//	@Failure	404	{object}	api.Response{error=api.Error}
+//	@Failure	502	{object}	api.Response{error=api.Error}
+//	@Security	BearerAuth
+//	@Router		/orders/{id} [get]
+func apiGetOrder(w http.ResponseWriter, r *http.Request) {
+	o, err := repository.GetOrderByID(chi.URLParam(r, "id"), apiToken(r))
+	if err != nil {
+		writeLookupError(w, err, "Order")
+		return
+	}
+	writeAPI(w, http.StatusOK, api.OK(o))
+}
+
+// apiCreateOrder godoc
+//
+//	@Summary		Place order
+//	@Description	Places an order as the POS does: prices and options come from the menu and the order type, stock is checked and taken, and the lines go to the kitchen. Tabs are opened from the POS only.
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		api.OrderInput	true	"Order"
+//	@Success		201		{object}	api.Response{data=model.Order}
+//	@Failure		400		{object}	api.Response{error=api.Error}
+//	@Failure		401		{object}	api.Response{error=api.Error}
+//	@Failure		403		{object}	api.Response{error=api.Error}
+//	@Failure		422		{object}	api.Response{error=api.Error}
+//	@Failure		502		{object}	api.Response{error=api.Error}
+//	@Security		BearerAuth
+//	@Router			/orders [post]
+func apiCreateOrder(w http.ResponseWriter, r *http.Request) {
+	var in api.OrderInput
+	if !readAPI(w, r, &in) {
+		return
+	}
+	if err := in.Check(); err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	// Orders for a table are dine-in, as on the POS
+	entered := model.Order{
+		Type:     in.Type,
+		Customer: strings.TrimSpace(in.Customer),
+		PickupAt: strings.TrimSpace(in.PickupAt),
+		Address:  strings.TrimSpace(in.Address),
+		Phone:    strings.TrimSpace(in.Phone),
+		Zone:     in.Zone,
+	}
+	if in.Table != "" {
+		entered.Type = model.OrderDineIn
+	}
+	fee := ""
+	if in.DeliveryFee != nil {
+		fee = strconv.FormatFloat(*in.DeliveryFee, 'f', -1, 64)
+	}
+	details, msg := orderType(entered, fee, time.Now())
+	if msg != "" {
+		writeAPIError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+
+	tender := in.Tender
+	if tender == "" {
+		tender = model.TenderCash
+	}
+	order, _, msg := placeOrder(newOrder{
+		Lines:   in.Lines,
+		Details: details,
+		Tender:  tender,
+		Table:   in.Table,
+	}, apiToken(r))
+	if msg != "" {
+		writeAPIError(w, placeOrderStatus(msg), msg)
+		return
+	}
+
+	writeAPI(w, http.StatusCreated, api.OK(order))
+}
+
+// placeOrderStatus is the status of a message from [placeOrder]: PocketBase
+// failing, or an order that cannot be placed as it is.
+func placeOrderStatus(msg string) int {
+	if strings.HasPrefix(msg, "Failed") {
+		return http.StatusBadGateway
+	}
+	return http.StatusUnprocessableEntity
+}
+
+// apiSetOrderStatus godoc
+//
+//	@Summary		Change order status
+//	@Description	Moves an order to any status its type allows. Deliveries are sent out and marked delivered on the dispatch board only, and open tabs can only be cancelled, since they are paid when closed.
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Order ID"
+//	@Param			body	body		api.StatusInput	true	"New status"
+//	@Success		200		{object}	api.Response{data=model.Order}
+//	@Failure		400		{object}	api.Response{error=api.Error}
+//	@Failure		401		{object}	api.Response{error=api.Error}
+//	@Failure		403		{object}	api.Response{error=api.Error}
+//	@Failure		404		{object}	api.Response{error=api.Error}
+//	@Failure		422		{object}	api.Response{error=api.Error}
+//	@Failure		502		{object}	api.Response{error=api.Error}
+//	@Security		BearerAuth
+//	@Router			/orders/{id}/status [put]
+func apiSetOrderStatus(w http.ResponseWriter, r *http.Request) {
+	token := apiToken(r)
+	o, err := repository.GetOrderByID(chi.URLParam(r, "id"), token)
+	if err != nil {
+		writeLookupError(w, err, "Order")
+		return
+	}
+
+	var in api.StatusInput
+	if !readAPI(w, r, &in) {
+		return
+	}
+	if msg := checkStatus(o, in.Status); msg != "" {
+		writeAPIError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+	if in.Status != o.Status {
+		if err := repository.SetOrderStatus(o, in.Status, token); err != nil {
+			writeAPIError(w, http.StatusBadGateway, "Failed to update status")
+			return
+		}
+		o.Status = in.Status
+	}
+
+	writeAPI(w, http.StatusOK, api.OK(o))
+}
+
+// apiListPayments godoc
+//
+//	@Summary		List payments of an order
+//	@Description	The parts of a split bill, paid or due. Orders that were not split have none.
+//	@Tags			payments
+//	@Produce		json
+//	@Param			id	path		string	true	"Order ID"
+//	@Success		200	{object}	api.Response{data=[]model.Payment}
+//	@Failure		401	{object}	api.Response{error=api.Error}
+//	@Failure		403	{object}	api.Response{error=api.Error}
+//	@Failure		404	{object}	api.Response{error=api.Error}
+//	@Failure		502	{object}	api.Response{error=api.Error}
+//	@Security		BearerAuth
+//	@Router			/orders/{id}/payments [get]
+func apiListPayments(w http.ResponseWriter, r *http.Request) {
+	token := apiToken(r)
+	o, err := repository.GetOrderByID(chi.URLParam(r, "id"), token)
+	if err != nil {
+		writeLookupError(w, err, "Order")
+		return
+	}
+	payments, err := repository.GetOrderPayments(o.ID, token)
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, "Failed to fetch payments")
+		return
+	}
+
+	if payments == nil {
+		payments = []model.Payment{}
+	}
+	writeAPI(w, http.StatusOK, api.OK(payments))
+}
+
+// apiGetPayment godoc
+//
+//	@Summary	Get payment
+//	@Tags		payments
+//	@Produce	json
+//	@Param		id	path		string	true	"Payment ID"
+//	@Success	200	{object}	api.Response{data=model.Payment}
+//	@Failure	401	{object}	api.Response{error=api.Error}
+//	@Failure	403	{object}	api.Response{error=api.Error}
+//	@Failure	404	{object}	api.Response{error=api.Error}
+//	@Failure	502	{object}	api.Response{error=api.Error}
+//	@Security	BearerAuth
+//	@Router		/payments/{id} [get]
+func apiGetPayment(w http.ResponseWriter, r *http.Request) {
+	p, err := repository.GetPaymentByID(chi.URLParam(r, "id"), apiToken(r))
+	if err != nil {
+		writeLookupError(w, err, "Payment")
+		return
+	}
+	writeAPI(w, http.StatusOK, api.OK(p))
+}
+
+// apiPayPayment godoc
+//
+//	@Summary		Pay part of a split bill
+//	@Description	Takes payment for one part. Paying the last part of an open tab closes it.
+//	@Tags			payments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Payment ID"
+//	@Param			body	body		api.PayInput	true	"Tender"
+//	@Success		200		{object}	api.Response{data=model.Payment}
+//	@Failure		400		{object}	api.Response{error=api.Error}
+//	@Failure		401		{object}	api.Response{error=api.Error}
+//	@Failure		403		{object}	api.Response{error=api.Error}
+//	@Failure		404		{object}	api.Response{error=api.Error}
+//	@Failure		409		{object}	api.Response{error=api.Error}
+//	@Failure		422		{object}	api.Response{error=api.Error}
+//	@Failure		502		{object}	api.Response{error=api.Error}
+//	@Security		BearerAuth
+//	@Router			/payments/{id}/pay [post]
+func apiPayPayment(w http.ResponseWriter, r *http.Request) {
+	token := apiToken(r)
+	p, err := repository.GetPaymentByID(chi.URLParam(r, "id"), token)
+	if err != nil {
+		writeLookupError(w, err, "Payment")
+		return
+	}
+
+	var in api.PayInput
+	if !readAPI(w, r, &in) {
+		return
+	}
+	if !api.ValidTender(in.Tender) {
+		writeAPIError(w, http.StatusUnprocessableEntity, "Unknown tender")
+		return
+	}
+	if p.Status == model.PaymentPaid {
+		writeAPIError(w, http.StatusConflict, "This part is already paid")
+		return
+	}
+	o, err := repository.GetOrderByID(p.Order, token)
+	if err != nil {
+		writeLookupError(w, err, "Order")
+		return
+	}
+
+	tender := in.Tender
+	if tender == "" {
+		tender = model.TenderCash
+	}
+	if err := repository.PayPayment(p, tender, token); err != nil {
+		writeAPIError(w, http.StatusBadGateway, "Failed to take payment")
+		return
+	}
+
+	payments, err := repository.GetOrderPayments(o.ID, token)
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, "Payment taken, but failed to fetch the other parts")
+		return
+	}
+	if split.Settled(payments) && o.Status == model.OrderStatusOpen {
+		if err := repository.SettleSplitOrder(o, token); err != nil {
+			writeAPIError(w, http.StatusBadGateway, "Every part is paid, but the tab failed to close")
+			return
+		}
+	}
+
+	for _, paid := range payments {
+		if paid.ID == p.ID {
+			p = paid
+		}
+	}
+	writeAPI(w, http.StatusOK, api.OK(p))
+}
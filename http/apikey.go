@@ -0,0 +1,133 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/apikey"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// APIKeyRoutes registers the settings page where admins create and revoke
+// the API keys machine clients call the JSON API with.
+func APIKeyRoutes(r chi.Router) {
+	// GET /settings/api-keys – List API keys with a form to create one
+	r.Get("/settings/api-keys", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return apiKeysPage(cookie.Value, "", ""), nil
+	}))
+
+	// POST /settings/api-keys – Create an API key and show its secret once
+	r.Post("/settings/api-keys", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+		if !isAdmin(cookie.Value) {
+			return apiKeysPage(cookie.Value, "", ""), nil
+		}
+
+		k, msg := apiKeyFromForm(r)
+		if msg != "" {
+			return apiKeysPage(cookie.Value, "", msg), nil
+		}
+		key, prefix, hash, err := apikey.New()
+		if err != nil {
+			return apiKeysPage(cookie.Value, "", "Failed to generate key"), nil
+		}
+		k.Prefix, k.Hash = prefix, hash
+		k.CreatedBy, _ = repository.TokenUserID(cookie.Value)
+		if err := repository.CreateAPIKey(k, cookie.Value); err != nil {
+			return apiKeysPage(cookie.Value, "", "Failed to save key"), nil
+		}
+
+		// Not redirected: the secret is in this response only
+		return apiKeysPage(cookie.Value, key, ""), nil
+	}))
+
+	// POST /settings/api-keys/{id}/revoke – Stop an API key from working
+	r.Post("/settings/api-keys/{id}/revoke", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+		if !isAdmin(cookie.Value) {
+			return apiKeysPage(cookie.Value, "", ""), nil
+		}
+
+		if err := repository.RevokeAPIKey(chi.URLParam(r, "id"), cookie.Value); err != nil {
+			return apiKeysPage(cookie.Value, "", "Failed to revoke key"), nil
+		}
+
+		http.Redirect(w, r, "/settings/api-keys", http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// isAdmin reports whether the user a token was issued to is an admin.
+func isAdmin(token string) bool {
+	id, err := repository.TokenUserID(token)
+	if err != nil {
+		return false
+	}
+	u, err := repository.GetUserByID(id, token)
+	return err == nil && u.Role == model.RoleAdmin
+}
+
+// apiKeysPage fetches the API keys for [html.APIKeysPage]. Users who are
+// not admins are told so instead.
+func apiKeysPage(token, newKey, msg string) Node {
+	now := time.Now()
+	if !isAdmin(token) {
+		return html.APIKeysPage(nil, "", false, now, "Only admins can manage API keys")
+	}
+	keys, err := repository.GetAPIKeys(token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch API keys"
+	}
+	return html.APIKeysPage(keys, newKey, true, now, msg)
+}
+
+// apiKeyFromForm reads a new API key's name, scopes and expiry from the
+// create form. It returns a user-facing message if the form is invalid.
+func apiKeyFromForm(r *http.Request) (model.APIKey, string) {
+	if err := r.ParseForm(); err != nil {
+		return model.APIKey{}, "Invalid form submission"
+	}
+
+	k := model.APIKey{Name: strings.TrimSpace(r.FormValue("name"))}
+	if k.Name == "" {
+		return k, "Please enter a name"
+	}
+	for _, s := range r.Form["scopes"] {
+		if !apikey.ValidScope(s) {
+			return k, "Unknown scope " + s
+		}
+		k.Scopes = append(k.Scopes, s)
+	}
+	if len(k.Scopes) == 0 {
+		return k, "Please pick at least one scope"
+	}
+	expires, err := apikey.Expiry(r.FormValue("expires"), time.Local, time.Now())
+	if err != nil {
+		return k, "Please enter a valid expiry: " + err.Error()
+	}
+	if !expires.IsZero() {
+		k.ExpiresAt = model.FormatTime(expires)
+	}
+	return k, ""
+}
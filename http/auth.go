@@ -3,6 +3,7 @@ package http
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/rustacean-dev/possystem/html"
 	"github.com/rustacean-dev/possystem/model"
@@ -73,3 +74,16 @@ func Auth(r chi.Router) {
 	})
 
 }
+
+// validToken reports whether a cookie token belongs to a signed-in user.
+// Pages check tokens by using them, since PocketBase refuses bad ones on
+// every call; event streams make no such call, so they ask PocketBase for
+// the user before subscribing.
+func validToken(token string) bool {
+	id, err := repository.TokenUserID(token)
+	if err != nil || repository.TokenExpired(token, time.Now()) {
+		return false
+	}
+	_, err = repository.GetUserByID(id, token)
+	return err == nil
+}
@@ -0,0 +1,130 @@
+package http
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// CategoryRoutes registers the menu category admin page.
+func CategoryRoutes(r chi.Router) {
+	// GET /categories – List categories with inline edit forms
+	r.Get("/categories", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		categories, err := repository.GetCategories(cookie.Value)
+		if err != nil {
+			return html.CategoriesPage(nil, "Failed to fetch categories"), nil
+		}
+
+		return html.CategoriesPage(categories, ""), nil
+	}))
+
+	// POST /categories – Add a category
+	r.Post("/categories", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		c, msg := categoryFromForm(r)
+		if msg == "" {
+			if err := repository.CreateCategory(c, cookie.Value); err != nil {
+				msg = "Failed to add category"
+			}
+		}
+		if msg != "" {
+			categories, _ := repository.GetCategories(cookie.Value)
+			return html.CategoriesPage(categories, msg), nil
+		}
+
+		http.Redirect(w, r, "/categories", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /categories/{id} – Save a category
+	r.Post("/categories/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		c, msg := categoryFromForm(r)
+		c.ID = chi.URLParam(r, "id")
+		if msg == "" {
+			if err := repository.UpdateCategory(c, cookie.Value); err != nil {
+				msg = "Failed to save category"
+			}
+		}
+		if msg != "" {
+			categories, _ := repository.GetCategories(cookie.Value)
+			return html.CategoriesPage(categories, msg), nil
+		}
+
+		http.Redirect(w, r, "/categories", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /categories/{id}/delete – Delete a category
+	r.Post("/categories/{id}/delete", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		if err := repository.DeleteCategory(chi.URLParam(r, "id"), cookie.Value); err != nil {
+			categories, _ := repository.GetCategories(cookie.Value)
+			return html.CategoriesPage(categories, "Failed to delete category. Move its items to another category first."), nil
+		}
+
+		http.Redirect(w, r, "/categories", http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// categoryFromForm reads a category from the add/edit form. It returns a
+// user-facing message if the form is invalid.
+func categoryFromForm(r *http.Request) (model.Category, string) {
+	if err := r.ParseForm(); err != nil {
+		return model.Category{}, "Invalid form submission"
+	}
+
+	c := model.Category{
+		Name:   strings.TrimSpace(r.FormValue("name")),
+		Colour: r.FormValue("colour"),
+	}
+	if c.Name == "" {
+		return c, "Please enter a name"
+	}
+
+	c.Station = r.FormValue("station")
+	if c.Station != "" && !slices.Contains(model.Stations, c.Station) {
+		return c, "Unknown kitchen station"
+	}
+
+	if s := r.FormValue("sort_order"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return c, "Sort order must be a whole number"
+		}
+		c.SortOrder = n
+	}
+
+	return c, ""
+}
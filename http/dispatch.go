@@ -0,0 +1,300 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/dispatch"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// DispatchRoutes registers the dispatch board, where ready delivery orders
+// are sent out with riders, the rider cash-up pages, and delivery zones.
+func DispatchRoutes(r chi.Router) {
+	// GET /dispatch – Delivery orders by stage, riders and the cash they hold
+	r.Get("/dispatch", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			if r.Header.Get("HX-Request") == "true" {
+				w.Header().Set("HX-Redirect", "/login")
+				return nil, nil
+			}
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return dispatchPage(cookie.Value, ""), nil
+	}))
+
+	// POST /dispatch/{id}/send – Hand a ready order to a rider
+	r.Post("/dispatch/{id}/send", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		o, err := repository.GetOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			return dispatchPage(cookie.Value, "Order not found"), nil
+		}
+		out, err := dispatch.Dispatch(o, r.FormValue("rider"), time.Now())
+		if err != nil {
+			return dispatchPage(cookie.Value, "Cannot dispatch order: "+err.Error()), nil
+		}
+		rider, err := repository.GetUserByID(out.Rider, cookie.Value)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return dispatchPage(cookie.Value, "Failed to look up rider"), nil
+		}
+		if err != nil || rider.Role != model.RoleRider {
+			return dispatchPage(cookie.Value, "Cannot dispatch order: only riders can take deliveries out"), nil
+		}
+		if err := repository.DispatchOrder(out, o.Status, cookie.Value); err != nil {
+			return dispatchPage(cookie.Value, "Failed to dispatch order"), nil
+		}
+
+		http.Redirect(w, r, "/dispatch", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /dispatch/{id}/delivered – Mark an order out for delivery as delivered
+	r.Post("/dispatch/{id}/delivered", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		o, err := repository.GetOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			return dispatchPage(cookie.Value, "Order not found"), nil
+		}
+		done, err := dispatch.Deliver(o, time.Now())
+		if err != nil {
+			return dispatchPage(cookie.Value, "Cannot mark order delivered: "+err.Error()), nil
+		}
+		if err := repository.DeliverOrder(done, o.Status, cookie.Value); err != nil {
+			return dispatchPage(cookie.Value, "Failed to mark order delivered"), nil
+		}
+
+		http.Redirect(w, r, "/dispatch", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// GET /riders/{id} – A rider's unsettled deliveries and past settlements
+	r.Get("/riders/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return riderPage(chi.URLParam(r, "id"), cookie.Value, ""), nil
+	}))
+
+	// POST /riders/{id}/settle – Record the cash a rider handed in
+	r.Post("/riders/{id}/settle", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		rider := chi.URLParam(r, "id")
+		counted, err := strconv.ParseFloat(strings.TrimSpace(r.FormValue("counted")), 64)
+		if err != nil {
+			return riderPage(rider, cookie.Value, "Please enter the cash handed in"), nil
+		}
+		orders, err := repository.GetUnsettledDeliveries(rider, cookie.Value)
+		if err != nil {
+			return riderPage(rider, cookie.Value, "Failed to fetch deliveries"), nil
+		}
+
+		settledBy, _ := repository.TokenUserID(cookie.Value)
+		s, err := dispatch.Settle(rider, orders, counted, r.FormValue("note"), settledBy)
+		if err != nil {
+			return riderPage(rider, cookie.Value, "Cannot settle: "+err.Error()), nil
+		}
+		if err := repository.SettleRider(s, cookie.Value); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				return riderPage(rider, cookie.Value, "Some of these deliveries have been settled already; check the settlements below and try again"), nil
+			}
+			return riderPage(rider, cookie.Value, "Failed to save settlement"), nil
+		}
+
+		http.Redirect(w, r, "/riders/"+rider, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// GET /zones – List delivery zones with inline edit forms
+	r.Get("/zones", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return zonesPage(cookie.Value, ""), nil
+	}))
+
+	// POST /zones – Add a delivery zone
+	r.Post("/zones", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		z, msg := zoneFromForm(r)
+		if msg == "" {
+			if err := repository.CreateZone(z, cookie.Value); err != nil {
+				msg = "Failed to add zone"
+			}
+		}
+		if msg != "" {
+			return zonesPage(cookie.Value, msg), nil
+		}
+
+		http.Redirect(w, r, "/zones", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /zones/{id} – Save a delivery zone
+	r.Post("/zones/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		z, msg := zoneFromForm(r)
+		z.ID = chi.URLParam(r, "id")
+		if msg == "" {
+			if err := repository.UpdateZone(z, cookie.Value); err != nil {
+				msg = "Failed to save zone"
+			}
+		}
+		if msg != "" {
+			return zonesPage(cookie.Value, msg), nil
+		}
+
+		http.Redirect(w, r, "/zones", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /zones/{id}/delete – Delete a delivery zone
+	r.Post("/zones/{id}/delete", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		if err := repository.DeleteZone(chi.URLParam(r, "id"), cookie.Value); err != nil {
+			return zonesPage(cookie.Value, "Failed to delete zone"), nil
+		}
+
+		http.Redirect(w, r, "/zones", http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// dispatchPage fetches the delivery orders, riders, zones and unsettled
+// cash for [html.DispatchPage].
+func dispatchPage(token, msg string) Node {
+	orders, err := repository.GetDeliveryOrders(token)
+	if err != nil {
+		return html.DispatchPage(dispatch.Board{}, nil, nil, nil, time.Now(), "Failed to fetch delivery orders")
+	}
+	riders, err := repository.GetRiders(token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch riders"
+	}
+	zones, err := repository.GetZones(token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch zones"
+	}
+
+	owed := map[string]float64{}
+	delivered, err := repository.GetUnsettledDeliveries("", token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch rider cash"
+	}
+	for _, o := range delivered {
+		owed[o.Rider] += o.Collected
+	}
+
+	return html.DispatchPage(dispatch.NewBoard(orders), riders, zones, owed, time.Now(), msg)
+}
+
+// riderPage fetches a rider's unsettled deliveries and settlements for
+// [html.RiderPage].
+func riderPage(id, token, msg string) Node {
+	riders, err := repository.GetRiders(token)
+	if err != nil {
+		return dispatchPage(token, "Failed to fetch riders")
+	}
+	var rider model.User
+	for _, u := range riders {
+		if u.ID == id {
+			rider = u
+		}
+	}
+	if rider.ID == "" {
+		return dispatchPage(token, "Rider not found")
+	}
+
+	orders, err := repository.GetUnsettledDeliveries(id, token)
+	if err != nil {
+		return html.RiderPage(rider, nil, nil, "Failed to fetch deliveries")
+	}
+	settlements, err := repository.GetRiderSettlements(id, token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch past settlements"
+	}
+	return html.RiderPage(rider, orders, settlements, msg)
+}
+
+// zonesPage fetches the delivery zones for [html.ZonesPage].
+func zonesPage(token, msg string) Node {
+	zones, err := repository.GetZones(token)
+	if err != nil {
+		return html.ZonesPage(nil, "Failed to fetch zones")
+	}
+	return html.ZonesPage(zones, msg)
+}
+
+// zoneFromForm reads a delivery zone from the add/edit form. It returns a
+// user-facing message if the form is invalid.
+func zoneFromForm(r *http.Request) (model.Zone, string) {
+	if err := r.ParseForm(); err != nil {
+		return model.Zone{}, "Invalid form submission"
+	}
+
+	z := model.Zone{Name: strings.TrimSpace(r.FormValue("name"))}
+	if z.Name == "" {
+		return z, "Please enter a name"
+	}
+	fee, err := strconv.ParseFloat(strings.TrimSpace(r.FormValue("fee")), 64)
+	if err != nil || fee < 0 {
+		return z, "Please enter a valid delivery fee"
+	}
+	z.Fee = fee
+	if s := strings.TrimSpace(r.FormValue("free_from")); s != "" {
+		freeFrom, err := strconv.ParseFloat(s, 64)
+		if err != nil || freeFrom < 0 {
+			return z, "Please enter a valid free delivery amount"
+		}
+		z.FreeFrom = freeFrom
+	}
+	return z, ""
+}
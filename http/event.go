@@ -0,0 +1,75 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rustacean-dev/possystem/internal/event"
+)
+
+// events carries order, stock and kitchen changes to the live pages. It is
+// set up by [NewServer].
+var events = event.NewBus()
+
+// eventKeepAlive is how often an idle event stream sends a comment, so
+// proxies don't close it.
+const eventKeepAlive = 30 * time.Second
+
+// EventRoutes registers the stream of shop events for browser clients.
+func EventRoutes(r chi.Router) {
+	// GET /events – Server-Sent Events; ?types= limits them, comma-separated
+	r.Get("/events", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("token")
+		if err != nil || !validToken(cookie.Value) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var names []string
+		if q := r.URL.Query().Get("types"); q != "" {
+			for _, name := range strings.Split(q, ",") {
+				if !slices.Contains(event.Names, name) {
+					http.Error(w, "Unknown event type "+name, http.StatusBadRequest)
+					return
+				}
+				names = append(names, name)
+			}
+		}
+
+		changes, cancel := events.Subscribe(names...)
+		defer cancel()
+
+		allowLongWrite(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flush(w)
+
+		ticker := time.NewTicker(eventKeepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e, ok := <-changes:
+				if !ok {
+					return
+				}
+				data, err := event.Encode(e, time.Now())
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Name(), data)
+			case <-ticker.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			flush(w)
+		}
+	})
+}
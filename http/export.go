@@ -0,0 +1,230 @@
+package http
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/internal/modifier"
+	"github.com/rustacean-dev/possystem/internal/ordertype"
+	"github.com/rustacean-dev/possystem/internal/report"
+	"github.com/rustacean-dev/possystem/internal/xlsx"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// ExportRoutes registers the spreadsheet exports of orders, items and
+// stocktake variance reports.
+// Order exports honour the same from/to/status/type query filters as /orders.
+func ExportRoutes(r chi.Router) {
+	r.Get("/orders/export.csv", exportOrders(newCSVRows))
+	r.Get("/orders/export.xlsx", exportOrders(newXLSXRows))
+	r.Get("/items/export.csv", exportItems(newCSVRows))
+	r.Get("/items/export.xlsx", exportItems(newXLSXRows))
+	r.Get("/stocktakes/{id}/export.csv", exportStocktake(newCSVRows))
+	r.Get("/stocktakes/{id}/export.xlsx", exportStocktake(newXLSXRows))
+}
+
+// rowWriter is implemented by the CSV and XLSX exporters.
+type rowWriter interface {
+	WriteRow(cells ...any) error
+	Close() error
+}
+
+// newRowWriter sets the download headers on w and returns a rowWriter for it.
+type newRowWriter func(w http.ResponseWriter, name string) (rowWriter, error)
+
+// exportOrders streams one row per order line, page by page.
+func exportOrders(newRows newRowWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		filter := orderFilterFromRequest(r)
+		if err := repository.CheckOrderFilter(filter); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		allowLongWrite(w)
+
+		rows, err := newRows(w, "orders-"+time.Now().Format("20060102-1504"))
+		if err != nil {
+			http.Error(w, "Failed to start export", http.StatusInternalServerError)
+			return
+		}
+
+		_ = rows.WriteRow("order_id", "created_at", "cashier", "type", "status", "tender",
+			"item_id", "item_name", "options", "note", "unit_price", "quantity", "line_total", "unit_cost",
+			"order_total", "delivery_fee")
+
+		err = repository.EachOrder(filter, cookie.Value, func(o model.Order) error {
+			head := []any{o.ID, o.CreatedAt, report.CashierOf(o), ordertype.Label(o.Type), o.Status, o.Tender}
+			tail := []any{o.TotalCost, o.DeliveryFee}
+
+			if len(o.Items) == 0 {
+				return rows.WriteRow(append(append(head, "", "", "", "", "", "", "", ""), tail...)...)
+			}
+			for _, line := range o.Items {
+				cells := append([]any{}, head...)
+				cells = append(cells, line.ID, line.Name, modifier.Describe(line.Modifiers), line.Note,
+					compute.UnitPrice(line), line.Quantity, compute.LineTotal(line), line.Cost)
+				if err := rows.WriteRow(append(cells, tail...)...); err != nil {
+					return err
+				}
+			}
+			flush(w)
+			return nil
+		})
+		if err != nil {
+			// Headers are already sent, so all we can do is log and cut the file short.
+			log.Println("Order export failed:", err)
+		}
+
+		_ = rows.Close()
+	}
+}
+
+// exportItems streams the item catalogue with stock values.
+func exportItems(newRows newRowWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		categories, err := repository.GetCategories(cookie.Value)
+		if err != nil {
+			http.Error(w, "Failed to fetch categories", http.StatusBadGateway)
+			return
+		}
+		categoryNames := map[string]string{}
+		for _, c := range categories {
+			categoryNames[c.ID] = c.Name
+		}
+
+		allowLongWrite(w)
+
+		rows, err := newRows(w, "items-"+time.Now().Format("20060102-1504"))
+		if err != nil {
+			http.Error(w, "Failed to start export", http.StatusInternalServerError)
+			return
+		}
+
+		_ = rows.WriteRow("item_id", "name", "description", "category", "sku", "barcodes", "price", "cost", "quantity", "stock_value", "archived", "updated")
+
+		err = repository.EachItem(true, cookie.Value, func(it model.Item) error {
+			return rows.WriteRow(it.ID, it.Name, it.Description, categoryNames[it.Category], it.SKU, strings.Join(it.Barcodes, " "), it.Price, it.Cost, it.Quantity,
+				it.Price*float64(it.Quantity), it.Archived, it.UpdatedAt)
+		})
+		if err != nil {
+			log.Println("Item export failed:", err)
+		}
+
+		_ = rows.Close()
+	}
+}
+
+// orderFilterFromRequest reads the from/to/status query parameters.
+func orderFilterFromRequest(r *http.Request) model.OrderFilter {
+	q := r.URL.Query()
+	return model.OrderFilter{
+		From:   q.Get("from"),
+		To:     q.Get("to"),
+		Status: q.Get("status"),
+		Type:   q.Get("type"),
+	}
+}
+
+// allowLongWrite lifts the server write timeout for responses that stream
+// for longer than a normal page render.
+func allowLongWrite(w http.ResponseWriter) {
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+}
+
+// flush sends buffered response data to the client, if the writer supports it.
+func flush(w http.ResponseWriter) {
+	_ = http.NewResponseController(w).Flush()
+}
+
+type csvRows struct {
+	cw *csv.Writer
+}
+
+func newCSVRows(w http.ResponseWriter, name string) (rowWriter, error) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".csv"))
+	return csvRows{cw: csv.NewWriter(w)}, nil
+}
+
+func (c csvRows) WriteRow(cells ...any) error {
+	record := make([]string, len(cells))
+	for i, v := range cells {
+		switch v := v.(type) {
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	if err := c.cw.Write(record); err != nil {
+		return err
+	}
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+func (c csvRows) Close() error {
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+func newXLSXRows(w http.ResponseWriter, name string) (rowWriter, error) {
+	w.Header().Set("Content-Type", xlsx.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".xlsx"))
+	return xlsx.NewWriter(w, name)
+}
+
+// exportStocktake writes the variance report of a closed stocktake.
+func exportStocktake(newRows newRowWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		st, err := repository.GetStocktakeByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Error(w, "Stocktake not found", http.StatusNotFound)
+			return
+		}
+		if st.Status != model.StocktakeClosed {
+			http.Error(w, "Stocktake is still open", http.StatusConflict)
+			return
+		}
+
+		rows, err := newRows(w, "stocktake-"+st.ID)
+		if err != nil {
+			http.Error(w, "Failed to start export", http.StatusInternalServerError)
+			return
+		}
+
+		_ = rows.WriteRow("item_id", "name", "expected", "counted", "variance", "cost", "value")
+		for _, l := range st.Lines {
+			_ = rows.WriteRow(l.Item, l.Name, l.Expected, l.Counted, l.Variance, l.Cost, l.Value)
+		}
+
+		_ = rows.Close()
+	}
+}
@@ -2,9 +2,13 @@ package http
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/analytics"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
 	. "maragu.dev/gomponents"
 
 	ghttp "maragu.dev/gomponents/http"
@@ -15,15 +19,34 @@ import (
 // This route renders the homepage and checks for a valid JWT token
 // in the `token` cookie to determine if the user is authenticated.
 //
-// If authenticated, the page might render a dashboard or show user-specific data.
-// Otherwise, it renders a guest-friendly homepage.
+// If authenticated, the page renders a sales dashboard for today.
+// Otherwise, or if the dashboard data cannot be fetched, it renders
+// the guest-friendly homepage.
 
 func Home(r chi.Router) {
 	r.Get("/", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
 		// Check if user is authenticated
 		cookie, err := r.Cookie("token")
 		authenticated := err == nil && cookie.Value != ""
+		if !authenticated {
+			return html.HomePage(false), nil
+		}
 
-		return html.HomePage(authenticated), nil
+		// The dashboard compares today with the same day last week
+		now := time.Now()
+		orders, err := repository.GetOrders(model.OrderFilter{
+			From: now.AddDate(0, 0, -7).Format("2006-01-02"),
+			To:   now.Format("2006-01-02"),
+		}, cookie.Value)
+		if err != nil {
+			return html.HomePage(true), nil
+		}
+		// Made-to-order items count as low when their ingredients run low
+		items, err := sellableItems(cookie.Value)
+		if err != nil {
+			return html.HomePage(true), nil
+		}
+
+		return html.DashboardPage(analytics.Build(orders, items, now)), nil
 	}))
 }
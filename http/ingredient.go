@@ -0,0 +1,162 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/recipe"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// IngredientRoutes registers the ingredient stock page.
+func IngredientRoutes(r chi.Router) {
+	// GET /ingredients – List ingredients with inline edit forms
+	r.Get("/ingredients", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return ingredientsPage(cookie.Value, ""), nil
+	}))
+
+	// POST /ingredients – Add an ingredient
+	r.Post("/ingredients", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		ing, msg := ingredientFromForm(r)
+		if msg == "" {
+			saved, err := repository.CreateIngredient(ing, cookie.Value)
+			if err != nil {
+				msg = "Failed to add ingredient"
+			} else if saved.Quantity > 0 {
+				err := repository.CreateStockMovement(model.StockMovement{
+					Ingredient: saved.ID,
+					Kind:       model.MovementRestock,
+					Quantity:   saved.Quantity,
+					Reason:     "Opening stock",
+				}, cookie.Value)
+				if err != nil {
+					log.Println("Failed to record opening stock:", err)
+				}
+			}
+		}
+		if msg != "" {
+			return ingredientsPage(cookie.Value, msg), nil
+		}
+
+		http.Redirect(w, r, "/ingredients", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /ingredients/{id} – Save an ingredient
+	r.Post("/ingredients/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		ing, msg := ingredientFromForm(r)
+		ing.ID = chi.URLParam(r, "id")
+		if msg == "" {
+			if err := repository.UpdateIngredient(ing, cookie.Value); err != nil {
+				msg = "Failed to save ingredient"
+			}
+		}
+		if msg == "" {
+			// A changed stock level is recorded as an adjustment
+			ingredients, _ := repository.GetIngredients(cookie.Value)
+			if old, ok := recipe.ByID(ingredients)[ing.ID]; ok && ing.Quantity != old.Quantity {
+				err := repository.ApplyStockMovement(model.StockMovement{
+					Ingredient: ing.ID,
+					Kind:       model.MovementAdjustment,
+					Quantity:   ing.Quantity - old.Quantity,
+					Reason:     "Edited on ingredients page",
+				}, cookie.Value)
+				if err != nil {
+					msg = "Failed to save stock level"
+				}
+			}
+		}
+		if msg != "" {
+			return ingredientsPage(cookie.Value, msg), nil
+		}
+
+		http.Redirect(w, r, "/ingredients", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /ingredients/{id}/delete – Delete an ingredient
+	r.Post("/ingredients/{id}/delete", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		if err := repository.DeleteIngredient(chi.URLParam(r, "id"), cookie.Value); err != nil {
+			return ingredientsPage(cookie.Value, "Failed to delete ingredient"), nil
+		}
+
+		http.Redirect(w, r, "/ingredients", http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// ingredientsPage fetches the ingredients and the items using them.
+func ingredientsPage(token, msg string) Node {
+	ingredients, err := repository.GetIngredients(token)
+	if err != nil {
+		return html.IngredientsPage(nil, nil, "Failed to fetch ingredients")
+	}
+
+	usedBy := map[string][]string{}
+	items, _ := repository.GetAllItems(token)
+	for _, it := range items {
+		for _, line := range it.Recipe {
+			usedBy[line.Ingredient] = append(usedBy[line.Ingredient], it.Name)
+		}
+	}
+
+	return html.IngredientsPage(ingredients, usedBy, msg)
+}
+
+// ingredientFromForm reads an ingredient from the add/edit form. It returns
+// a user-facing message if the form is invalid.
+func ingredientFromForm(r *http.Request) (model.Ingredient, string) {
+	if err := r.ParseForm(); err != nil {
+		return model.Ingredient{}, "Invalid form submission"
+	}
+
+	ing := model.Ingredient{
+		Name: strings.TrimSpace(r.FormValue("name")),
+		Unit: r.FormValue("unit"),
+	}
+	if ing.Name == "" {
+		return ing, "Please enter a name"
+	}
+	if !recipe.ValidUnit(ing.Unit) {
+		return ing, "Please choose a unit"
+	}
+
+	qty, err := recipe.ParseQuantity(r.FormValue("quantity"))
+	if err != nil {
+		return ing, "Quantity must be a number ≥ 0"
+	}
+	ing.Quantity = qty
+
+	return ing, ""
+}
@@ -1,21 +1,113 @@
 package http
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/rustacean-dev/possystem/internal/analytics"
+	"github.com/rustacean-dev/possystem/internal/catalog"
+	"github.com/rustacean-dev/possystem/internal/ordertype"
+	"github.com/rustacean-dev/possystem/internal/recipe"
+	"github.com/rustacean-dev/possystem/internal/reorder"
 	"github.com/rustacean-dev/possystem/model"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rustacean-dev/possystem/html"
 	"github.com/rustacean-dev/possystem/repository"
 	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
 	ghttp "maragu.dev/gomponents/http"
 )
 
 func ItemRoutes(r chi.Router) {
+	// GET /items – Searchable, sortable, paginated item catalogue
+	r.Get("/items", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		query := r.URL.Query()
+		page, _ := strconv.Atoi(query.Get("page"))
+		q := model.ItemQuery{
+			Search:     strings.TrimSpace(query.Get("q")),
+			Sort:       query.Get("sort"),
+			Stock:      query.Get("stock"),
+			LowStockAt: analytics.LowStockThreshold,
+			Page:       page,
+			PerPage:    25,
+		}
+
+		categories, _ := repository.GetCategories(cookie.Value)
+
+		items, err := repository.ListItems(q, cookie.Value)
+		if err != nil {
+			return html.ItemListPage(model.ItemPage{}, q, categories, "Failed to fetch items"), nil
+		}
+
+		return html.ItemListPage(items, q, categories, ""), nil
+	}))
+
+	// GET /items/{id}/quantity – Inline quantity editor (HTMX fragment)
+	r.Get("/items/{id}/quantity", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			w.Header().Set("HX-Redirect", "/login")
+			return nil, nil
+		}
+
+		item, err := repository.GetItemByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			return Td(Class("px-4 py-2 border-t text-red-600"), Text("Item not found")), nil
+		}
+
+		return html.QuantityEditor(item, ""), nil
+	}))
+
+	// POST /items/{id}/quantity – Save an inline quantity edit (HTMX fragment)
+	r.Post("/items/{id}/quantity", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			w.Header().Set("HX-Redirect", "/login")
+			return nil, nil
+		}
+
+		item, err := repository.GetItemByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			return Td(Class("px-4 py-2 border-t text-red-600"), Text("Item not found")), nil
+		}
+
+		quantity, err := catalog.ParseQuantity(r.FormValue("quantity"))
+		if err != nil {
+			return html.QuantityEditor(item, err.Error()), nil
+		}
+
+		if msg := perishableIncrease(item, quantity-item.Quantity); msg != "" {
+			return html.QuantityEditor(item, msg), nil
+		}
+		if quantity != item.Quantity {
+			m := model.StockMovement{
+				Item:     item.ID,
+				Kind:     model.MovementAdjustment,
+				Quantity: float64(quantity - item.Quantity),
+				Reason:   "Edited in item list",
+			}
+			if err := repository.ApplyStockMovement(m, cookie.Value); err != nil {
+				return html.QuantityEditor(item, "Failed to save"), nil
+			}
+			takeLots(item, m, cookie.Value)
+		}
+
+		item.Quantity = quantity
+		return html.QuantityCell(item), nil
+	}))
+
 	r.Get("/items/new", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
 		//  Redirect if not logged in
 		cookie, err := r.Cookie("token")
@@ -24,7 +116,16 @@ func ItemRoutes(r chi.Router) {
 			return nil, nil
 		}
 
-		return html.NewItemPage(""), nil
+		// Categories fill the category dropdown
+		categories, _ := repository.GetCategories(cookie.Value)
+
+		// Prefill codes, e.g. when coming from an unknown scan on the POS screen
+		var draft model.Item
+		if code, err := catalog.NormalizeCode(r.URL.Query().Get("barcode")); err == nil && code != "" {
+			draft.Barcodes = []string{code}
+		}
+
+		return html.NewItemPage("", categories, draft), nil
 	}))
 
 	r.Post("/items/new", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
@@ -35,35 +136,79 @@ func ItemRoutes(r chi.Router) {
 			return nil, nil
 		}
 
+		// Categories fill the category dropdown
+		categories, _ := repository.GetCategories(cookie.Value)
+
 		// Parse form
 		if err := r.ParseForm(); err != nil {
-			return html.NewItemPage("Invalid form submission"), nil
+			return html.NewItemPage("Invalid form submission", categories, model.Item{}), nil
+		}
+
+		// Keep what was entered, so errors don't clear the form
+		draft := model.Item{
+			Name:        r.FormValue("name"),
+			Description: r.FormValue("description"),
+			Category:    r.FormValue("category"),
+			SKU:         r.FormValue("sku"),
+			Barcodes:    strings.Fields(strings.ReplaceAll(r.FormValue("barcodes"), ",", " ")),
 		}
 
 		// Parse price
-		price, err := strconv.ParseFloat(r.FormValue("price"), 64)
-		if err != nil || price <= 0 {
-			return html.NewItemPage("Please enter a valid price"), nil
+		price, err := catalog.ParsePrice(r.FormValue("price"))
+		if err != nil {
+			return html.NewItemPage("Please enter a valid price", categories, draft), nil
+		}
+
+		cost, err := catalog.ParseCost(r.FormValue("cost"))
+		if err != nil {
+			return html.NewItemPage("Please enter a valid cost", categories, draft), nil
+		}
+		typePrices, err := typePricesFromForm(r)
+		if err != nil {
+			return html.NewItemPage(err.Error(), categories, draft), nil
 		}
 
 		// Parse quantity (optional: default to 0)
-		quantity := 0
-		if q := r.FormValue("quantity"); q != "" {
-			quantity, _ = strconv.Atoi(q)
+		quantity, err := catalog.ParseQuantity(r.FormValue("quantity"))
+		if err != nil {
+			return html.NewItemPage("Please enter a valid quantity", categories, draft), nil
 		}
 
 		// Normalize item name (case-insensitive matching)
-		name := strings.ToLower(strings.TrimSpace(r.FormValue("name")))
+		name := catalog.NormalizeName(r.FormValue("name"))
 		description := r.FormValue("description")
+		category := strings.TrimSpace(r.FormValue("category"))
+
+		sku, err := catalog.NormalizeCode(r.FormValue("sku"))
+		if err != nil {
+			return html.NewItemPage("Invalid SKU: "+err.Error(), categories, draft), nil
+		}
+		barcodes, err := catalog.ParseCodes(r.FormValue("barcodes"))
+		if err != nil {
+			return html.NewItemPage("Invalid barcode: "+err.Error(), categories, draft), nil
+		}
 
 		// Check if item already exists
 		existingItem, err := repository.GetItemByName(name, cookie.Value)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return html.NewItemPage("Failed to check for an existing item", categories, draft), nil
+		}
+		if err == nil && existingItem.Archived {
+			return html.NewItemPage(fmt.Sprintf("'%s' is archived. Restore it from its edit page instead.", name), categories, draft), nil
+		}
 		if err == nil {
+			if msg := perishableIncrease(existingItem, quantity); msg != "" {
+				return html.NewItemPage(msg, categories, draft), nil
+			}
 			//  If item exists, increase quantity only
-			newQty := existingItem.Quantity + quantity
-			err := repository.UpdateItemStock(existingItem.ID, newQty, cookie.Value)
+			err := repository.ApplyStockMovement(model.StockMovement{
+				Item:     existingItem.ID,
+				Kind:     model.MovementRestock,
+				Quantity: float64(quantity),
+				Reason:   "Added with the new item form",
+			}, cookie.Value)
 			if err != nil {
-				return html.NewItemPage("Failed to update stock of existing item"), nil
+				return html.NewItemPage("Failed to update stock of existing item", categories, draft), nil
 			}
 
 			//  Redirect (stock updated)
@@ -75,17 +220,275 @@ func ItemRoutes(r chi.Router) {
 		item := model.Item{
 			Name:        name,
 			Price:       price,
+			Cost:        cost,
+			TypePrices:  typePrices,
 			Description: description,
 			Quantity:    quantity,
+			Category:    category,
+			SKU:         sku,
+			Barcodes:    barcodes,
 		}
 
-		err = repository.CreateItem(item, cookie.Value)
+		// SKUs and barcodes identify exactly one item
+		if msg := checkCodesUnique(item, cookie.Value); msg != "" {
+			return html.NewItemPage(msg, categories, draft), nil
+		}
+
+		saved, err := repository.CreateItem(item, cookie.Value)
 		if err != nil {
-			return html.NewItemPage(fmt.Sprintf("Failed to create item: %s", err.Error())), nil
+			return html.NewItemPage(fmt.Sprintf("Failed to create item: %s", err.Error()), categories, draft), nil
 		}
+		recordOpeningStock(saved, cookie.Value)
 
 		w.Header().Set("HX-Redirect", "/orders/new")
 		return nil, nil
 	}))
 
+	// GET /items/{id}/edit – Edit form with price history
+	r.Get("/items/{id}/edit", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		// Categories fill the category dropdown, groups the modifier
+		// checkboxes, ingredients the recipe rows and suppliers the
+		// reorder settings
+		categories, _ := repository.GetCategories(cookie.Value)
+		groups, _ := repository.GetModifierGroups(cookie.Value)
+		ingredients, _ := repository.GetIngredients(cookie.Value)
+		suppliers, _ := repository.GetSuppliers(cookie.Value)
+
+		item, err := repository.GetItemByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			return html.NewItemPage("Item not found", categories, model.Item{}), nil
+		}
+
+		history, err := repository.GetPriceChanges(item.ID, cookie.Value)
+		if err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, nil, "Failed to fetch price history"), nil
+		}
+
+		return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, ""), nil
+	}))
+
+	// POST /items/{id}/edit – Save all fields, recording any price change
+	r.Post("/items/{id}/edit", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		// Categories fill the category dropdown, groups the modifier
+		// checkboxes, ingredients the recipe rows and suppliers the
+		// reorder settings
+		categories, _ := repository.GetCategories(cookie.Value)
+		groups, _ := repository.GetModifierGroups(cookie.Value)
+		ingredients, _ := repository.GetIngredients(cookie.Value)
+		suppliers, _ := repository.GetSuppliers(cookie.Value)
+
+		item, err := repository.GetItemByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			return html.NewItemPage("Item not found", categories, model.Item{}), nil
+		}
+		history, _ := repository.GetPriceChanges(item.ID, cookie.Value)
+
+		if err := r.ParseForm(); err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, "Invalid form submission"), nil
+		}
+
+		price, err := catalog.ParsePrice(r.FormValue("price"))
+		if err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, "Please enter a valid price"), nil
+		}
+		cost, err := catalog.ParseCost(r.FormValue("cost"))
+		if err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, "Please enter a valid cost"), nil
+		}
+		typePrices, err := typePricesFromForm(r)
+		if err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, err.Error()), nil
+		}
+		quantity, err := catalog.ParseQuantity(r.FormValue("quantity"))
+		if err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, "Please enter a valid quantity"), nil
+		}
+		name := catalog.NormalizeName(r.FormValue("name"))
+		if name == "" {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, "Please enter a name"), nil
+		}
+
+		// Names are unique, since new stock is merged into items by name
+		if name != item.Name {
+			if other, err := repository.GetItemByName(name, cookie.Value); err == nil && other.ID != item.ID {
+				return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, fmt.Sprintf("Another item is already called '%s'", name)), nil
+			}
+		}
+
+		sku, err := catalog.NormalizeCode(r.FormValue("sku"))
+		if err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, "Invalid SKU: "+err.Error()), nil
+		}
+		barcodes, err := catalog.ParseCodes(r.FormValue("barcodes"))
+		if err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, "Invalid barcode: "+err.Error()), nil
+		}
+
+		recipeLines, err := recipe.ParseLines(r.Form["recipe_ingredient"], r.Form["recipe_quantity"])
+		if err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, "Recipe: "+err.Error()), nil
+		}
+
+		reorderPoint, parLevel, err := reorder.ParseLevels(r.FormValue("reorder_point"), r.FormValue("par_level"))
+		if err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, "Reorder: "+err.Error()), nil
+		}
+
+		oldPrice := item.Price
+		oldQuantity := item.Quantity
+		item.SKU = sku
+		item.Barcodes = barcodes
+		item.Name = name
+		item.Price = price
+		item.Cost = cost
+		item.TypePrices = typePrices
+		item.Description = r.FormValue("description")
+		item.Category = strings.TrimSpace(r.FormValue("category"))
+		item.Quantity = quantity
+		item.ModifierGroups = r.Form["modifier_groups"]
+		item.Recipe = recipeLines
+		item.ReorderPoint = reorderPoint
+		item.ParLevel = parLevel
+		item.Supplier = strings.TrimSpace(r.FormValue("supplier"))
+		item.Perishable = r.FormValue("perishable") == "true"
+
+		if msg := perishableIncrease(item, item.Quantity-oldQuantity); msg != "" {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, msg), nil
+		}
+		if msg := checkCodesUnique(item, cookie.Value); msg != "" {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, msg), nil
+		}
+
+		if err := repository.UpdateItem(item, cookie.Value); err != nil {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, "Failed to save item"), nil
+		}
+
+		if msg := recordItemEdit(item, oldPrice, oldQuantity, "Edited on item page", cookie.Value); msg != "" {
+			return html.EditItemPage(item, categories, groups, ingredients, suppliers, history, msg), nil
+		}
+
+		http.Redirect(w, r, "/items/"+item.ID+"/edit", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /items/{id}/archive – Soft-delete, past orders keep their reference
+	r.Post("/items/{id}/archive", setArchived(true))
+
+	// POST /items/{id}/restore – Undo an archive
+	r.Post("/items/{id}/restore", setArchived(false))
+}
+
+// recordOpeningStock starts the stock ledger of a new item with its
+// initial quantity, which was saved with the item itself.
+func recordOpeningStock(item model.Item, token string) {
+	if item.Quantity == 0 {
+		return
+	}
+	err := repository.CreateStockMovement(model.StockMovement{
+		Item:     item.ID,
+		Kind:     model.MovementRestock,
+		Quantity: float64(item.Quantity),
+		Reason:   "Opening stock",
+	}, token)
+	if err != nil {
+		log.Println("Failed to record opening stock:", err)
+	}
+}
+
+// recordItemEdit records a stock adjustment and a price change for an
+// edited item whose quantity or price changed, so the stock ledger and
+// price history stay complete. The item is saved already, so it returns a
+// user-facing message saying which record failed, if one did.
+func recordItemEdit(item model.Item, oldPrice float64, oldQuantity int, reason, token string) string {
+	var failed []string
+	if item.Quantity != oldQuantity {
+		m := model.StockMovement{
+			Item:     item.ID,
+			Kind:     model.MovementAdjustment,
+			Quantity: float64(item.Quantity - oldQuantity),
+			Reason:   reason,
+		}
+		if err := repository.ApplyStockMovement(m, token); err != nil {
+			log.Println("Failed to record stock adjustment:", err)
+			failed = append(failed, "the stock adjustment")
+		} else {
+			takeLots(item, m, token)
+		}
+	}
+
+	if item.Price != oldPrice {
+		author, _ := repository.TokenUserID(token)
+		err := repository.CreatePriceChange(model.PriceChange{
+			Item:        item.ID,
+			OldPrice:    oldPrice,
+			NewPrice:    item.Price,
+			EffectiveAt: model.FormatTime(time.Now()),
+			Author:      author,
+		}, token)
+		if err != nil {
+			log.Println("Failed to record price change:", err)
+			failed = append(failed, "the price change")
+		}
+	}
+
+	if len(failed) > 0 {
+		return "Item saved, but failed to record " + strings.Join(failed, " and ") + "; please check the item's history"
+	}
+	return ""
+}
+
+// checkCodesUnique makes sure no other item already uses the item's SKU or
+// one of its barcodes. It returns a user-facing message if one does.
+func checkCodesUnique(item model.Item, token string) string {
+	for _, code := range append([]string{item.SKU}, item.Barcodes...) {
+		if code == "" {
+			continue
+		}
+		other, err := repository.GetItemByCode(code, token)
+		if err == nil && other.ID != item.ID {
+			return fmt.Sprintf("Code %s is already used by '%s'", code, other.Name)
+		}
+	}
+	return ""
+}
+
+// setArchived returns a handler that archives or restores the item in the URL.
+func setArchived(archived bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		if err := repository.SetItemArchived(id, archived, cookie.Value); err != nil {
+			http.Error(w, "Failed to update item", http.StatusBadGateway)
+			return
+		}
+
+		http.Redirect(w, r, "/items/"+id+"/edit", http.StatusSeeOther)
+	}
+}
+
+// typePricesFromForm reads the optional price_<type> fields of the item
+// forms.
+func typePricesFromForm(r *http.Request) (map[string]float64, error) {
+	values := map[string]string{}
+	for _, t := range ordertype.Types {
+		values[t] = r.FormValue("price_" + t)
+	}
+	return ordertype.ParsePrices(values)
 }
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/catalog"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// ItemImportRoutes registers the bulk item import from CSV.
+// Uploading only previews the changes; nothing is saved until confirmed.
+func ItemImportRoutes(r chi.Router) {
+	r.Get("/items/import", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		//  Redirect if not logged in
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return html.ImportItemsPage(""), nil
+	}))
+
+	// POST /items/import – Validate the file and show a preview
+	r.Post("/items/import", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			return html.ImportItemsPage("Invalid upload"), nil
+		}
+
+		// Prefer the uploaded file, fall back to pasted text
+		text := r.FormValue("csv")
+		if f, _, err := r.FormFile("file"); err == nil {
+			b, err := io.ReadAll(f)
+			_ = f.Close()
+			if err != nil {
+				return html.ImportItemsPage("Could not read the uploaded file"), nil
+			}
+			text = string(b)
+		}
+		if strings.TrimSpace(text) == "" {
+			return html.ImportItemsPage("Please choose a CSV file or paste its contents"), nil
+		}
+
+		plan, err := planImport(text, cookie.Value)
+		if err != nil {
+			return html.ImportItemsPage(err.Error()), nil
+		}
+
+		return html.ImportPreviewPage(plan, text), nil
+	}))
+
+	// POST /items/import/confirm – Apply a previewed import
+	r.Post("/items/import/confirm", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		if err := r.ParseForm(); err != nil {
+			return html.ImportItemsPage("Invalid form submission"), nil
+		}
+
+		// Plan again so stock added since the preview is not overwritten
+		plan, err := planImport(r.FormValue("csv"), cookie.Value)
+		if err != nil {
+			return html.ImportItemsPage(err.Error()), nil
+		}
+
+		var created, updated []catalog.Change
+		errs := plan.Errors
+
+		for _, c := range plan.Creates {
+			item := model.Item{
+				Name:        c.Row.Name,
+				Price:       c.Row.Price,
+				Description: c.Row.Description,
+				Quantity:    c.Row.Quantity,
+				Category:    c.Row.CategoryID,
+			}
+			saved, err := repository.CreateItem(item, cookie.Value)
+			if err != nil {
+				errs = append(errs, catalog.RowError{Line: c.Row.Line, Name: c.Row.Name, Err: fmt.Sprintf("failed to create item: %s", err)})
+				continue
+			}
+			recordOpeningStock(saved, cookie.Value)
+			created = append(created, c)
+		}
+
+		for _, c := range plan.Updates {
+			err := repository.ApplyStockMovement(model.StockMovement{
+				Item:     c.Existing.ID,
+				Kind:     model.MovementRestock,
+				Quantity: float64(c.Row.Quantity),
+				Reason:   "CSV import",
+			}, cookie.Value)
+			if err != nil {
+				errs = append(errs, catalog.RowError{Line: c.Row.Line, Name: c.Row.Name, Err: "failed to update stock"})
+				continue
+			}
+			updated = append(updated, c)
+		}
+
+		return html.ImportResultPage(created, updated, errs), nil
+	}))
+}
+
+// planImport parses and validates the CSV, resolves category names and
+// matches rows to existing items by name, the same way the single-item form
+// merges stock.
+func planImport(text, token string) (catalog.Plan, error) {
+	rows, rowErrs, err := catalog.ParseCSV(strings.NewReader(text))
+	if err != nil {
+		return catalog.Plan{}, err
+	}
+
+	categories, err := repository.GetCategories(token)
+	if err != nil {
+		return catalog.Plan{}, fmt.Errorf("failed to fetch categories")
+	}
+	rows, catErrs := catalog.ResolveCategories(rows, categories)
+	rowErrs = append(rowErrs, catErrs...)
+
+	return catalog.BuildPlan(rows, rowErrs, func(name string) (model.Item, bool, error) {
+		item, err := repository.GetItemByName(name, token)
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Item{}, false, nil
+		}
+		return item, err == nil, err
+	}), nil
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/internal/kitchen"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// kitchenRefresh is how often kitchen screens are told to redraw, so ticket
+// ages keep counting up even when nothing changes.
+const kitchenRefresh = 30 * time.Second
+
+// KitchenRoutes registers the kitchen display, its live update stream and
+// the bumping of tickets. Each page takes ?station= to show one station.
+func KitchenRoutes(r chi.Router) {
+	// GET /kitchen – Open tickets as cards
+	r.Get("/kitchen", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		station := kitchenStation(r)
+		tickets, err := repository.GetOpenTickets(station, cookie.Value)
+		if err != nil {
+			return html.KitchenPage(station, nil, time.Now(), "Failed to fetch tickets"), nil
+		}
+		return html.KitchenPage(station, tickets, time.Now(), ""), nil
+	}))
+
+	// GET /kitchen/board – The ticket cards alone (HTMX)
+	r.Get("/kitchen/board", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			w.Header().Set("HX-Redirect", "/login")
+			return nil, nil
+		}
+
+		return kitchenBoard(kitchenStation(r), cookie.Value, ""), nil
+	}))
+
+	// GET /kitchen/stream – Server-Sent Events, one message per ticket change
+	r.Get("/kitchen/stream", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("token")
+		if err != nil || !validToken(cookie.Value) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		station := kitchenStation(r)
+		changes, cancel := events.Subscribe(event.NameTicketChanged)
+		defer cancel()
+
+		allowLongWrite(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flush(w)
+
+		ticker := time.NewTicker(kitchenRefresh)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e, ok := <-changes:
+				if !ok {
+					return
+				}
+				t := e.(event.TicketChanged).Ticket
+				if station != "" && t.Station != station {
+					continue
+				}
+				fmt.Fprintf(w, "data: %s\n\n", t.ID)
+			case <-ticker.C:
+				fmt.Fprint(w, "data: tick\n\n")
+			}
+			flush(w)
+		}
+	})
+
+	// POST /kitchen/tickets/{id}/bump – Move a ticket to its next status (HTMX)
+	r.Post("/kitchen/tickets/{id}/bump", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			w.Header().Set("HX-Redirect", "/login")
+			return nil, nil
+		}
+
+		station := kitchenStation(r)
+		t, err := repository.GetTicketByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			return kitchenBoard(station, cookie.Value, "Ticket not found"), nil
+		}
+
+		// A ticket served on another screen meanwhile is left as it is
+		if next, ok := kitchen.Next(t.Status); ok {
+			if _, err := repository.SetTicketStatus(t.ID, next, cookie.Value); err != nil {
+				return kitchenBoard(station, cookie.Value, "Failed to bump ticket"), nil
+			}
+		}
+
+		return kitchenBoard(station, cookie.Value, ""), nil
+	}))
+}
+
+// kitchenBoard fetches the open tickets for [html.KitchenBoard].
+func kitchenBoard(station, token, msg string) Node {
+	tickets, err := repository.GetOpenTickets(station, token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch tickets"
+	}
+	return html.KitchenBoard(station, tickets, time.Now(), msg)
+}
+
+// kitchenStation reads the station a kitchen page is for. Unknown stations
+// show every station.
+func kitchenStation(r *http.Request) string {
+	station := r.FormValue("station")
+	if !slices.Contains(model.Stations, station) {
+		return ""
+	}
+	return station
+}
+
+// sendToKitchen creates a ticket for each station that has lines of the
+// order to prepare. Failures are logged; the order stands either way.
+func sendToKitchen(o model.Order, token string) {
+	categories, err := repository.GetCategories(token)
+	if err != nil {
+		log.Println("Failed to fetch categories for kitchen tickets:", err)
+	}
+	byID := map[string]model.Category{}
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	for _, t := range kitchen.Tickets(o, byID) {
+		if _, err := repository.CreateTicket(t, token); err != nil {
+			log.Println("Failed to send ticket to the kitchen:", err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/lot"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// LotRoutes registers the expiring-soon report of perishable stock and the
+// write-off of expired lots.
+func LotRoutes(r chi.Router) {
+	// GET /lots/expiring – Lots expiring within ?days= (default 3), and expired ones
+	r.Get("/lots/expiring", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return expiringPage(expiringDays(r), cookie.Value, ""), nil
+	}))
+
+	// POST /lots/{id}/write-off – Write off what is left of a lot as waste
+	r.Post("/lots/{id}/write-off", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		l, err := repository.GetLotByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			return expiringPage(expiringDays(r), cookie.Value, "Lot not found"), nil
+		}
+		if err := writeOffLot(l, cookie.Value); err != nil {
+			return expiringPage(expiringDays(r), cookie.Value, "Failed to write off lot: "+err.Error()), nil
+		}
+
+		// Only return to pages of this app
+		back := r.FormValue("back")
+		if !strings.HasPrefix(back, "/") || strings.HasPrefix(back, "//") {
+			back = "/lots/expiring"
+		}
+		http.Redirect(w, r, back, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /lots/write-off-expired – Write off every lot past its expiry date
+	r.Post("/lots/write-off-expired", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		now := time.Now()
+		lots, err := repository.GetExpiringLots(now.Format("2006-01-02"), cookie.Value)
+		if err != nil {
+			return expiringPage(expiringDays(r), cookie.Value, "Failed to fetch lots"), nil
+		}
+		for _, l := range lots {
+			if !lot.Expired(l, now) {
+				continue
+			}
+			if err := writeOffLot(l, cookie.Value); err != nil {
+				return expiringPage(expiringDays(r), cookie.Value, "Failed to write off "+l.Expand.Item.Name), nil
+			}
+		}
+
+		http.Redirect(w, r, "/lots/expiring", http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// expiringPage fetches the lots for [html.ExpiringPage].
+func expiringPage(days int, token, msg string) Node {
+	now := time.Now()
+	lots, err := repository.GetExpiringLots(now.AddDate(0, 0, days).Format("2006-01-02"), token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch lots"
+	}
+	return html.ExpiringPage(lot.Expiring(lots, now, days), days, now, msg)
+}
+
+// expiringDays reads the ?days= window of the expiring report.
+func expiringDays(r *http.Request) int {
+	days, err := strconv.Atoi(r.URL.Query().Get("days"))
+	if err != nil || days < 0 {
+		return 3
+	}
+	return days
+}
+
+// writeOffLot empties a lot and records what was left of it as waste. The
+// lot is emptied first, so retrying after a failure cannot write the same
+// stock off twice.
+func writeOffLot(l model.Lot, token string) error {
+	if l.Quantity <= 0 {
+		return nil
+	}
+	if err := repository.TakeFromLot(l.ID, l.Quantity, token); err != nil {
+		return err
+	}
+	err := repository.ApplyStockMovement(model.StockMovement{
+		Item:     l.Item,
+		Kind:     model.MovementWaste,
+		Quantity: float64(-l.Quantity),
+		Reason:   "Expired lot, best before " + lot.Expiry(l),
+	}, token)
+	if err != nil {
+		return fmt.Errorf("lot emptied but waste not recorded: %w", err)
+	}
+	return nil
+}
+
+// addLot starts a lot for the units a movement added to a perishable item,
+// so its lots keep adding up to its stock. expires is blank for a lot
+// without an expiry date.
+func addLot(item model.Item, m model.StockMovement, expires, token string) error {
+	if !item.Perishable || m.Quantity <= 0 {
+		return nil
+	}
+	return repository.CreateLot(model.Lot{
+		Item:     item.ID,
+		Quantity: int(m.Quantity),
+		Received: model.FormatTime(time.Now()),
+		Expires:  expires,
+	}, token)
+}
+
+// perishableIncrease returns why stock cannot be added to a perishable
+// item outside the stock page and receiving, which ask for the expiry date
+// of its lot, or "" if change adds none.
+func perishableIncrease(item model.Item, change int) string {
+	if !item.Perishable || change <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("'%s' is perishable. Add its stock on the stock page or receive it against a purchase order, so its expiry date is recorded.", item.Name)
+}
+
+// takeLots keeps the lots of a perishable item in line with a movement
+// that lowered its stock. Whatever the kind of movement, the units that
+// left came out of its lots.
+func takeLots(item model.Item, m model.StockMovement, token string) {
+	if item.Perishable && m.Quantity < 0 {
+		consumeLots(item.ID, int(-m.Quantity), token)
+	}
+}
+
+// consumeLots takes qty units of a perishable item out of its lots,
+// first-expiry-first-out. Failures are logged: the stock movement has
+// already been recorded, and lots only track which units go first.
+func consumeLots(itemID string, qty int, token string) {
+	lots, err := repository.GetLots(itemID, token)
+	if err != nil {
+		log.Println("Failed to fetch lots:", err)
+		return
+	}
+	for _, t := range lot.Consume(lots, qty) {
+		if err := repository.TakeFromLot(t.Lot, t.Quantity, token); err != nil {
+			log.Println("Failed to update lot:", err)
+		}
+	}
+}
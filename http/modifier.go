@@ -0,0 +1,132 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/modifier"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// ModifierRoutes registers the modifier group admin page.
+func ModifierRoutes(r chi.Router) {
+	// GET /modifiers – List modifier groups with inline edit forms
+	r.Get("/modifiers", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		groups, err := repository.GetModifierGroups(cookie.Value)
+		if err != nil {
+			return html.ModifierGroupsPage(nil, "Failed to fetch modifier groups"), nil
+		}
+
+		return html.ModifierGroupsPage(groups, ""), nil
+	}))
+
+	// POST /modifiers – Add a modifier group
+	r.Post("/modifiers", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		g, msg := modifierGroupFromForm(r)
+		if msg == "" {
+			if err := repository.CreateModifierGroup(g, cookie.Value); err != nil {
+				msg = "Failed to add modifier group"
+			}
+		}
+		if msg != "" {
+			groups, _ := repository.GetModifierGroups(cookie.Value)
+			return html.ModifierGroupsPage(groups, msg), nil
+		}
+
+		http.Redirect(w, r, "/modifiers", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /modifiers/{id} – Save a modifier group
+	r.Post("/modifiers/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		g, msg := modifierGroupFromForm(r)
+		g.ID = chi.URLParam(r, "id")
+		if msg == "" {
+			if err := repository.UpdateModifierGroup(g, cookie.Value); err != nil {
+				msg = "Failed to save modifier group"
+			}
+		}
+		if msg != "" {
+			groups, _ := repository.GetModifierGroups(cookie.Value)
+			return html.ModifierGroupsPage(groups, msg), nil
+		}
+
+		http.Redirect(w, r, "/modifiers", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /modifiers/{id}/delete – Delete a modifier group
+	r.Post("/modifiers/{id}/delete", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		if err := repository.DeleteModifierGroup(chi.URLParam(r, "id"), cookie.Value); err != nil {
+			groups, _ := repository.GetModifierGroups(cookie.Value)
+			return html.ModifierGroupsPage(groups, "Failed to delete modifier group"), nil
+		}
+
+		http.Redirect(w, r, "/modifiers", http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// modifierGroupFromForm reads a modifier group from the add/edit form. It
+// returns a user-facing message if the form is invalid.
+func modifierGroupFromForm(r *http.Request) (model.ModifierGroup, string) {
+	if err := r.ParseForm(); err != nil {
+		return model.ModifierGroup{}, "Invalid form submission"
+	}
+
+	g := model.ModifierGroup{
+		Name:     strings.TrimSpace(r.FormValue("name")),
+		Required: r.FormValue("required") == "true",
+	}
+	if g.Name == "" {
+		return g, "Please enter a name"
+	}
+
+	var err error
+	if g.MinSelect, err = strconv.Atoi(r.FormValue("min_select")); err != nil {
+		return g, "Minimum must be a whole number"
+	}
+	if g.MaxSelect, err = strconv.Atoi(r.FormValue("max_select")); err != nil {
+		return g, "Maximum must be a whole number"
+	}
+
+	if g.Options, err = modifier.ParseOptions(r.FormValue("options")); err != nil {
+		return g, "Options: " + err.Error()
+	}
+	if err := modifier.ValidateGroup(g); err != nil {
+		return g, g.Name + ": " + err.Error()
+	}
+
+	return g, ""
+}
@@ -1,10 +1,16 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"maragu.dev/gomponents"
 	. "maragu.dev/gomponents"
@@ -12,7 +18,15 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/catalog"
 	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/internal/dispatch"
+	"github.com/rustacean-dev/possystem/internal/floor"
+	"github.com/rustacean-dev/possystem/internal/modifier"
+	"github.com/rustacean-dev/possystem/internal/ordertype"
+	"github.com/rustacean-dev/possystem/internal/recipe"
+	"github.com/rustacean-dev/possystem/internal/split"
+	"github.com/rustacean-dev/possystem/internal/tab"
 	"github.com/rustacean-dev/possystem/model"
 	"github.com/rustacean-dev/possystem/repository"
 	. "maragu.dev/gomponents/html"
@@ -26,12 +40,58 @@ func OrderRoutes(r chi.Router) {
 			return nil, nil
 		}
 
-		orders, err := repository.GetAllOrders(cookie.Value)
+		filter := orderFilterFromRequest(r)
+		orders, err := repository.GetOrders(filter, cookie.Value)
 		if err != nil {
 			return html.LoginPage("Failed to fetch orders"), nil
 		}
 
-		return html.OrderHistoryPage(orders), nil
+		return html.OrderHistoryPage(orders, filter), nil
+	}))
+
+	// Show one order with its options and notes; printable as a receipt
+	r.Get("/orders/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		order, err := repository.GetOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/orders", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return html.OrderDetailPage(order, ""), nil
+	}))
+
+	// POST /orders/{id}/status – Move an order to another status
+	r.Post("/orders/{id}/status", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		order, err := repository.GetOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/orders", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		status := r.FormValue("status")
+		if msg := checkStatus(order, status); msg != "" {
+			return html.OrderDetailPage(order, msg), nil
+		}
+		if status != order.Status {
+			if err := repository.SetOrderStatus(order, status, cookie.Value); err != nil {
+				return html.OrderDetailPage(order, "Failed to update status"), nil
+			}
+		}
+
+		http.Redirect(w, r, "/orders/"+order.ID, http.StatusSeeOther)
+		return nil, nil
 	}))
 
 	// Show the order form
@@ -42,7 +102,7 @@ func OrderRoutes(r chi.Router) {
 			return nil, nil
 		}
 
-		items, err := repository.GetAllItems(cookie.Value)
+		items, err := sellableItems(cookie.Value)
 		if err != nil {
 			return html.CreateOrderForm("Failed to fetch items", nil), nil
 		}
@@ -51,63 +111,214 @@ func OrderRoutes(r chi.Router) {
 
 	}))
 
-	// Create the order
-	r.Post("/orders", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
-		/* ---------- 1. Auth ---------- */
+	// Show the touch-friendly order entry screen
+	r.Get("/orders/pos", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
 		cookie, err := r.Cookie("token")
 		if err != nil {
 			http.Redirect(w, r, "/login", http.StatusSeeOther)
 			return nil, nil
 		}
 
-		/* ---------- 2. Parse form ---------- */
-		if err := r.ParseForm(); err != nil {
-			return html.CreateOrderForm("Invalid form", nil), nil
+		table := orderTable(r.URL.Query().Get("table"), cookie.Value)
+		open := openTab(r.URL.Query().Get("tab"), table, cookie.Value)
+		if open.Table != "" && table.ID == "" {
+			table = orderTable(open.Table, cookie.Value)
 		}
+		// ?type= starts a takeaway or delivery order
+		details := orderDetailsFromForm(r)
+		zones, _ := repository.GetZones(cookie.Value)
 
-		itemID := r.FormValue("item_id")
-		quantityStr := r.FormValue("quantity")
+		categories, err := repository.GetCategories(cookie.Value)
+		if err != nil {
+			return html.POSPage(nil, nil, "", nil, "", table, open, details, zones, "Failed to fetch categories"), nil
+		}
+		items, err := sellableItems(cookie.Value)
+		if err != nil {
+			return html.POSPage(categories, nil, "", nil, "", table, open, details, zones, "Failed to fetch items"), nil
+		}
 
-		qty, err := strconv.Atoi(quantityStr)
-		if err != nil || qty <= 0 {
-			return html.CreateOrderForm("Quantity must be ≥ 1", nil), nil
+		return html.POSPage(categories, items, r.URL.Query().Get("category"), nil, "", table, open, details, zones, ""), nil
+	}))
+
+	// Open the options dialog of an item with modifier groups (HTMX, out-of-band)
+	r.Get("/orders/pos/items/{id}/options", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			w.Header().Set("HX-Redirect", "/login")
+			return nil, nil
 		}
 
-		/* ------- 3. Fetch item ------- */
-		item, err := repository.GetItemByID(itemID, cookie.Value)
+		item, err := repository.GetItemByID(chi.URLParam(r, "id"), cookie.Value)
 		if err != nil {
-			return html.CreateOrderForm("Item not found", nil), nil
+			return html.ScanResult("", "Item not found"), nil
 		}
-		if qty > item.Quantity {
-			if item.Quantity == 0 {
-				return html.CreateOrderForm(fmt.Sprintf("'%s' is out of stock", item.Name), nil), nil
+		groups, err := repository.GetModifierGroups(cookie.Value)
+		if err != nil {
+			return html.ScanResult("", "Failed to fetch options"), nil
+		}
+
+		return html.ModifierPanel(item, modifier.ForItem(item, groups)), nil
+	}))
+
+	// Update the POS cart (HTMX fragment).
+	// The form carries the current lines plus one action: add=<item id>,
+	// or inc/dec=<line index>. Adds from the options dialog also carry the
+	// chosen options as mod-<group id> fields and a note. Lines are priced
+	// for the order type; type_changed also redraws the order type panel.
+	r.Post("/orders/cart", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			w.Header().Set("HX-Redirect", "/login")
+			return nil, nil
+		}
+
+		if err := r.ParseForm(); err != nil {
+			return html.Cart(nil, "", r.FormValue("tab"), "Invalid form"), nil
+		}
+
+		lines, _ := cartLinesFromForm(r)
+
+		details := orderDetailsFromForm(r)
+
+		items, err := sellableItems(cookie.Value)
+		if err != nil {
+			return html.Cart(resolveCart(lines, nil, details.Type), r.FormValue("tender"), r.FormValue("tab"), "Failed to fetch items"), nil
+		}
+
+		var groups []model.ModifierGroup
+		if r.FormValue("add") != "" {
+			if groups, err = repository.GetModifierGroups(cookie.Value); err != nil {
+				return html.Cart(resolveCart(lines, items, details.Type), r.FormValue("tender"), r.FormValue("tab"), "Failed to fetch options"), nil
 			}
-			return html.CreateOrderForm(fmt.Sprintf("Only %d '%s' left in stock", item.Quantity, item.Name), nil), nil
 		}
-		/* ---------- 4. Calculate & log total ---------- */
-		total := compute.OrderTotal(item.Price, qty)
-		fmt.Printf("Create order – item:%s qty:%d total:%s\n",
-			item.Name, qty, FormatTZS(total))
 
-		/* ------- 5. Persist order ------- */
-		order := model.Order{
-			Items:     []model.Item{{ID: item.ID, Name: item.Name, Price: item.Price, Quantity: qty}},
-			TotalCost: total,
-			Status:    "pending",
+		lines, msg := updateCart(lines, items, groups, r.Form)
+		cart := html.Cart(resolveCart(lines, items, details.Type), r.FormValue("tender"), r.FormValue("tab"), msg)
+
+		if r.Form.Has("type_changed") {
+			zones, _ := repository.GetZones(cookie.Value)
+			return Group{cart, html.OrderTypePanel(details, orderTable(r.FormValue("table"), cookie.Value), zones, true)}, nil
+		}
+		// Close the options dialog once its item is in the cart
+		if msg == "" && r.Form.Has("note") {
+			return Group{cart, html.ClosedModifierPanel()}, nil
+		}
+		return cart, nil
+	}))
+
+	// Add a scanned barcode or SKU to the POS cart (HTMX fragment).
+	// The scan feedback is swapped out-of-band next to the cart.
+	r.Post("/orders/cart/scan", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			w.Header().Set("HX-Redirect", "/login")
+			return nil, nil
+		}
+
+		if err := r.ParseForm(); err != nil {
+			return html.Cart(nil, "", r.FormValue("tab"), "Invalid form"), nil
 		}
 
-		if err := repository.CreateOrder(order, cookie.Value); err != nil {
-			return html.CreateOrderForm("Failed to create order", nil), nil
+		lines, _ := cartLinesFromForm(r)
+		tender := r.FormValue("tender")
+		orderType := orderDetailsFromForm(r).Type
+
+		code, err := catalog.NormalizeCode(r.FormValue("code"))
+		if err != nil || code == "" {
+			return Group{html.Cart(resolveCartItems(lines, orderType, cookie.Value), tender, r.FormValue("tab"), ""), html.ScanResult("", "Invalid code")}, nil
 		}
 
-		err = repository.UpdateItemStock(item.ID, item.Quantity-qty, cookie.Value)
+		item, err := repository.GetItemByCode(code, cookie.Value)
+		if errors.Is(err, repository.ErrNotFound) {
+			return Group{
+				html.Cart(resolveCartItems(lines, orderType, cookie.Value), tender, r.FormValue("tab"), ""),
+				html.ScanResult(code, fmt.Sprintf("No item with code %s", code)),
+			}, nil
+		}
 		if err != nil {
-			fmt.Println(" Failed to update item stock:", err)
-			// Optionally: rollback order creation, or just log the error
-			log.Println(" Failed to update item stock:", err)
+			return Group{html.Cart(resolveCartItems(lines, orderType, cookie.Value), tender, r.FormValue("tab"), "Failed to look up code"), html.ScanResult("", "")}, nil
+		}
+		if item.Archived {
+			return Group{
+				html.Cart(resolveCartItems(lines, orderType, cookie.Value), tender, r.FormValue("tab"), fmt.Sprintf("'%s' is no longer sold", item.Name)),
+				html.ScanResult("", ""),
+			}, nil
+		}
+
+		// Items with options need them chosen first
+		if len(item.ModifierGroups) > 0 {
+			groups, _ := repository.GetModifierGroups(cookie.Value)
+			return Group{
+				html.Cart(resolveCartItems(lines, orderType, cookie.Value), tender, r.FormValue("tab"), ""),
+				html.ScanResult("", ""),
+				html.ModifierPanel(item, modifier.ForItem(item, groups)),
+			}, nil
+		}
+
+		items, err := sellableItems(cookie.Value)
+		if err != nil {
+			return html.Cart(resolveCart(lines, nil, orderType), tender, r.FormValue("tab"), "Failed to fetch items"), nil
+		}
+
+		lines, msg := updateCart(lines, items, nil, url.Values{"add": {item.ID}})
+		return Group{html.Cart(resolveCart(lines, items, orderType), tender, r.FormValue("tab"), msg), html.ScanResult("", "")}, nil
+	}))
+
+	// Create the order.
+	// The single-item form posts item_id and quantity; the POS screen posts
+	// one JSON-encoded "line" field per cart line, and the order type with
+	// the details it needs.
+	r.Post("/orders", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		/* ---------- 1. Auth ---------- */
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		/* ---------- 2. Parse form ---------- */
+		if err := r.ParseForm(); err != nil {
+			return html.CreateOrderForm("Invalid form", nil), nil
+		}
+
+		tender := r.FormValue("tender")
+		if tender == "" {
+			tender = model.TenderCash
+		}
+
+		lines, msg := cartLinesFromForm(r)
+		if msg != "" {
+			return orderFormError(r, cookie.Value, lines, msg), nil
+		}
+		details, msg := orderTypeFromForm(r, time.Now())
+		if msg != "" {
+			return orderFormError(r, cookie.Value, lines, msg), nil
+		}
+
+		/* ------- 3. Place order ------- */
+		order, table, msg := placeOrder(newOrder{
+			Lines:   lines,
+			Details: details,
+			Tender:  tender,
+			Table:   r.FormValue("table"),
+			Tab:     r.FormValue("tab"),
+			OpenTab: r.FormValue("open_tab") != "",
+			TabName: r.FormValue("customer"),
+		}, cookie.Value)
+		if msg != "" {
+			return orderFormError(r, cookie.Value, lines, msg), nil
 		}
 
-		/* ---------- 6. Redirect to history ---------- */
+		if table.ID != "" {
+			http.Redirect(w, r, "/tables/"+table.ID, http.StatusSeeOther)
+			return nil, nil
+		}
+
+		/* ---------- 4. Redirect to history ---------- */
+		if order.Status == model.OrderStatusOpen {
+			http.Redirect(w, r, "/tabs/"+order.ID, http.StatusSeeOther)
+			return nil, nil
+		}
 		http.Redirect(w, r, "/orders", http.StatusSeeOther)
 		return nil, nil
 	}))
@@ -146,3 +357,543 @@ func OrderRoutes(r chi.Router) {
 	}))
 
 }
+
+// cartLinesFromForm reads the order lines of a submitted order form: either the
+// JSON "line" fields of the POS cart, or the single item_id and quantity of
+// the classic order form, with the notes of the POS cart lines. If the form is invalid it also returns a user-facing
+// message, along with whatever lines it could read.
+func cartLinesFromForm(r *http.Request) ([]model.CartLine, string) {
+	var lines []model.CartLine
+
+	if raw := r.Form["line"]; len(raw) > 0 {
+		for _, s := range raw {
+			var line model.CartLine
+			if err := json.Unmarshal([]byte(s), &line); err != nil {
+				return lines, "Invalid order line"
+			}
+			if line.Quantity <= 0 {
+				return lines, "Quantity must be ≥ 1"
+			}
+			lines = append(lines, line)
+		}
+
+		// Notes are edited in place, one field per line
+		if notes := r.Form["line_note"]; len(notes) == len(lines) {
+			for i := range lines {
+				lines[i].Note = strings.TrimSpace(notes[i])
+			}
+		}
+		return lines, ""
+	}
+
+	if r.FormValue("item_id") == "" {
+		return nil, "Your order is empty"
+	}
+
+	qty, err := strconv.Atoi(r.FormValue("quantity"))
+	if err != nil || qty <= 0 {
+		return nil, "Quantity must be ≥ 1"
+	}
+
+	return []model.CartLine{{ItemID: r.FormValue("item_id"), Quantity: qty}}, ""
+}
+
+// checkStatus checks that an order can be moved to status by hand. Each
+// order type has its own steps, deliveries go out and come back through
+// the dispatch board, and open tabs are closed by paying them. It returns
+// a user-facing message if not.
+func checkStatus(o model.Order, status string) string {
+	if !slices.Contains(model.OrderStatuses, status) {
+		return "Unknown status"
+	}
+	if o.Status == model.OrderStatusOpen && !slices.Contains(ordertype.Statuses(o), status) {
+		return "Open tabs can only be cancelled here; close the tab from its page to take payment"
+	}
+	if !slices.Contains(ordertype.Statuses(o), status) {
+		return fmt.Sprintf("%s orders cannot be marked %s", ordertype.Label(o.Type), strings.ToLower(ordertype.StepLabel(o.Type, status)))
+	}
+	if dispatch.ViaBoard(o, status) {
+		return "Use the dispatch board to send deliveries out and mark them delivered"
+	}
+	return ""
+}
+
+// newOrder is an order to place, from the POS form or the API.
+type newOrder struct {
+	Lines []model.CartLine
+	// Details are the order type and its details, checked by [orderType].
+	Details model.Order
+	Tender  string
+	// Table is the table a dine-in order is for. Tab is the open tab the
+	// lines are a new round for; OpenTab keeps a new order open as a tab
+	// named TabName instead.
+	Table   string
+	Tab     string
+	OpenTab bool
+	TabName string
+}
+
+// placeOrder prices and saves an order: options and prices are taken from
+// the menu, stock is checked and taken, the lines go to the kitchen, and a
+// dine-in table is seated. It returns the saved order, or the tab the
+// lines were added to, and the table it is for. If the order cannot be
+// placed it returns a user-facing message instead.
+func placeOrder(n newOrder, token string) (model.Order, model.Table, string) {
+	lines, details, tender := n.Lines, n.Details, n.Tender
+	var err error
+
+	/* ------- 1. Fetch items & check stock ------- */
+	items := map[string]model.Item{}
+	wanted := map[string]int{}
+	var groups []model.ModifierGroup
+	var orderLines []model.Item
+	for _, line := range lines {
+		item, ok := items[line.ItemID]
+		if !ok {
+			item, err = repository.GetItemByID(line.ItemID, token)
+			if err != nil {
+				return model.Order{}, model.Table{}, "Item not found"
+			}
+			items[item.ID] = item
+		}
+		if item.Archived {
+			return model.Order{}, model.Table{}, fmt.Sprintf("'%s' is no longer sold", item.Name)
+		}
+
+		// Items with a recipe are checked against their ingredients below
+		wanted[item.ID] += line.Quantity
+		if len(item.Recipe) == 0 && wanted[item.ID] > item.Quantity {
+			if item.Quantity == 0 {
+				return model.Order{}, model.Table{}, fmt.Sprintf("'%s' is out of stock", item.Name)
+			}
+			return model.Order{}, model.Table{}, fmt.Sprintf("Only %d '%s' left in stock", item.Quantity, item.Name)
+		}
+
+		// Take option names and prices from the menu, not from the form
+		var mods []model.Modifier
+		if len(item.ModifierGroups) > 0 || len(line.Modifiers) > 0 {
+			if groups == nil {
+				if groups, err = repository.GetModifierGroups(token); err != nil {
+					return model.Order{}, model.Table{}, "Failed to fetch options"
+				}
+			}
+			mods, err = modifier.Choose(modifier.ForItem(item, groups), modifier.Picks(line.Modifiers))
+			if err != nil {
+				return model.Order{}, model.Table{}, fmt.Sprintf("'%s': %s", item.Name, err)
+			}
+		}
+
+		// Cost and category are kept on the line so margin reports
+		// show what the sale earned at the time
+		orderLines = append(orderLines, model.Item{
+			ID:        item.ID,
+			Name:      item.Name,
+			Price:     ordertype.Price(item, details.Type),
+			Cost:      item.Cost,
+			Category:  item.Category,
+			Quantity:  line.Quantity,
+			Modifiers: mods,
+			Note:      line.Note,
+		})
+	}
+
+	used := recipe.Usage(items, wanted)
+	var ingredients []model.Ingredient
+	if len(used) > 0 {
+		if ingredients, err = repository.GetIngredients(token); err != nil {
+			return model.Order{}, model.Table{}, "Failed to fetch ingredients"
+		}
+		if short := recipe.Shortages(used, ingredients); len(short) > 0 {
+			return model.Order{}, model.Table{}, "Not enough " + strings.Join(short, ", ") + " for this order"
+		}
+	}
+
+	// Dine-in orders go to the table running the party
+	var table model.Table
+	if id := n.Table; id != "" {
+		if table = orderTable(id, token); table.ID == "" {
+			return model.Order{}, model.Table{}, "Table not found"
+		}
+	}
+
+	// Deliveries to a zone pay the zone's fee, which may be waived on big orders
+	if details.Zone != "" {
+		zone, err := repository.GetZoneByID(details.Zone, token)
+		if err != nil {
+			return model.Order{}, model.Table{}, "Delivery zone not found"
+		}
+		details.DeliveryFee = dispatch.Fee(zone, compute.Subtotal(orderLines))
+	}
+
+	/* ---------- 2. Calculate & log total ---------- */
+	// Delivery fees are charged on top of the lines
+	total := compute.Subtotal(orderLines) + details.DeliveryFee
+	fmt.Printf("Create order – lines:%d total:%s\n", len(orderLines), FormatTZS(total))
+
+	/* ------- 3. Persist order ------- */
+	var order model.Order
+	switch {
+	case n.Tab != "":
+		// A new round on an open tab. If another round was added at the
+		// same time, this one is added again on top of it
+		// The parts of a split bill add up to the tab as it was split, so a
+		// round on top would never be paid
+		payments, err := repository.GetOrderPayments(n.Tab, token)
+		if err != nil {
+			return model.Order{}, model.Table{}, "Failed to fetch the tab's payments"
+		}
+		if split.Started(payments) {
+			return model.Order{}, model.Table{}, "Part of this tab's bill is paid already; start a new tab for more rounds"
+		}
+		if len(payments) > 0 {
+			return model.Order{}, model.Table{}, "This tab's bill is split; undo the split before adding a round"
+		}
+
+		round := orderLines
+		for attempt := 1; ; attempt++ {
+			read, err := repository.GetOrderByID(n.Tab, token)
+			if err != nil {
+				return model.Order{}, model.Table{}, "Tab not found"
+			}
+			if order, orderLines, err = tab.Add(read, round); err != nil {
+				return model.Order{}, model.Table{}, "Cannot add round: " + err.Error()
+			}
+			err = repository.SaveTabRound(read, order, token)
+			if err == nil {
+				break
+			}
+			if !errors.Is(err, repository.ErrConflict) || attempt == 3 {
+				return model.Order{}, model.Table{}, "Failed to add round"
+			}
+		}
+		if table.ID == "" {
+			table = orderTable(order.Table, token)
+		}
+
+	default:
+		userID, _ := repository.TokenUserID(token)
+		order = details
+		order.UserID = userID
+		order.Items = orderLines
+		order.TotalCost = total
+		order.Tender = tender
+		order.Status = model.OrderStatusPending
+		order.Table = table.ID
+
+		// Tabs stay open for more rounds and are paid when closed
+		if n.OpenTab {
+			if details.Type != "" && details.Type != model.OrderDineIn {
+				return model.Order{}, model.Table{}, "Only counter and dine-in orders can be kept open as a tab"
+			}
+			opened := tab.Open(n.TabName, table.ID, userID)
+			opened.Type = details.Type
+			order, orderLines, _ = tab.Add(opened, orderLines)
+		}
+		if err := ordertype.Validate(order); err != nil {
+			return model.Order{}, model.Table{}, "Cannot place order: " + err.Error()
+		}
+
+		order, err = repository.CreateOrder(order, token)
+		if err != nil {
+			return model.Order{}, model.Table{}, "Failed to create order"
+		}
+	}
+
+	// Made-to-order items use up their ingredients, the rest their own stock
+	for id, qty := range used {
+		err := repository.ApplyStockMovement(model.StockMovement{
+			Ingredient: id,
+			Kind:       model.MovementSale,
+			Quantity:   -qty,
+			Order:      order.ID,
+		}, token)
+		if err != nil {
+			log.Println(" Failed to update ingredient stock:", err)
+		}
+	}
+	for id, qty := range wanted {
+		if len(items[id].Recipe) > 0 {
+			continue
+		}
+		err = repository.ApplyStockMovement(model.StockMovement{
+			Item:     id,
+			Kind:     model.MovementSale,
+			Quantity: float64(-qty),
+			Order:    order.ID,
+		}, token)
+		if err != nil {
+			fmt.Println(" Failed to update item stock:", err)
+			// Optionally: rollback order creation, or just log the error
+			log.Println(" Failed to update item stock:", err)
+			continue
+		}
+		if items[id].Perishable {
+			consumeLots(id, qty, token)
+		}
+	}
+
+	// Each round of a tab is a ticket of its own
+	sendToKitchen(model.Order{ID: order.ID, Items: orderLines}, token)
+
+	if table.ID != "" {
+		if err := repository.SetTableState(floor.Receive(table, order, time.Now()), token); err != nil {
+			log.Println(" Failed to seat table:", err)
+		}
+	}
+	return order, table, ""
+}
+
+// orderFormError re-renders the form an order was placed from with an error:
+// the POS screen with its cart intact, or the classic order form.
+func orderFormError(r *http.Request, token string, lines []model.CartLine, msg string) Node {
+	if r.FormValue("from") != "pos" {
+		items, _ := sellableItems(token)
+		return html.CreateOrderForm(msg, items)
+	}
+
+	categories, _ := repository.GetCategories(token)
+	items, _ := sellableItems(token)
+	table := orderTable(r.FormValue("table"), token)
+	open := openTab(r.FormValue("tab"), model.Table{}, token)
+	if open.Table != "" && table.ID == "" {
+		table = orderTable(open.Table, token)
+	}
+	details := orderDetailsFromForm(r)
+	zones, _ := repository.GetZones(token)
+	return html.POSPage(categories, items, "", resolveCart(lines, items, details.Type), r.FormValue("tender"), table, open, details, zones, msg)
+}
+
+// openTab looks up the open tab a new round is for: the tab with the given
+// ID, or else the open tab at the table. It returns the zero order if there
+// is none.
+func openTab(id string, table model.Table, token string) model.Order {
+	if id != "" {
+		o, err := repository.GetOrderByID(id, token)
+		if err != nil || o.Status != model.OrderStatusOpen {
+			return model.Order{}
+		}
+		return o
+	}
+	if table.ID == "" {
+		return model.Order{}
+	}
+	tabs, err := repository.GetOpenTabs(token)
+	if err != nil {
+		return model.Order{}
+	}
+	for _, o := range tabs {
+		if o.Table == table.ID {
+			return o
+		}
+	}
+	return model.Order{}
+}
+
+// orderDetailsFromForm reads the order type and its details from a POS
+// form as entered, for the form to show them again. Orders for a table are
+// dine-in, and unknown types are counter sales.
+func orderDetailsFromForm(r *http.Request) model.Order {
+	fee, _ := strconv.ParseFloat(strings.TrimSpace(r.FormValue("delivery_fee")), 64)
+	d := model.Order{
+		Type:        r.FormValue("type"),
+		Customer:    strings.TrimSpace(r.FormValue("customer")),
+		PickupAt:    strings.TrimSpace(r.FormValue("pickup_at")),
+		Address:     strings.TrimSpace(r.FormValue("address")),
+		Phone:       strings.TrimSpace(r.FormValue("phone")),
+		Zone:        r.FormValue("zone"),
+		DeliveryFee: fee,
+	}
+	if r.FormValue("table") != "" {
+		d.Type = model.OrderDineIn
+	}
+	if !ordertype.Valid(d.Type) {
+		d.Type = ""
+	}
+	return d
+}
+
+// orderTypeFromForm reads the order type and the details it needs from a
+// POS order form, ready to be saved, as [orderType] does. If the form is
+// invalid it also returns a user-facing message.
+func orderTypeFromForm(r *http.Request, now time.Time) (model.Order, string) {
+	if !ordertype.Valid(r.FormValue("type")) {
+		return model.Order{}, "Unknown order type"
+	}
+	return orderType(orderDetailsFromForm(r), r.FormValue("delivery_fee"), now)
+}
+
+// orderType checks the order type and details as entered and makes them
+// ready to be saved: the pickup time of a takeaway and the fee of a
+// delivery are parsed, and details of other types dropped. The fee of a
+// delivery to a zone is left for [placeOrder] to work out from the zone.
+// If the details are invalid it also returns a user-facing message.
+func orderType(entered model.Order, fee string, now time.Time) (model.Order, string) {
+	d := model.Order{Type: entered.Type}
+
+	switch d.Type {
+	case model.OrderTakeaway:
+		at, err := ordertype.ParsePickup(entered.PickupAt, now)
+		if err != nil {
+			return d, "Please enter a valid pickup time, e.g. 18:30"
+		}
+		d.Customer = entered.Customer
+		d.PickupAt = model.FormatTime(at)
+	case model.OrderDelivery:
+		d.Customer = entered.Customer
+		d.Address = entered.Address
+		d.Phone = entered.Phone
+		if entered.Zone != "" {
+			d.Zone = entered.Zone
+			break
+		}
+		parsed, err := ordertype.ParseFee(fee)
+		if err != nil {
+			return d, "Please enter a valid delivery fee"
+		}
+		d.DeliveryFee = parsed
+	}
+	return d, ""
+}
+
+// resolveCart turns cart lines into order lines with item names and the
+// prices of the order type, dropping lines whose item no longer exists.
+func resolveCart(lines []model.CartLine, items []model.Item, orderType string) []model.Item {
+	byID := map[string]model.Item{}
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+
+	var res []model.Item
+	for _, line := range lines {
+		it, ok := byID[line.ItemID]
+		if !ok {
+			continue
+		}
+		res = append(res, model.Item{
+			ID:        it.ID,
+			Name:      it.Name,
+			Price:     ordertype.Price(it, orderType),
+			Quantity:  line.Quantity,
+			Category:  it.Category,
+			Modifiers: line.Modifiers,
+			Note:      line.Note,
+		})
+	}
+	return res
+}
+
+// modifierPicks reads the options chosen in the options dialog: the option
+// names of each mod-<group id> field.
+func modifierPicks(form url.Values) map[string][]string {
+	picks := map[string][]string{}
+	for key, values := range form {
+		if id, ok := strings.CutPrefix(key, "mod-"); ok {
+			picks[id] = values
+		}
+	}
+	return picks
+}
+
+// sellableItems fetches the items that can be ordered. Items with a recipe
+// get the number of portions their ingredients allow as their quantity, so
+// they show as sold out once any ingredient runs out.
+func sellableItems(token string) ([]model.Item, error) {
+	items, err := repository.GetAllItems(token)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, it := range items {
+		if len(it.Recipe) > 0 {
+			ingredients, err := repository.GetIngredients(token)
+			if err != nil {
+				return nil, err
+			}
+			recipe.Apply(items, ingredients)
+			break
+		}
+	}
+	return items, nil
+}
+
+// resolveCartItems is resolveCart with the items fetched from the repository.
+func resolveCartItems(lines []model.CartLine, orderType, token string) []model.Item {
+	items, _ := sellableItems(token)
+	return resolveCart(lines, items, orderType)
+}
+
+// updateCart applies one cart action from the form to the lines. Adding an
+// item that is already in the cart with the same options and no note
+// increases its quantity; decreasing a line to zero removes it. Options are
+// checked against the item's modifier groups. It returns a user-facing
+// message if the action was refused.
+func updateCart(lines []model.CartLine, items []model.Item, groups []model.ModifierGroup, form url.Values) ([]model.CartLine, string) {
+	stock := map[string]model.Item{}
+	for _, it := range items {
+		stock[it.ID] = it
+	}
+	inCart := func(itemID string) int {
+		n := 0
+		for _, l := range lines {
+			if l.ItemID == itemID {
+				n += l.Quantity
+			}
+		}
+		return n
+	}
+	index := func(key string) (int, bool) {
+		i, err := strconv.Atoi(form.Get(key))
+		return i, err == nil && i >= 0 && i < len(lines)
+	}
+
+	switch {
+	case form.Get("add") != "":
+		id := form.Get("add")
+		it, ok := stock[id]
+		if !ok {
+			return lines, "Item not found"
+		}
+		if inCart(id) >= it.Quantity {
+			return lines, fmt.Sprintf("Only %d '%s' left in stock", it.Quantity, it.Name)
+		}
+
+		mods, err := modifier.Choose(modifier.ForItem(it, groups), modifierPicks(form))
+		if err != nil {
+			return lines, fmt.Sprintf("'%s': %s", it.Name, err)
+		}
+		note := strings.TrimSpace(form.Get("note"))
+
+		if note == "" {
+			for i := range lines {
+				if lines[i].ItemID == id && lines[i].Note == "" && modifier.Same(lines[i].Modifiers, mods) {
+					lines[i].Quantity++
+					return lines, ""
+				}
+			}
+		}
+		return append(lines, model.CartLine{ItemID: id, Quantity: 1, Modifiers: mods, Note: note}), ""
+
+	case form.Has("inc"):
+		i, ok := index("inc")
+		if !ok {
+			return lines, ""
+		}
+		it := stock[lines[i].ItemID]
+		if inCart(it.ID) >= it.Quantity {
+			return lines, fmt.Sprintf("Only %d '%s' left in stock", it.Quantity, it.Name)
+		}
+		lines[i].Quantity++
+
+	case form.Has("dec"):
+		i, ok := index("dec")
+		if !ok {
+			return lines, ""
+		}
+		lines[i].Quantity--
+		if lines[i].Quantity <= 0 {
+			lines = append(lines[:i], lines[i+1:]...)
+		}
+	}
+
+	return lines, ""
+}
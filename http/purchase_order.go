@@ -0,0 +1,365 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/lot"
+	"github.com/rustacean-dev/possystem/internal/purchasing"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// PurchaseOrderRoutes registers purchase orders: drafting them, marking
+// them sent or cancelled, and receiving deliveries into stock.
+func PurchaseOrderRoutes(r chi.Router) {
+	// GET /purchase-orders – List purchase orders, optionally by status
+	r.Get("/purchase-orders", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return purchaseOrderListPage(r.URL.Query().Get("status"), cookie.Value, ""), nil
+	}))
+
+	// POST /purchase-orders – Start a draft, optionally with lines from the reorder list
+	r.Post("/purchase-orders", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		if err := r.ParseForm(); err != nil {
+			return purchaseOrderListPage("", cookie.Value, "Invalid form submission"), nil
+		}
+		po := model.PurchaseOrder{
+			Supplier: strings.TrimSpace(r.FormValue("supplier")),
+			Note:     strings.TrimSpace(r.FormValue("note")),
+		}
+		if po.Supplier == "" {
+			return purchaseOrderListPage("", cookie.Value, "Please choose a supplier"), nil
+		}
+
+		if len(r.Form["line_item"]) > 0 {
+			items, err := repository.GetAllItems(cookie.Value)
+			if err != nil {
+				return purchaseOrderListPage("", cookie.Value, "Failed to fetch items"), nil
+			}
+			po.Lines, err = purchasing.ParseLines(r.Form["line_item"], r.Form["line_quantity"], r.Form["line_cost"], itemsByID(items))
+			if err != nil {
+				return purchaseOrderListPage("", cookie.Value, "Invalid line: "+err.Error()), nil
+			}
+		}
+
+		saved, err := repository.CreatePurchaseOrder(po, cookie.Value)
+		if err != nil {
+			return purchaseOrderListPage("", cookie.Value, "Failed to create purchase order"), nil
+		}
+
+		http.Redirect(w, r, "/purchase-orders/"+saved.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// GET /purchase-orders/{id} – Draft editor or order summary
+	r.Get("/purchase-orders/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		po, err := repository.GetPurchaseOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/purchase-orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		return purchaseOrderPage(po, cookie.Value, ""), nil
+	}))
+
+	// POST /purchase-orders/{id} – Save a draft
+	r.Post("/purchase-orders/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		po, err := repository.GetPurchaseOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/purchase-orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		if po.Status != model.PurchaseDraft {
+			return purchaseOrderPage(po, cookie.Value, "Only drafts can be edited"), nil
+		}
+		if err := r.ParseForm(); err != nil {
+			return purchaseOrderPage(po, cookie.Value, "Invalid form submission"), nil
+		}
+
+		items, err := repository.GetAllItems(cookie.Value)
+		if err != nil {
+			return purchaseOrderPage(po, cookie.Value, "Failed to fetch items"), nil
+		}
+		lines, err := purchasing.ParseLines(r.Form["line_item"], r.Form["line_quantity"], r.Form["line_cost"], itemsByID(items))
+		if err != nil {
+			return purchaseOrderPage(po, cookie.Value, "Invalid line: "+err.Error()), nil
+		}
+
+		po.Supplier = strings.TrimSpace(r.FormValue("supplier"))
+		po.Note = strings.TrimSpace(r.FormValue("note"))
+		po.Lines = lines
+		if po.Supplier == "" {
+			return purchaseOrderPage(po, cookie.Value, "Please choose a supplier"), nil
+		}
+
+		if err := repository.UpdatePurchaseOrder(po, cookie.Value); err != nil {
+			return purchaseOrderPage(po, cookie.Value, "Failed to save purchase order"), nil
+		}
+
+		http.Redirect(w, r, "/purchase-orders/"+po.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /purchase-orders/{id}/send – Mark a draft as sent to the supplier
+	r.Post("/purchase-orders/{id}/send", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		po, err := repository.GetPurchaseOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/purchase-orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		if !purchasing.CanSend(po) {
+			return purchaseOrderPage(po, cookie.Value, "Add at least one line before sending"), nil
+		}
+
+		po.Status = model.PurchaseSent
+		po.SentAt = model.FormatTime(time.Now())
+		if err := repository.UpdatePurchaseOrder(po, cookie.Value); err != nil {
+			return purchaseOrderPage(po, cookie.Value, "Failed to update purchase order"), nil
+		}
+
+		http.Redirect(w, r, "/purchase-orders/"+po.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /purchase-orders/{id}/cancel – Cancel an order that is not fully received
+	r.Post("/purchase-orders/{id}/cancel", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		po, err := repository.GetPurchaseOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/purchase-orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		if !purchasing.CanCancel(po) {
+			return purchaseOrderPage(po, cookie.Value, "This purchase order can no longer be cancelled"), nil
+		}
+
+		po.Status = model.PurchaseCancelled
+		if err := repository.UpdatePurchaseOrder(po, cookie.Value); err != nil {
+			return purchaseOrderPage(po, cookie.Value, "Failed to update purchase order"), nil
+		}
+
+		http.Redirect(w, r, "/purchase-orders/"+po.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// GET /purchase-orders/{id}/receive – Receiving screen
+	r.Get("/purchase-orders/{id}/receive", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		po, err := repository.GetPurchaseOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/purchase-orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		if !purchasing.CanReceive(po) {
+			http.Redirect(w, r, "/purchase-orders/"+po.ID, http.StatusSeeOther)
+			return nil, nil
+		}
+		return receivePage(po, cookie.Value, ""), nil
+	}))
+
+	// POST /purchase-orders/{id}/receive – Book a delivery into stock
+	r.Post("/purchase-orders/{id}/receive", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		po, err := repository.GetPurchaseOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/purchase-orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		if err := r.ParseForm(); err != nil {
+			return receivePage(po, cookie.Value, "Invalid form submission"), nil
+		}
+
+		fields := map[string]string{}
+		for key := range r.PostForm {
+			if itemID, ok := strings.CutPrefix(key, "receive-"); ok {
+				fields[itemID] = r.PostForm.Get(key)
+			}
+		}
+		amounts, err := purchasing.ParseAmounts(fields)
+		if err != nil {
+			return receivePage(po, cookie.Value, err.Error()), nil
+		}
+		// Checked as a whole first, so nothing is booked for a delivery that
+		// cannot be received
+		if _, err := purchasing.Receive(po, amounts); err != nil {
+			return receivePage(po, cookie.Value, "Cannot receive: "+err.Error()), nil
+		}
+
+		// Perishable deliveries start a lot with the expiry date entered
+		expiries := map[string]string{}
+		for _, l := range po.Lines {
+			expires, err := lot.ParseExpiry(r.FormValue("expires-" + l.Item))
+			if err != nil {
+				return receivePage(po, cookie.Value, "Invalid expiry date for "+l.Name), nil
+			}
+			expiries[l.Item] = expires
+		}
+
+		reason := "Received on purchase order"
+		if ref := strings.TrimSpace(r.FormValue("reference")); ref != "" {
+			reason += ", delivery note " + ref
+		}
+		// Each line is marked received on the order before its stock is
+		// booked, so retrying after a failure cannot book it twice
+		current := po
+		var costFailed []string
+		for _, l := range po.Lines {
+			n := amounts[l.Item]
+			if n == 0 {
+				continue
+			}
+			// The cost is averaged with the stock on hand before it grows
+			item, err := repository.GetItemByID(l.Item, cookie.Value)
+			if err != nil {
+				return receivePage(current, cookie.Value, "Failed to fetch "+l.Name), nil
+			}
+			next, err := purchasing.Receive(current, map[string]int{l.Item: n})
+			if err != nil {
+				return receivePage(current, cookie.Value, "Cannot receive: "+err.Error()), nil
+			}
+			if err := repository.UpdatePurchaseOrder(next, cookie.Value); err != nil {
+				return receivePage(current, cookie.Value, "Failed to update the purchase order for "+l.Name), nil
+			}
+			current = next
+
+			err = repository.ApplyStockMovement(model.StockMovement{
+				Item:          l.Item,
+				Kind:          model.MovementRestock,
+				Quantity:      float64(n),
+				Reason:        reason,
+				PurchaseOrder: po.ID,
+			}, cookie.Value)
+			if err != nil {
+				return purchaseOrderPage(current, cookie.Value, fmt.Sprintf("%s is marked received, but its stock failed to be added; add %d on its stock page", l.Name, n)), nil
+			}
+			if item.Perishable {
+				err := repository.CreateLot(model.Lot{
+					Item:          l.Item,
+					Quantity:      n,
+					Received:      model.FormatTime(time.Now()),
+					Expires:       expiries[l.Item],
+					PurchaseOrder: po.ID,
+				}, cookie.Value)
+				if err != nil {
+					log.Println("Failed to record lot:", err)
+				}
+			}
+			cost := purchasing.AverageCost(item.Quantity, item.Cost, n, l.Cost)
+			if err := repository.SetItemCost(l.Item, cost, cookie.Value); err != nil {
+				log.Println("Failed to update item cost:", err)
+				costFailed = append(costFailed, fmt.Sprintf("%s (%.2f)", l.Name, cost))
+			}
+		}
+		if len(costFailed) > 0 {
+			return purchaseOrderPage(current, cookie.Value, "Stock was added, but the average cost failed to be saved for "+strings.Join(costFailed, ", ")+"; set it on the item page"), nil
+		}
+
+		http.Redirect(w, r, "/purchase-orders/"+po.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// purchaseOrderListPage fetches the purchase orders and suppliers for
+// [html.PurchaseOrderListPage].
+func purchaseOrderListPage(status, token, msg string) Node {
+	suppliers, _ := repository.GetSuppliers(token)
+	orders, err := repository.GetPurchaseOrders(status, token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch purchase orders"
+	}
+	return html.PurchaseOrderListPage(orders, suppliers, status, msg)
+}
+
+// purchaseOrderPage fetches what [html.PurchaseOrderPage] needs besides the
+// order itself: items and suppliers for drafts, deliveries otherwise.
+func purchaseOrderPage(po model.PurchaseOrder, token, msg string) Node {
+	if po.Status == model.PurchaseDraft {
+		// Recipe items are restocked through their ingredients
+		all, _ := repository.GetAllItems(token)
+		var items []model.Item
+		for _, it := range all {
+			if len(it.Recipe) == 0 {
+				items = append(items, it)
+			}
+		}
+		suppliers, _ := repository.GetSuppliers(token)
+		return html.PurchaseOrderPage(po, items, suppliers, nil, msg)
+	}
+
+	deliveries, err := repository.GetPurchaseOrderMovements(po.ID, token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch deliveries"
+	}
+	return html.PurchaseOrderPage(po, nil, nil, deliveries, msg)
+}
+
+// receivePage fetches which lines are perishable for [html.ReceivePage].
+func receivePage(po model.PurchaseOrder, token, msg string) Node {
+	perishable := map[string]bool{}
+	items, _ := repository.GetAllItems(token)
+	for _, it := range items {
+		perishable[it.ID] = it.Perishable
+	}
+	return html.ReceivePage(po, perishable, msg)
+}
+
+// itemsByID indexes items by their ID.
+func itemsByID(items []model.Item) map[string]model.Item {
+	byID := make(map[string]model.Item, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+	return byID
+}
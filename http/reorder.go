@@ -0,0 +1,37 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/reorder"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// ReorderRoutes registers the reorder list.
+func ReorderRoutes(r chi.Router) {
+	// GET /reorder – Items at or below their reorder point, by supplier
+	r.Get("/reorder", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		// Built from current stock rather than the last background check
+		items, err := repository.GetAllItems(cookie.Value)
+		if err != nil {
+			return html.ReorderPage(nil, "Failed to fetch items"), nil
+		}
+		suppliers, err := repository.GetSuppliers(cookie.Value)
+		if err != nil {
+			return html.ReorderPage(nil, "Failed to fetch suppliers"), nil
+		}
+
+		return html.ReorderPage(reorder.List(items, suppliers), ""), nil
+	}))
+}
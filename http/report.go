@@ -0,0 +1,241 @@
+package http
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/margin"
+	"github.com/rustacean-dev/possystem/internal/report"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// ReportRoutes registers the X-report (mid-day snapshot) and Z-report
+// (end-of-day closing) pages and their CSV exports, and the gross margin
+// report.
+func ReportRoutes(r chi.Router) {
+	// GET /reports/x – Sales since the last Z-report, nothing is saved
+	r.Get("/reports/x", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		summary, _, err := currentSummary(cookie.Value)
+		if err != nil {
+			return html.XReportPage(model.SalesSummary{}, "Failed to build report"), nil
+		}
+
+		return html.XReportPage(summary, ""), nil
+	}))
+
+	// GET /reports/x/export.csv – The current X-report as CSV
+	r.Get("/reports/x/export.csv", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		summary, _, err := currentSummary(cookie.Value)
+		if err != nil {
+			http.Error(w, "Failed to build report", http.StatusBadGateway)
+			return
+		}
+
+		writeSummaryCSV(w, "x-report-"+time.Now().Format("20060102-1504")+".csv", summary)
+	})
+
+	// GET /reports/z – All closed business days
+	r.Get("/reports/z", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		reports, err := repository.GetZReports(cookie.Value)
+		if err != nil {
+			return html.ZReportListPage(nil, "Failed to fetch Z-reports"), nil
+		}
+
+		return html.ZReportListPage(reports, ""), nil
+	}))
+
+	// POST /reports/z – Close the business day and freeze its numbers
+	r.Post("/reports/z", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		summary, last, err := currentSummary(cookie.Value)
+		if err != nil {
+			return html.XReportPage(model.SalesSummary{}, "Failed to build report"), nil
+		}
+
+		userID, _ := repository.TokenUserID(cookie.Value)
+
+		z, err := repository.CreateZReport(model.ZReport{
+			Sequence: last.Sequence + 1,
+			OpenedAt: summary.From,
+			ClosedAt: summary.To,
+			ClosedBy: userID,
+			Summary:  summary,
+		}, cookie.Value)
+		if err != nil {
+			return html.XReportPage(summary, "Failed to close the day"), nil
+		}
+
+		w.Header().Set("HX-Redirect", "/reports/z/"+z.ID)
+		return nil, nil
+	}))
+
+	// GET /reports/z/{id} – A closed business day
+	r.Get("/reports/z/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		z, err := repository.GetZReportByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			return html.ZReportListPage(nil, "Z-report not found"), nil
+		}
+
+		return html.ZReportPage(z), nil
+	}))
+
+	// GET /reports/z/{id}/export.csv – A closed business day as CSV
+	r.Get("/reports/z/{id}/export.csv", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		z, err := repository.GetZReportByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Error(w, "Z-report not found", http.StatusNotFound)
+			return
+		}
+
+		writeSummaryCSV(w, fmt.Sprintf("z-report-%d.csv", z.Sequence), z.Summary)
+	})
+
+	// GET /reports/margin – Gross margin by item, category and day
+	r.Get("/reports/margin", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		// The last 30 days unless a range is given
+		filter := orderFilterFromRequest(r)
+		filter.Status = ""
+		if filter.From == "" && filter.To == "" {
+			now := time.Now()
+			filter.From = now.AddDate(0, 0, -29).Format("2006-01-02")
+			filter.To = now.Format("2006-01-02")
+		}
+
+		orders, err := repository.GetOrders(filter, cookie.Value)
+		if err != nil {
+			return html.MarginReportPage(margin.Report{}, filter, "Failed to fetch orders"), nil
+		}
+
+		categoryNames := map[string]string{}
+		categories, _ := repository.GetCategories(cookie.Value)
+		for _, c := range categories {
+			categoryNames[c.ID] = c.Name
+		}
+		items, _ := repository.GetAllItems(cookie.Value)
+
+		return html.MarginReportPage(margin.Build(orders, categoryNames, itemsByID(items), time.Local), filter, ""), nil
+	}))
+}
+
+// currentSummary totals every order placed since the last Z-report.
+// It also returns that last Z-report, which is empty if no day was closed yet.
+func currentSummary(token string) (model.SalesSummary, model.ZReport, error) {
+	last, err := repository.GetLatestZReport(token)
+	if err != nil {
+		return model.SalesSummary{}, model.ZReport{}, err
+	}
+
+	var from time.Time
+	if last.ID != "" {
+		from, _ = model.ParseTime(last.ClosedAt)
+	}
+	to := time.Now()
+
+	// The filter works in whole days; Between trims to the exact window.
+	filter := model.OrderFilter{To: to.Format("2006-01-02")}
+	if !from.IsZero() {
+		filter.From = from.In(time.Local).Format("2006-01-02")
+	}
+	orders, err := repository.GetOrders(filter, token)
+	if err != nil {
+		return model.SalesSummary{}, model.ZReport{}, err
+	}
+
+	summary := report.Summarize(report.Between(orders, from, to))
+	if !from.IsZero() {
+		summary.From = model.FormatTime(from)
+	}
+	summary.To = model.FormatTime(to)
+
+	return summary, last, nil
+}
+
+// writeSummaryCSV writes a sales summary as a downloadable CSV file.
+func writeSummaryCSV(w http.ResponseWriter, filename string, s model.SalesSummary) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"section", "name", "orders", "amount"})
+	_ = cw.Write([]string{"period", "from", "", s.From})
+	_ = cw.Write([]string{"period", "to", "", s.To})
+
+	totals := []struct {
+		name   string
+		amount float64
+	}{
+		{"gross", s.Gross},
+		{"refunds", s.Refunds},
+		{"net", s.Net},
+	}
+	for _, t := range totals {
+		_ = cw.Write([]string{"totals", t.name, "", fmt.Sprintf("%.2f", t.amount)})
+	}
+	_ = cw.Write([]string{"totals", "orders", fmt.Sprint(s.OrderCount), ""})
+	_ = cw.Write([]string{"totals", "items", fmt.Sprint(s.ItemCount), ""})
+
+	sections := []struct {
+		name string
+		rows []model.Breakdown
+	}{
+		{"tender", s.ByTender},
+		{"cashier", s.ByCashier},
+		{"status", s.ByStatus},
+	}
+	for _, sec := range sections {
+		for _, b := range sec.rows {
+			_ = cw.Write([]string{sec.name, b.Name, fmt.Sprint(b.Orders), fmt.Sprintf("%.2f", b.Amount)})
+		}
+	}
+
+	cw.Flush()
+}
@@ -17,7 +17,7 @@ func (s *Server) setupRoutes() {
 		})
 
 		r.Get("/swagger/*", httpSwagger.Handler(
-			httpSwagger.URL("http://localhost:8080/swagger/doc.json"), //The url pointing to API definition
+			httpSwagger.URL("/swagger/doc.json"), //The url pointing to API definition
 		))
 
 		// r.Get("/docs/*", httpSwagger.Handler(
@@ -27,7 +27,27 @@ func (s *Server) setupRoutes() {
 		Home(r)
 		Auth(r)
 		OrderRoutes(r)
+		KitchenRoutes(r)
+		TableRoutes(r)
+		TabRoutes(r)
+		SplitRoutes(r)
+		DispatchRoutes(r)
+		EventRoutes(r)
 		ItemRoutes(r)
+		ItemImportRoutes(r)
+		CategoryRoutes(r)
+		ModifierRoutes(r)
+		IngredientRoutes(r)
+		StockRoutes(r)
+		StocktakeRoutes(r)
+		SupplierRoutes(r)
+		ReorderRoutes(r)
+		PurchaseOrderRoutes(r)
+		LotRoutes(r)
+		ReportRoutes(r)
+		ExportRoutes(r)
+		APIKeyRoutes(r)
+		APIRoutes(r)
 
 	})
 }
@@ -9,6 +9,11 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/internal/reorder"
+	"github.com/rustacean-dev/possystem/repository"
 )
 
 type Server struct {
@@ -20,6 +25,17 @@ type Server struct {
 type NewServerOptions struct {
 	Mux chi.Router
 	Log *slog.Logger
+	// Alerts, if set, feed the low-stock banner shown on every page.
+	Alerts *reorder.Alerts
+	// Events, if set, is the bus order, stock and kitchen changes are
+	// published on, so other parts of the app can subscribe to it.
+	Events *event.Bus
+	// ServiceToken, if set, logs in as the service user API keys are looked
+	// up as. KeyUserToken, if set, logs in as the user requests made with
+	// API keys act as, which must not be an admin. Without both API keys
+	// are refused.
+	ServiceToken func(ctx context.Context) (string, error)
+	KeyUserToken func(ctx context.Context) (string, error)
 }
 
 func NewServer(opts NewServerOptions) *Server {
@@ -28,6 +44,16 @@ func NewServer(opts NewServerOptions) *Server {
 	}
 	mux := chi.NewMux()
 
+	if opts.Alerts != nil {
+		html.StockAlerts = opts.Alerts.Items
+	}
+	if opts.Events != nil {
+		events = opts.Events
+	}
+	repository.Events = events
+	serviceToken = opts.ServiceToken
+	keyUserToken = opts.KeyUserToken
+
 	return &Server{
 		mux: mux,
 		log: opts.Log,
@@ -0,0 +1,270 @@
+package http
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/split"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// SplitRoutes registers split bills: dividing an open tab between guests,
+// then taking a payment, with a receipt of its own, for each part. The tab
+// is closed once every part is paid.
+func SplitRoutes(r chi.Router) {
+	// GET /orders/{id}/split – Split-bill screen: a preview while splitting
+	// (?mode=items|seats|even), the parts and their payments after
+	r.Get("/orders/{id}/split", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		o, err := repository.GetOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		return splitPage(o, r, cookie.Value, ""), nil
+	}))
+
+	// POST /orders/{id}/split – Set up the parts as due payments
+	r.Post("/orders/{id}/split", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		o, err := repository.GetOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		if o.Status != model.OrderStatusOpen {
+			return splitPage(o, r, cookie.Value, "Only open tabs can be split"), nil
+		}
+
+		payments, err := repository.GetOrderPayments(o.ID, cookie.Value)
+		if err != nil {
+			return splitPage(o, r, cookie.Value, "Failed to fetch payments"), nil
+		}
+		if split.Started(payments) {
+			return splitPage(o, r, cookie.Value, "Part of the bill is paid already"), nil
+		}
+
+		o, parts, msg := splitFromForm(r, o)
+		if msg != "" {
+			return splitPage(o, r, cookie.Value, msg), nil
+		}
+
+		// Seats stay on the lines for the next time the bill is split
+		if r.FormValue("mode") == split.BySeat {
+			if err := repository.SetOrderSeats(o, cookie.Value); err != nil {
+				return splitPage(o, r, cookie.Value, "Failed to save seats"), nil
+			}
+		}
+		for _, p := range payments {
+			if err := repository.DeletePayment(p.ID, cookie.Value); err != nil {
+				return splitPage(o, r, cookie.Value, "Failed to replace the earlier split"), nil
+			}
+		}
+		for _, p := range split.Payments(o, parts) {
+			if err := repository.CreatePayment(p, cookie.Value); err != nil {
+				return splitPage(o, r, cookie.Value, "Failed to split bill"), nil
+			}
+		}
+
+		http.Redirect(w, r, "/orders/"+o.ID+"/split", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /orders/{id}/split/reset – Undo a split before any part is paid
+	r.Post("/orders/{id}/split/reset", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		o, err := repository.GetOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		payments, err := repository.GetOrderPayments(o.ID, cookie.Value)
+		if err != nil {
+			return splitPage(o, r, cookie.Value, "Failed to fetch payments"), nil
+		}
+		if split.Started(payments) {
+			return splitPage(o, r, cookie.Value, "Part of the bill is paid already"), nil
+		}
+		for _, p := range payments {
+			if err := repository.DeletePayment(p.ID, cookie.Value); err != nil {
+				return splitPage(o, r, cookie.Value, "Failed to undo the split"), nil
+			}
+		}
+
+		http.Redirect(w, r, "/orders/"+o.ID+"/split", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// GET /payments/{id} – Receipt for one part of a split bill
+	r.Get("/payments/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		p, err := repository.GetPaymentByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		o, err := repository.GetOrderByID(p.Order, cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		return html.PaymentReceiptPage(p, o), nil
+	}))
+
+	// POST /payments/{id}/pay – Take payment for one part; the last one closes the tab
+	r.Post("/payments/{id}/pay", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		p, err := repository.GetPaymentByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		o, err := repository.GetOrderByID(p.Order, cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/orders", http.StatusSeeOther)
+			return nil, nil
+		}
+		if p.Status == model.PaymentPaid {
+			http.Redirect(w, r, "/payments/"+p.ID, http.StatusSeeOther)
+			return nil, nil
+		}
+
+		tender := r.FormValue("tender")
+		if tender == "" {
+			tender = model.TenderCash
+		}
+		if err := repository.PayPayment(p, tender, cookie.Value); err != nil {
+			return splitPage(o, r, cookie.Value, "Failed to take payment"), nil
+		}
+
+		payments, err := repository.GetOrderPayments(o.ID, cookie.Value)
+		if err != nil {
+			return splitPage(o, r, cookie.Value, "Failed to fetch payments"), nil
+		}
+		if split.Settled(payments) && o.Status == model.OrderStatusOpen {
+			if err := repository.SettleSplitOrder(o, cookie.Value); err != nil {
+				return splitPage(o, r, cookie.Value, "Every part is paid, but the tab failed to close"), nil
+			}
+		}
+
+		http.Redirect(w, r, "/payments/"+p.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// splitPage renders the split-bill screen: the parts and their payments
+// once the bill is split, or else a preview of the split in the request.
+func splitPage(o model.Order, r *http.Request, token, msg string) Node {
+	payments, err := repository.GetOrderPayments(o.ID, token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch payments"
+	}
+
+	preview, parts, formMsg := splitFromForm(r, o)
+	if msg == "" && len(payments) == 0 {
+		msg = formMsg
+	}
+	return html.SplitPage(preview, splitMode(r), splitParts(r), guestsFromForm(r, o), parts, payments, msg)
+}
+
+// splitFromForm reads how a bill is split from a request's form or query:
+// mode, the number of parts n, and a guest or seat number for each line.
+// It returns the order with the seats applied and the parts. If the split
+// is invalid it also returns a user-facing message.
+func splitFromForm(r *http.Request, o model.Order) (model.Order, []split.Part, string) {
+	if err := r.ParseForm(); err != nil {
+		return o, nil, "Invalid form submission"
+	}
+
+	var parts []split.Part
+	var err error
+	switch splitMode(r) {
+	case split.Evenly:
+		parts, err = split.Even(o, splitParts(r))
+	case split.BySeat:
+		o.Items = slices.Clone(o.Items)
+		for i, seat := range numbersFromForm(r.Form["seat"], len(o.Items)) {
+			if seat > 0 {
+				o.Items[i].Seat = seat
+			}
+		}
+		parts, err = split.BySeats(o)
+	default:
+		parts, err = split.ByGuest(o, guestsFromForm(r, o))
+	}
+	if err != nil {
+		return o, nil, "Cannot split: " + err.Error()
+	}
+	return o, parts, ""
+}
+
+// splitMode returns the way the bill is split, by item unless the request
+// says otherwise.
+func splitMode(r *http.Request) string {
+	if mode := r.FormValue("mode"); slices.Contains(split.Modes, mode) {
+		return mode
+	}
+	return split.ByItem
+}
+
+// splitParts returns the number of guests or even parts, two by default.
+func splitParts(r *http.Request) int {
+	n, err := strconv.Atoi(r.FormValue("n"))
+	if err != nil {
+		return 2
+	}
+	return min(max(n, 2), split.MaxParts)
+}
+
+// guestsFromForm returns the guest each line is assigned to, guest 1 for
+// lines not assigned yet.
+func guestsFromForm(r *http.Request, o model.Order) []int {
+	guests := numbersFromForm(r.Form["guest"], len(o.Items))
+	for i, g := range guests {
+		if g < 1 {
+			guests[i] = 1
+		}
+	}
+	return guests
+}
+
+// numbersFromForm parses one number per line, zero where missing.
+func numbersFromForm(values []string, lines int) []int {
+	numbers := make([]int, lines)
+	for i := range min(len(values), lines) {
+		numbers[i], _ = strconv.Atoi(values[i])
+	}
+	return numbers
+}
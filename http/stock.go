@@ -0,0 +1,148 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/lot"
+	"github.com/rustacean-dev/possystem/internal/stock"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// StockRoutes registers the per-item stock ledger pages.
+func StockRoutes(r chi.Router) {
+	// GET /items/{id}/stock – Movement history of one item
+	r.Get("/items/{id}/stock", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return stockHistoryPage(chi.URLParam(r, "id"), cookie.Value, ""), nil
+	}))
+
+	// POST /items/{id}/stock – Record a restock, waste, refund return or adjustment
+	r.Post("/items/{id}/stock", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		id := chi.URLParam(r, "id")
+		if err := r.ParseForm(); err != nil {
+			return stockHistoryPage(id, cookie.Value, "Invalid form submission"), nil
+		}
+
+		qty, err := strconv.Atoi(strings.TrimSpace(r.FormValue("quantity")))
+		if err != nil {
+			return stockHistoryPage(id, cookie.Value, "Quantity must be a whole number"), nil
+		}
+		change, err := stock.Signed(r.FormValue("kind"), float64(qty))
+		if err != nil {
+			return stockHistoryPage(id, cookie.Value, "Cannot record movement: "+err.Error()), nil
+		}
+
+		item, err := repository.GetItemByID(id, cookie.Value)
+		if err != nil {
+			return stockHistoryPage(id, cookie.Value, "Item not found"), nil
+		}
+		// Stock added to perishable items starts a lot
+		expires, err := lot.ParseExpiry(r.FormValue("expires"))
+		if err != nil {
+			return stockHistoryPage(id, cookie.Value, "Invalid expiry date"), nil
+		}
+
+		m := model.StockMovement{
+			Item:     id,
+			Kind:     r.FormValue("kind"),
+			Quantity: change,
+			Reason:   strings.TrimSpace(r.FormValue("reason")),
+			Order:    strings.TrimSpace(r.FormValue("order")),
+		}
+		if m.Order != "" {
+			if _, err := repository.GetOrderByID(m.Order, cookie.Value); err != nil {
+				return stockHistoryPage(id, cookie.Value, "Order not found"), nil
+			}
+		}
+
+		if err := repository.ApplyStockMovement(m, cookie.Value); err != nil {
+			return stockHistoryPage(id, cookie.Value, "Failed to record movement"), nil
+		}
+
+		if err := addLot(item, m, expires, cookie.Value); err != nil {
+			return stockHistoryPage(id, cookie.Value, "Stock was added but its lot could not be recorded"), nil
+		}
+		takeLots(item, m, cookie.Value)
+
+		http.Redirect(w, r, "/items/"+id+"/stock", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /items/{id}/stock/reconcile – Bring the ledger in line with the stored quantity
+	r.Post("/items/{id}/stock/reconcile", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		id := chi.URLParam(r, "id")
+		item, err := repository.GetItemByID(id, cookie.Value)
+		if err != nil {
+			return stockHistoryPage(id, cookie.Value, "Item not found"), nil
+		}
+		movements, err := repository.GetStockMovements(id, cookie.Value)
+		if err != nil {
+			return stockHistoryPage(id, cookie.Value, "Failed to fetch stock movements"), nil
+		}
+
+		// The stored quantity stays; the ledger gets the missing entry
+		if drift := stock.Drift(float64(item.Quantity), movements); drift != 0 {
+			err := repository.CreateStockMovement(model.StockMovement{
+				Item:     id,
+				Kind:     model.MovementAdjustment,
+				Quantity: drift,
+				Reason:   "Reconciled with stored quantity",
+			}, cookie.Value)
+			if err != nil {
+				return stockHistoryPage(id, cookie.Value, "Failed to record adjustment"), nil
+			}
+		}
+
+		http.Redirect(w, r, "/items/"+id+"/stock", http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// stockHistoryPage fetches an item and its ledger for [html.StockHistoryPage].
+func stockHistoryPage(id, token, msg string) Node {
+	item, err := repository.GetItemByID(id, token)
+	if err != nil {
+		return html.StockHistoryPage(model.Item{ID: id}, nil, nil, "Item not found")
+	}
+
+	movements, err := repository.GetStockMovements(id, token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch stock movements"
+	}
+
+	var lots []model.Lot
+	if item.Perishable {
+		lots, err = repository.GetLots(id, token)
+		if err != nil && msg == "" {
+			msg = "Failed to fetch lots"
+		}
+		lot.Sort(lots)
+	}
+
+	return html.StockHistoryPage(item, movements, lots, msg)
+}
@@ -0,0 +1,213 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/stocktake"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// StocktakeRoutes registers the stocktake sessions: counting stock in one or
+// more passes, then closing the session to apply the variance as adjustments.
+func StocktakeRoutes(r chi.Router) {
+	// GET /stocktakes – List of stocktake sessions
+	r.Get("/stocktakes", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		sessions, err := repository.GetStocktakes(cookie.Value)
+		if err != nil {
+			return html.StocktakeListPage(nil, "Failed to fetch stocktakes"), nil
+		}
+		return html.StocktakeListPage(sessions, ""), nil
+	}))
+
+	// POST /stocktakes – Start a stocktake; only one may be open at a time
+	r.Post("/stocktakes", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		sessions, err := repository.GetStocktakes(cookie.Value)
+		if err != nil {
+			return html.StocktakeListPage(nil, "Failed to fetch stocktakes"), nil
+		}
+		for _, st := range sessions {
+			if st.Status == model.StocktakeOpen {
+				return html.StocktakeListPage(sessions, "Close the open stocktake before starting another"), nil
+			}
+		}
+
+		st, err := repository.CreateStocktake(strings.TrimSpace(r.FormValue("note")), cookie.Value)
+		if err != nil {
+			return html.StocktakeListPage(sessions, "Failed to start stocktake"), nil
+		}
+
+		http.Redirect(w, r, "/stocktakes/"+st.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// GET /stocktakes/{id} – Count form, or the variance report once closed
+	r.Get("/stocktakes/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return stocktakePage(w, r, chi.URLParam(r, "id"), cookie.Value, ""), nil
+	}))
+
+	// POST /stocktakes/{id}/counts – Record one counting pass
+	r.Post("/stocktakes/{id}/counts", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		id := chi.URLParam(r, "id")
+		if err := r.ParseForm(); err != nil {
+			return stocktakePage(w, r, id, cookie.Value, "Invalid form submission"), nil
+		}
+
+		st, err := repository.GetStocktakeByID(id, cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/stocktakes", http.StatusSeeOther)
+			return nil, nil
+		}
+		if st.Status != model.StocktakeOpen {
+			return stocktakePage(w, r, id, cookie.Value, "This stocktake is closed"), nil
+		}
+
+		fields := map[string]string{}
+		for key := range r.PostForm {
+			if itemID, ok := strings.CutPrefix(key, "count-"); ok {
+				fields[itemID] = r.PostForm.Get(key)
+			}
+		}
+		counts, err := stocktake.ParseCounts(fields)
+		if err != nil {
+			return stocktakePage(w, r, id, cookie.Value, "Invalid count: "+err.Error()), nil
+		}
+
+		for itemID, qty := range counts {
+			err := repository.AddStocktakeCount(model.StocktakeCount{Stocktake: id, Item: itemID, Quantity: qty}, cookie.Value)
+			if err != nil {
+				return stocktakePage(w, r, id, cookie.Value, "Failed to save counts"), nil
+			}
+		}
+
+		http.Redirect(w, r, "/stocktakes/"+id, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /stocktakes/{id}/close – Apply the variance and close the session
+	r.Post("/stocktakes/{id}/close", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		id := chi.URLParam(r, "id")
+		st, err := repository.GetStocktakeByID(id, cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/stocktakes", http.StatusSeeOther)
+			return nil, nil
+		}
+		if st.Status != model.StocktakeOpen {
+			http.Redirect(w, r, "/stocktakes/"+id, http.StatusSeeOther)
+			return nil, nil
+		}
+
+		items, err := repository.GetAllItems(cookie.Value)
+		if err != nil {
+			return stocktakePage(w, r, id, cookie.Value, "Failed to fetch items"), nil
+		}
+		counts, err := repository.GetStocktakeCounts(id, cookie.Value)
+		if err != nil {
+			return stocktakePage(w, r, id, cookie.Value, "Failed to fetch counts"), nil
+		}
+
+		// Quantities are compared with the stock at closing time, so sales
+		// made during the count should be finished before closing
+		lines := stocktake.Variance(items, stocktake.Totals(counts))
+		byID := map[string]model.Item{}
+		for _, it := range items {
+			byID[it.ID] = it
+		}
+		for _, l := range lines {
+			if l.Variance == 0 {
+				continue
+			}
+			m := model.StockMovement{
+				Item:     l.Item,
+				Kind:     model.MovementStocktake,
+				Quantity: float64(l.Variance),
+				Reason:   "Stocktake " + id,
+			}
+			if err := repository.ApplyStockMovement(m, cookie.Value); err != nil {
+				return stocktakePage(w, r, id, cookie.Value, "Failed to adjust stock of "+l.Name), nil
+			}
+			// Found units of perishable items start a lot without an
+			// expiry date; missing ones come out of their lots
+			if err := addLot(byID[l.Item], m, "", cookie.Value); err != nil {
+				log.Println("Failed to record lot:", err)
+			}
+			takeLots(byID[l.Item], m, cookie.Value)
+		}
+
+		if err := repository.CloseStocktake(id, lines, cookie.Value); err != nil {
+			return stocktakePage(w, r, id, cookie.Value, "Stock was adjusted but the stocktake could not be closed"), nil
+		}
+
+		http.Redirect(w, r, "/stocktakes/"+id, http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// stocktakePage fetches a session, the stocked items and the counts so far
+// for [html.StocktakePage]. Unknown sessions redirect to the list.
+func stocktakePage(w http.ResponseWriter, r *http.Request, id, token, msg string) Node {
+	st, err := repository.GetStocktakeByID(id, token)
+	if err != nil {
+		http.Redirect(w, r, "/stocktakes", http.StatusSeeOther)
+		return nil
+	}
+	if st.Status != model.StocktakeOpen {
+		return html.StocktakePage(st, nil, nil, msg)
+	}
+
+	all, err := repository.GetAllItems(token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch items"
+	}
+	// Recipe items have no stock of their own, so they are not counted
+	var items []model.Item
+	for _, it := range all {
+		if len(it.Recipe) == 0 {
+			items = append(items, it)
+		}
+	}
+
+	counts, err := repository.GetStocktakeCounts(id, token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch counts"
+	}
+
+	return html.StocktakePage(st, items, stocktake.Totals(counts), msg)
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// SupplierRoutes registers the supplier management page.
+func SupplierRoutes(r chi.Router) {
+	// GET /suppliers – List suppliers with inline edit forms
+	r.Get("/suppliers", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return suppliersPage(cookie.Value, ""), nil
+	}))
+
+	// POST /suppliers – Add a supplier
+	r.Post("/suppliers", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		s, msg := supplierFromForm(r)
+		if msg == "" {
+			if err := repository.CreateSupplier(s, cookie.Value); err != nil {
+				msg = "Failed to add supplier"
+			}
+		}
+		if msg != "" {
+			return suppliersPage(cookie.Value, msg), nil
+		}
+
+		http.Redirect(w, r, "/suppliers", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /suppliers/{id} – Save a supplier
+	r.Post("/suppliers/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		s, msg := supplierFromForm(r)
+		s.ID = chi.URLParam(r, "id")
+		if msg == "" {
+			if err := repository.UpdateSupplier(s, cookie.Value); err != nil {
+				msg = "Failed to save supplier"
+			}
+		}
+		if msg != "" {
+			return suppliersPage(cookie.Value, msg), nil
+		}
+
+		http.Redirect(w, r, "/suppliers", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /suppliers/{id}/delete – Delete a supplier
+	r.Post("/suppliers/{id}/delete", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		if err := repository.DeleteSupplier(chi.URLParam(r, "id"), cookie.Value); err != nil {
+			return suppliersPage(cookie.Value, "Failed to delete supplier"), nil
+		}
+
+		http.Redirect(w, r, "/suppliers", http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// suppliersPage fetches the suppliers for [html.SuppliersPage].
+func suppliersPage(token, msg string) Node {
+	suppliers, err := repository.GetSuppliers(token)
+	if err != nil {
+		return html.SuppliersPage(nil, "Failed to fetch suppliers")
+	}
+	return html.SuppliersPage(suppliers, msg)
+}
+
+// supplierFromForm reads a supplier from the add/edit form. It returns a
+// user-facing message if the form is invalid.
+func supplierFromForm(r *http.Request) (model.Supplier, string) {
+	if err := r.ParseForm(); err != nil {
+		return model.Supplier{}, "Invalid form submission"
+	}
+
+	s := model.Supplier{
+		Name:  strings.TrimSpace(r.FormValue("name")),
+		Phone: strings.TrimSpace(r.FormValue("phone")),
+		Email: strings.TrimSpace(r.FormValue("email")),
+	}
+	if s.Name == "" {
+		return s, "Please enter a name"
+	}
+	return s, ""
+}
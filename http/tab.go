@@ -0,0 +1,123 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/tab"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// TabRoutes registers open tabs: listing them, and closing them with
+// payment. Rounds are added through the POS, which posts to /orders.
+func TabRoutes(r chi.Router) {
+	// GET /tabs – Open tabs, listed ?by=table (default) or ?by=customer
+	r.Get("/tabs", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return tabsPage(r.URL.Query().Get("by"), cookie.Value, ""), nil
+	}))
+
+	// GET /tabs/{id} – An open tab round by round
+	r.Get("/tabs/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		o, err := repository.GetOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/tabs", http.StatusSeeOther)
+			return nil, nil
+		}
+		// Closed tabs are plain orders
+		if o.Status != model.OrderStatusOpen {
+			http.Redirect(w, r, "/orders/"+o.ID, http.StatusSeeOther)
+			return nil, nil
+		}
+		return tabPage(o, cookie.Value, ""), nil
+	}))
+
+	// POST /tabs/{id}/close – Take payment for every round and close the tab
+	r.Post("/tabs/{id}/close", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		o, err := repository.GetOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/tabs", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		// A split bill is paid part by part
+		if payments, err := repository.GetOrderPayments(o.ID, cookie.Value); err != nil || len(payments) > 0 {
+			http.Redirect(w, r, "/orders/"+o.ID+"/split", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		tender := r.FormValue("tender")
+		if tender == "" {
+			tender = model.TenderCash
+		}
+		closed, err := tab.Close(o, tender)
+		if err != nil {
+			return tabPage(o, cookie.Value, "Cannot close tab: "+err.Error()), nil
+		}
+		if err := repository.CloseTab(closed, cookie.Value); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				fresh, err := repository.GetOrderByID(o.ID, cookie.Value)
+				if err == nil {
+					o = fresh
+				}
+				return tabPage(o, cookie.Value, "The tab changed while it was being closed. Check its rounds and close it again."), nil
+			}
+			return tabPage(o, cookie.Value, "Failed to close tab"), nil
+		}
+
+		// Show the receipt
+		http.Redirect(w, r, "/orders/"+o.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// tabsPage renders the open tabs, sorted as by says.
+func tabsPage(by, token, msg string) Node {
+	if by != tab.ByCustomer {
+		by = tab.ByTable
+	}
+
+	tabs, err := repository.GetOpenTabs(token)
+	if err != nil {
+		return html.TabsPage(nil, nil, by, "Failed to fetch tabs")
+	}
+	tables, err := repository.GetTables(token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch tables"
+	}
+
+	tab.Sort(tabs, tablesByID(tables), by)
+	return html.TabsPage(tabs, tables, by, msg)
+}
+
+// tabPage renders an open tab.
+func tabPage(o model.Order, token, msg string) Node {
+	tables, err := repository.GetTables(token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch tables"
+	}
+	return html.TabPage(o, tables, msg)
+}
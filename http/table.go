@@ -0,0 +1,435 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	. "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+
+	"github.com/rustacean-dev/possystem/html"
+	"github.com/rustacean-dev/possystem/internal/floor"
+	"github.com/rustacean-dev/possystem/model"
+	"github.com/rustacean-dev/possystem/repository"
+)
+
+// TableRoutes registers the floor plan and table management for dine-in
+// service: seating guests, asking for the bill, clearing tables, moving
+// orders between tables and merging tables, and the table layout.
+func TableRoutes(r chi.Router) {
+	// GET /tables – Floor plan with the status of every table
+	r.Get("/tables", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return floorPlanPage(cookie.Value, ""), nil
+	}))
+
+	// GET /tables/layout – Add, place and remove tables
+	r.Get("/tables/layout", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		return tableLayoutPage(cookie.Value, ""), nil
+	}))
+
+	// POST /tables/layout – Add a table
+	r.Post("/tables/layout", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		t, msg := tableFromForm(r)
+		if msg != "" {
+			return tableLayoutPage(cookie.Value, msg), nil
+		}
+		if err := repository.CreateTable(t, cookie.Value); err != nil {
+			return tableLayoutPage(cookie.Value, "Failed to add table"), nil
+		}
+
+		http.Redirect(w, r, "/tables/layout", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// GET /tables/{id} – Table status and the orders of the current sitting
+	r.Get("/tables/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		t, err := repository.GetTableByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/tables", http.StatusSeeOther)
+			return nil, nil
+		}
+		return tablePage(t, cookie.Value, ""), nil
+	}))
+
+	// POST /tables/{id} – Save a table's number, seats, area and position
+	r.Post("/tables/{id}", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		t, msg := tableFromForm(r)
+		if msg != "" {
+			return tableLayoutPage(cookie.Value, msg), nil
+		}
+		t.ID = chi.URLParam(r, "id")
+		if err := repository.UpdateTable(t, cookie.Value); err != nil {
+			return tableLayoutPage(cookie.Value, "Failed to save table"), nil
+		}
+
+		http.Redirect(w, r, "/tables/layout", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /tables/{id}/delete – Remove a free table
+	r.Post("/tables/{id}/delete", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		tables, err := repository.GetTables(cookie.Value)
+		if err != nil {
+			return tableLayoutPage(cookie.Value, "Failed to fetch tables"), nil
+		}
+		id := chi.URLParam(r, "id")
+		byID := tablesByID(tables)
+		for _, t := range tables {
+			if (t.ID == id || t.MergedInto == id) && floor.Status(t, byID) != model.TableFree {
+				return tableLayoutPage(cookie.Value, "Only free tables can be removed"), nil
+			}
+		}
+		if err := repository.DeleteTable(id, cookie.Value); err != nil {
+			return tableLayoutPage(cookie.Value, "Failed to remove table"), nil
+		}
+
+		http.Redirect(w, r, "/tables/layout", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /tables/{id}/seat – Seat guests at a free table
+	r.Post("/tables/{id}/seat", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		t, err := repository.GetTableByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/tables", http.StatusSeeOther)
+			return nil, nil
+		}
+		if t.MergedInto != "" {
+			return tablePage(t, cookie.Value, "This table is merged into another table"), nil
+		}
+		if err := repository.SetTableState(floor.Seat(t, time.Now()), cookie.Value); err != nil {
+			return tablePage(t, cookie.Value, "Failed to update table"), nil
+		}
+
+		http.Redirect(w, r, "/tables/"+t.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /tables/{id}/bill – Mark an occupied table as waiting for the bill
+	r.Post("/tables/{id}/bill", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		t, err := repository.GetTableByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/tables", http.StatusSeeOther)
+			return nil, nil
+		}
+		billed, err := floor.RequestBill(t)
+		if err != nil {
+			return tablePage(t, cookie.Value, "Only occupied tables can ask for the bill"), nil
+		}
+		if err := repository.SetTableState(billed, cookie.Value); err != nil {
+			return tablePage(t, cookie.Value, "Failed to update table"), nil
+		}
+
+		http.Redirect(w, r, "/tables/"+t.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /tables/{id}/clear – Free a table, and the tables merged into it, once the guests have left
+	r.Post("/tables/{id}/clear", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		tables, err := repository.GetTables(cookie.Value)
+		if err != nil {
+			return floorPlanPage(cookie.Value, "Failed to fetch tables"), nil
+		}
+		if err := saveTables(floor.Clear(tables, chi.URLParam(r, "id")), cookie.Value); err != nil {
+			return floorPlanPage(cookie.Value, "Failed to clear table"), nil
+		}
+
+		http.Redirect(w, r, "/tables", http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /tables/{id}/merge – Merge the table in form field "merge" into this one
+	r.Post("/tables/{id}/merge", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		t, err := repository.GetTableByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/tables", http.StatusSeeOther)
+			return nil, nil
+		}
+		tables, err := repository.GetTables(cookie.Value)
+		if err != nil {
+			return tablePage(t, cookie.Value, "Failed to fetch tables"), nil
+		}
+
+		other := r.FormValue("merge")
+		changed, err := floor.Merge(tables, t.ID, other, time.Now())
+		if err != nil {
+			return tablePage(t, cookie.Value, "Cannot merge: "+err.Error()), nil
+		}
+
+		// The party's orders so far move along to this table
+		joined := tablesByID(tables)[other]
+		orders, err := sittingOrders(joined, cookie.Value)
+		if err != nil {
+			return tablePage(t, cookie.Value, "Failed to fetch orders"), nil
+		}
+		for _, o := range orders {
+			if err := repository.SetOrderTable(o.ID, t.ID, cookie.Value); err != nil {
+				return tablePage(t, cookie.Value, "Failed to move orders"), nil
+			}
+		}
+		if err := saveTables(changed, cookie.Value); err != nil {
+			return tablePage(t, cookie.Value, "Failed to merge tables"), nil
+		}
+
+		http.Redirect(w, r, "/tables/"+t.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /tables/{id}/split – Take a merged table back out of its party
+	r.Post("/tables/{id}/split", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		t, err := repository.GetTableByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/tables", http.StatusSeeOther)
+			return nil, nil
+		}
+		split, err := floor.Split(t)
+		if err != nil {
+			return tablePage(t, cookie.Value, "Cannot split: "+err.Error()), nil
+		}
+		t = split
+		if err := repository.SetTableState(t, cookie.Value); err != nil {
+			return tablePage(t, cookie.Value, "Failed to update table"), nil
+		}
+
+		http.Redirect(w, r, "/tables/"+t.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+
+	// POST /orders/{id}/table – Move an order to the table in form field "table".
+	// The old table is freed once none of its orders are left.
+	r.Post("/orders/{id}/table", ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (Node, error) {
+		cookie, err := r.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return nil, nil
+		}
+
+		order, err := repository.GetOrderByID(chi.URLParam(r, "id"), cookie.Value)
+		if err != nil {
+			http.Redirect(w, r, "/tables", http.StatusSeeOther)
+			return nil, nil
+		}
+		target := orderTable(r.FormValue("table"), cookie.Value)
+		if target.ID == "" {
+			return floorPlanPage(cookie.Value, "Table not found"), nil
+		}
+		if target.ID == order.Table {
+			http.Redirect(w, r, "/tables/"+target.ID, http.StatusSeeOther)
+			return nil, nil
+		}
+
+		if err := repository.SetOrderTable(order.ID, target.ID, cookie.Value); err != nil {
+			return tablePage(target, cookie.Value, "Failed to move order"), nil
+		}
+		if err := repository.SetTableState(floor.Receive(target, order, time.Now()), cookie.Value); err != nil {
+			return tablePage(target, cookie.Value, "Failed to update table"), nil
+		}
+
+		if order.Table != "" {
+			if err := freeIfEmpty(order.Table, cookie.Value); err != nil {
+				log.Println(" Failed to free table:", err)
+			}
+		}
+
+		http.Redirect(w, r, "/tables/"+target.ID, http.StatusSeeOther)
+		return nil, nil
+	}))
+}
+
+// floorPlanPage renders the floor plan, or it with an error if the tables
+// cannot be fetched.
+func floorPlanPage(token, msg string) Node {
+	tables, err := repository.GetTables(token)
+	if err != nil {
+		return html.FloorPlanPage(nil, time.Now(), "Failed to fetch tables")
+	}
+	return html.FloorPlanPage(tables, time.Now(), msg)
+}
+
+// tablePage renders a table with the orders of its current sitting.
+func tablePage(t model.Table, token, msg string) Node {
+	tables, err := repository.GetTables(token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch tables"
+	}
+	orders, err := sittingOrders(t, token)
+	if err != nil && msg == "" {
+		msg = "Failed to fetch orders"
+	}
+	return html.TablePage(t, tables, orders, time.Now(), msg)
+}
+
+// tableLayoutPage renders the table layout editor.
+func tableLayoutPage(token, msg string) Node {
+	tables, err := repository.GetTables(token)
+	if err != nil {
+		return html.TableLayoutPage(nil, "Failed to fetch tables")
+	}
+	return html.TableLayoutPage(tables, msg)
+}
+
+// tableFromForm reads the add and edit forms of the table layout. If the
+// form is invalid it also returns a user-facing message.
+func tableFromForm(r *http.Request) (model.Table, string) {
+	if err := r.ParseForm(); err != nil {
+		return model.Table{}, "Invalid form submission"
+	}
+
+	t := model.Table{Area: strings.TrimSpace(r.FormValue("area"))}
+	fields := []struct {
+		name  string
+		label string
+		v     *int
+	}{
+		{"number", "Table number", &t.Number},
+		{"seats", "Seats", &t.Seats},
+		{"x", "Column", &t.X},
+		{"y", "Row", &t.Y},
+	}
+	for _, f := range fields {
+		n, err := strconv.Atoi(strings.TrimSpace(r.FormValue(f.name)))
+		if err != nil || n < 1 {
+			return t, f.label + " must be a whole number of at least 1"
+		}
+		*f.v = n
+	}
+	return t, ""
+}
+
+// orderTable looks up the table an order is placed at or moved to. Orders
+// for a merged table go to the table it is merged into. It returns the zero
+// table if id is empty or unknown.
+func orderTable(id, token string) model.Table {
+	if id == "" {
+		return model.Table{}
+	}
+	t, err := repository.GetTableByID(id, token)
+	if err != nil {
+		return model.Table{}
+	}
+	if t.MergedInto != "" {
+		if main, err := repository.GetTableByID(t.MergedInto, token); err == nil {
+			return main
+		}
+	}
+	return t
+}
+
+// sittingOrders fetches the orders of a table's current sitting; free
+// tables have none.
+func sittingOrders(t model.Table, token string) ([]model.Order, error) {
+	if t.Status == "" || t.Status == model.TableFree || t.SeatedAt == "" {
+		return nil, nil
+	}
+	return repository.GetTableOrders(t.ID, t.SeatedAt, token)
+}
+
+// freeIfEmpty clears a table once its current sitting has no orders left,
+// after they were moved elsewhere.
+func freeIfEmpty(id, token string) error {
+	t, err := repository.GetTableByID(id, token)
+	if err != nil {
+		return err
+	}
+	if t.Status == "" || t.Status == model.TableFree {
+		return nil
+	}
+	orders, err := sittingOrders(t, token)
+	if err != nil || len(orders) > 0 {
+		return err
+	}
+	tables, err := repository.GetTables(token)
+	if err != nil {
+		return err
+	}
+	return saveTables(floor.Clear(tables, id), token)
+}
+
+// saveTables saves the state of each table.
+func saveTables(tables []model.Table, token string) error {
+	for _, t := range tables {
+		if err := repository.SetTableState(t, token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tablesByID indexes tables by ID.
+func tablesByID(tables []model.Table) map[string]model.Table {
+	byID := make(map[string]model.Table, len(tables))
+	for _, t := range tables {
+		byID[t.ID] = t
+	}
+	return byID
+}
@@ -0,0 +1,177 @@
+// Package analytics aggregates orders and items into the numbers shown on
+// the dashboard for logged-in users.
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// LowStockThreshold is the stock level at or below which an item without a
+// reorder point is flagged.
+const LowStockThreshold = 5
+
+// LowStockAt returns the stock level at or below which an item is flagged:
+// its reorder point, or [LowStockThreshold] if it has none.
+func LowStockAt(it model.Item) int {
+	if it.ReorderPoint > 0 {
+		return it.ReorderPoint
+	}
+	return LowStockThreshold
+}
+
+// ItemSales is how much of one item was sold.
+type ItemSales struct {
+	Name     string
+	Quantity int
+	Revenue  float64
+}
+
+// Dashboard holds everything the home page dashboard shows.
+type Dashboard struct {
+	Today         float64
+	LastWeek      float64
+	OrderCount    int
+	AverageTicket float64
+	TopItems      []ItemSales
+	Hourly        [24]float64
+	LowStock      []model.Item
+}
+
+// Build computes the dashboard for the day containing now, in now's location.
+// LastWeek is the revenue of the same weekday one week earlier.
+func Build(orders []model.Order, items []model.Item, now time.Time) Dashboard {
+	today := OnDay(orders, now)
+	lastWeek := OnDay(orders, now.AddDate(0, 0, -7))
+
+	d := Dashboard{
+		Today:    Revenue(today),
+		LastWeek: Revenue(lastWeek),
+		TopItems: TopItems(today, 10),
+		Hourly:   Hourly(today, now.Location()),
+		LowStock: LowStock(items),
+	}
+	for _, o := range today {
+		if counts(o) {
+			d.OrderCount++
+		}
+	}
+	if d.OrderCount > 0 {
+		d.AverageTicket = d.Today / float64(d.OrderCount)
+	}
+	return d
+}
+
+// OnDay returns the orders created on the calendar day of day, in day's location.
+func OnDay(orders []model.Order, day time.Time) []model.Order {
+	y, m, dd := day.Date()
+	start := time.Date(y, m, dd, 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+
+	var res []model.Order
+	for _, o := range orders {
+		created, err := model.ParseTime(o.CreatedAt)
+		if err != nil {
+			continue
+		}
+		if !created.Before(start) && created.Before(end) {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+// Revenue sums the totals of paid or completed orders.
+func Revenue(orders []model.Order) float64 {
+	var total float64
+	for _, o := range orders {
+		if counts(o) {
+			total += o.TotalCost
+		}
+	}
+	return total
+}
+
+// TopItems returns up to n items by quantity sold, ties broken by revenue then name.
+func TopItems(orders []model.Order, n int) []ItemSales {
+	byName := map[string]*ItemSales{}
+	for _, o := range orders {
+		if !counts(o) {
+			continue
+		}
+		for _, line := range o.Items {
+			s, ok := byName[line.Name]
+			if !ok {
+				s = &ItemSales{Name: line.Name}
+				byName[line.Name] = s
+			}
+			s.Quantity += line.Quantity
+			s.Revenue += compute.LineTotal(line)
+		}
+	}
+
+	res := make([]ItemSales, 0, len(byName))
+	for _, s := range byName {
+		res = append(res, *s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Quantity != res[j].Quantity {
+			return res[i].Quantity > res[j].Quantity
+		}
+		if res[i].Revenue != res[j].Revenue {
+			return res[i].Revenue > res[j].Revenue
+		}
+		return res[i].Name < res[j].Name
+	})
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
+
+// Hourly buckets order revenue by hour of day in loc.
+func Hourly(orders []model.Order, loc *time.Location) [24]float64 {
+	var h [24]float64
+	for _, o := range orders {
+		if !counts(o) {
+			continue
+		}
+		created, err := model.ParseTime(o.CreatedAt)
+		if err != nil {
+			continue
+		}
+		h[created.In(loc).Hour()] += o.TotalCost
+	}
+	return h
+}
+
+// LowStock returns the items with a quantity at or below their [LowStockAt],
+// lowest first.
+func LowStock(items []model.Item) []model.Item {
+	var res []model.Item
+	for _, it := range items {
+		if it.Quantity <= LowStockAt(it) {
+			res = append(res, it)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Quantity < res[j].Quantity
+	})
+	return res
+}
+
+// counts reports whether an order contributes to revenue: it must be
+// completed, or paid and still being worked on. Open tabs are not paid
+// until they are closed.
+func counts(o model.Order) bool {
+	switch o.Status {
+	case model.OrderStatusCompleted:
+		return true
+	case model.OrderStatusOpen, model.OrderStatusCancelled, model.OrderStatusRefunded:
+		return false
+	}
+	return o.Tender != ""
+}
@@ -0,0 +1,82 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2025, 3, 12, 15, 0, 0, 0, time.UTC) // a Wednesday
+
+	orders := []model.Order{
+		{TotalCost: 6000, CreatedAt: "2025-03-12 09:15:00.000Z", Tender: model.TenderCash, Items: []model.Item{{Name: "chai", Price: 1000, Quantity: 6}}},
+		{TotalCost: 4000, CreatedAt: "2025-03-12 09:45:00.000Z", Status: model.OrderStatusCompleted, Items: []model.Item{{Name: "chapati", Price: 2000, Quantity: 2}}},
+		{TotalCost: 3000, CreatedAt: "2025-03-12 13:05:00.000Z", Tender: model.TenderCard, Items: []model.Item{{Name: "chai", Price: 1000, Quantity: 3}}},
+		{TotalCost: 9000, CreatedAt: "2025-03-12 14:00:00.000Z", Status: model.OrderStatusCancelled, Tender: model.TenderCash, Items: []model.Item{{Name: "pilau", Price: 9000, Quantity: 1}}},
+		{TotalCost: 8000, CreatedAt: "2025-03-12 14:30:00.000Z", Status: model.OrderStatusOpen, Items: []model.Item{{Name: "pilau", Price: 8000, Quantity: 1}}},
+		{TotalCost: 2000, CreatedAt: "2025-03-12 14:45:00.000Z", Items: []model.Item{{Name: "pilau", Price: 2000, Quantity: 1}}},
+		{TotalCost: 5000, CreatedAt: "2025-03-05 10:00:00.000Z", Status: model.OrderStatusCompleted},
+		{TotalCost: 7000, CreatedAt: "2025-03-11 10:00:00.000Z", Status: model.OrderStatusCompleted},
+	}
+	items := []model.Item{
+		{Name: "chai", Quantity: 40},
+		{Name: "chapati", Quantity: 5},
+		{Name: "pilau", Quantity: 0},
+		{Name: "rice", Quantity: 12, ReorderPoint: 20},
+		{Name: "beans", Quantity: 4, ReorderPoint: 2},
+	}
+
+	d := Build(orders, items, now)
+
+	if d.Today != 13000 {
+		t.Fatalf("today: got %.0f want 13000", d.Today)
+	}
+	if d.LastWeek != 5000 {
+		t.Fatalf("last week: got %.0f want 5000", d.LastWeek)
+	}
+	if d.OrderCount != 3 {
+		t.Fatalf("order count: got %d want 3", d.OrderCount)
+	}
+	if d.AverageTicket < 4333 || d.AverageTicket > 4334 {
+		t.Fatalf("average ticket: got %.2f", d.AverageTicket)
+	}
+	if len(d.TopItems) != 2 || d.TopItems[0].Name != "chai" || d.TopItems[0].Quantity != 9 {
+		t.Fatalf("top items: got %+v", d.TopItems)
+	}
+	if d.Hourly[9] != 10000 || d.Hourly[13] != 3000 || d.Hourly[14] != 0 {
+		t.Fatalf("hourly: got %v", d.Hourly)
+	}
+	if len(d.LowStock) != 3 || d.LowStock[0].Name != "pilau" || d.LowStock[2].Name != "rice" {
+		t.Fatalf("low stock: got %+v", d.LowStock)
+	}
+}
+
+func TestTopItemsLimit(t *testing.T) {
+	var lines []model.Item
+	for i := 0; i < 15; i++ {
+		lines = append(lines, model.Item{Name: string(rune('a' + i)), Price: 100, Quantity: i + 1})
+	}
+
+	got := TopItems([]model.Order{{Status: model.OrderStatusCompleted, Items: lines}}, 10)
+	if len(got) != 10 {
+		t.Fatalf("got %d items want 10", len(got))
+	}
+	if got[0].Name != "o" {
+		t.Fatalf("first: got %q want %q", got[0].Name, "o")
+	}
+}
+
+func TestOnDayUsesLocation(t *testing.T) {
+	eat := time.FixedZone("EAT", 3*60*60)
+	orders := []model.Order{
+		{ID: "late", CreatedAt: "2025-03-11 22:30:00.000Z"}, // 01:30 on the 12th in EAT
+		{ID: "early", CreatedAt: "2025-03-11 20:30:00.000Z"},
+	}
+
+	got := OnDay(orders, time.Date(2025, 3, 12, 12, 0, 0, 0, eat))
+	if len(got) != 1 || got[0].ID != "late" {
+		t.Fatalf("got %+v", got)
+	}
+}
@@ -0,0 +1,260 @@
+// Package api holds the shapes of the versioned JSON API under /api/v1:
+// the envelope every response is wrapped in, pagination, and the request
+// bodies with the checks on them that need no database.
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/internal/catalog"
+	"github.com/rustacean-dev/possystem/internal/ordertype"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Response is the envelope of every API response. Successful responses
+// carry Data, and Meta when Data is one page of a list; failed responses
+// carry only Error.
+type Response struct {
+	Data  any    `json:"data,omitempty"`
+	Meta  *Meta  `json:"meta,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// Meta describes the page of a list response.
+type Meta struct {
+	Page       int `json:"page" example:"1"`
+	PerPage    int `json:"per_page" example:"50"`
+	TotalItems int `json:"total_items" example:"120"`
+	TotalPages int `json:"total_pages" example:"3"`
+}
+
+// Error says why a request failed. Code is stable for clients to branch
+// on; Message is for people.
+type Error struct {
+	Code    string `json:"code" example:"not_found"`
+	Message string `json:"message" example:"Order not found"`
+}
+
+// Error codes, one per HTTP status the API fails with. Upstream errors are
+// failures of PocketBase, which holds the data.
+const (
+	CodeBadRequest   = "bad_request"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeMethod       = "method_not_allowed"
+	CodeConflict     = "conflict"
+	CodeInvalid      = "invalid"
+	CodeUpstream     = "upstream"
+	CodeInternal     = "internal"
+)
+
+// Code returns the error code of an HTTP status.
+func Code(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethod
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnprocessableEntity:
+		return CodeInvalid
+	case http.StatusBadGateway:
+		return CodeUpstream
+	default:
+		return CodeInternal
+	}
+}
+
+// OK wraps data in a successful response.
+func OK(data any) Response {
+	return Response{Data: data}
+}
+
+// List wraps one page of a list in a successful response.
+func List(data any, meta Meta) Response {
+	return Response{Data: data, Meta: &meta}
+}
+
+// Fail wraps a message in a failed response with the code of status.
+func Fail(status int, message string) Response {
+	return Response{Error: &Error{Code: Code(status), Message: message}}
+}
+
+// Pages are DefaultPerPage long unless the client asks for up to
+// MaxPerPage.
+const (
+	DefaultPerPage = 50
+	MaxPerPage     = 200
+)
+
+// ParsePage reads the page and per_page query parameters. Both are
+// optional; page starts at 1.
+func ParsePage(q url.Values) (page, perPage int, err error) {
+	page, perPage = 1, DefaultPerPage
+	if s := q.Get("page"); s != "" {
+		if page, err = strconv.Atoi(s); err != nil || page < 1 {
+			return 0, 0, errors.New("page must be a whole number ≥ 1")
+		}
+	}
+	if s := q.Get("per_page"); s != "" {
+		if perPage, err = strconv.Atoi(s); err != nil || perPage < 1 || perPage > MaxPerPage {
+			return 0, 0, fmt.Errorf("per_page must be a whole number from 1 to %d", MaxPerPage)
+		}
+	}
+	return page, perPage, nil
+}
+
+// NewMeta describes page of a list of total items.
+func NewMeta(page, perPage, total int) Meta {
+	m := Meta{Page: page, PerPage: perPage, TotalItems: total}
+	if perPage > 0 {
+		m.TotalPages = (total + perPage - 1) / perPage
+	}
+	return m
+}
+
+// ItemInput is the body of creating or replacing an item.
+type ItemInput struct {
+	Name        string   `json:"name" example:"chips mayai"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Price       float64  `json:"price" example:"5000"`
+	Cost        float64  `json:"cost" example:"2200"`
+	Quantity    int      `json:"quantity" example:"40"`
+	SKU         string   `json:"sku,omitempty"`
+	Barcodes    []string `json:"barcodes,omitempty"`
+	// TypePrices overrides the price for some order types. When replacing
+	// an item, leaving it out keeps the prices it has.
+	TypePrices map[string]float64 `json:"type_prices,omitempty"`
+}
+
+// Item checks the input and returns the item it describes, with its name
+// and codes normalised as the item forms do.
+func (in ItemInput) Item() (model.Item, error) {
+	item := model.Item{
+		Name:        catalog.NormalizeName(in.Name),
+		Description: strings.TrimSpace(in.Description),
+		Category:    strings.TrimSpace(in.Category),
+		Price:       in.Price,
+		Cost:        in.Cost,
+		Quantity:    in.Quantity,
+	}
+	if item.Name == "" {
+		return item, errors.New("name is required")
+	}
+	if in.Price <= 0 {
+		return item, errors.New("price must be a positive number")
+	}
+	if in.Cost < 0 {
+		return item, errors.New("cost must be a number ≥ 0")
+	}
+	if in.Quantity < 0 {
+		return item, errors.New("quantity must be a whole number ≥ 0")
+	}
+
+	var err error
+	if item.SKU, err = catalog.NormalizeCode(in.SKU); err != nil {
+		return item, fmt.Errorf("sku: %w", err)
+	}
+	seen := map[string]bool{}
+	for _, c := range in.Barcodes {
+		code, err := catalog.NormalizeCode(c)
+		if err != nil {
+			return item, fmt.Errorf("barcode: %w", err)
+		}
+		if code != "" && !seen[code] {
+			seen[code] = true
+			item.Barcodes = append(item.Barcodes, code)
+		}
+	}
+	for t, p := range in.TypePrices {
+		if !ordertype.Valid(t) || t == "" {
+			return item, fmt.Errorf("unknown order type %q in type_prices", t)
+		}
+		if p <= 0 {
+			return item, errors.New(ordertype.Label(t) + " price must be a number > 0")
+		}
+		if item.TypePrices == nil {
+			item.TypePrices = map[string]float64{}
+		}
+		item.TypePrices[t] = p
+	}
+	return item, nil
+}
+
+// OrderInput is the body of placing an order. Lines pick options by the
+// names of their groups and options, as the POS does. The type and its
+// details follow the POS order type panel: takeaways need a customer and a
+// pickup time of day, deliveries an address, a phone number and either a
+// zone or a delivery fee.
+type OrderInput struct {
+	Lines  []model.CartLine `json:"lines"`
+	Tender string           `json:"tender" enums:"cash,card,mobile" example:"cash"`
+	// Type is dine_in, takeaway, delivery, or empty for a counter sale.
+	Type     string `json:"type,omitempty" enums:"dine_in,takeaway,delivery"`
+	Table    string `json:"table,omitempty"`
+	Customer string `json:"customer,omitempty"`
+	// PickupAt is a time of day, e.g. "18:30"; a time already past today
+	// is for tomorrow.
+	PickupAt    string   `json:"pickup_at,omitempty" example:"18:30"`
+	Address     string   `json:"address,omitempty"`
+	Phone       string   `json:"phone,omitempty"`
+	Zone        string   `json:"zone,omitempty"`
+	DeliveryFee *float64 `json:"delivery_fee,omitempty"`
+}
+
+// Check checks the parts of the input that need no lookups. It does not
+// check the type details, which are checked as the POS checks them.
+func (in OrderInput) Check() error {
+	if len(in.Lines) == 0 {
+		return errors.New("an order needs at least one line")
+	}
+	for i, l := range in.Lines {
+		if l.ItemID == "" {
+			return fmt.Errorf("line %d: item_id is required", i+1)
+		}
+		if l.Quantity < 1 {
+			return fmt.Errorf("line %d: quantity must be at least 1", i+1)
+		}
+	}
+	if !ValidTender(in.Tender) {
+		return fmt.Errorf("unknown tender %q", in.Tender)
+	}
+	if !ordertype.Valid(in.Type) {
+		return fmt.Errorf("unknown order type %q", in.Type)
+	}
+	return nil
+}
+
+// StatusInput is the body of changing an order's status.
+type StatusInput struct {
+	Status string `json:"status" example:"preparing"`
+}
+
+// PayInput is the body of paying one part of a split bill.
+type PayInput struct {
+	Tender string `json:"tender" enums:"cash,card,mobile" example:"card"`
+}
+
+// ValidTender reports whether t is a tender a payment can be taken with.
+// Empty means cash.
+func ValidTender(t string) bool {
+	switch t {
+	case "", model.TenderCash, model.TenderCard, model.TenderMobile:
+		return true
+	}
+	return false
+}
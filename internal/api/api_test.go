@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestParsePage(t *testing.T) {
+	page, perPage, err := ParsePage(url.Values{})
+	if err != nil || page != 1 || perPage != DefaultPerPage {
+		t.Fatalf("defaults: %d, %d, %v", page, perPage, err)
+	}
+	page, perPage, err = ParsePage(url.Values{"page": {"3"}, "per_page": {"20"}})
+	if err != nil || page != 3 || perPage != 20 {
+		t.Fatalf("got %d, %d, %v", page, perPage, err)
+	}
+	for _, q := range []url.Values{
+		{"page": {"0"}},
+		{"page": {"x"}},
+		{"per_page": {"0"}},
+		{"per_page": {"201"}},
+	} {
+		if _, _, err := ParsePage(q); err == nil {
+			t.Fatalf("accepted %v", q)
+		}
+	}
+}
+
+func TestNewMeta(t *testing.T) {
+	if m := NewMeta(2, 50, 120); m.TotalPages != 3 {
+		t.Fatalf("got %+v", m)
+	}
+	if m := NewMeta(1, 50, 0); m.TotalPages != 0 {
+		t.Fatalf("got %+v", m)
+	}
+}
+
+func TestFail(t *testing.T) {
+	r := Fail(http.StatusUnprocessableEntity, "nope")
+	if r.Error == nil || r.Error.Code != CodeInvalid || r.Data != nil {
+		t.Fatalf("got %+v", r)
+	}
+	if Code(http.StatusBadGateway) != CodeUpstream {
+		t.Fatal("PocketBase failures are upstream errors")
+	}
+	if Code(http.StatusTeapot) != CodeInternal {
+		t.Fatal("unknown statuses are internal errors")
+	}
+}
+
+func TestItemInput(t *testing.T) {
+	item, err := ItemInput{
+		Name:       "  Chips Mayai ",
+		Price:      5000,
+		SKU:        "cm-1",
+		Barcodes:   []string{"123", "123", "456"},
+		TypePrices: map[string]float64{model.OrderDelivery: 5500},
+	}.Item()
+	if err != nil || item.Name != "chips mayai" || item.SKU != "CM-1" || len(item.Barcodes) != 2 || item.TypePrices[model.OrderDelivery] != 5500 {
+		t.Fatalf("got %+v, %v", item, err)
+	}
+
+	for _, in := range []ItemInput{
+		{Price: 5000},
+		{Name: "soda", Price: 0},
+		{Name: "soda", Price: 1000, Cost: -1},
+		{Name: "soda", Price: 1000, Quantity: -1},
+		{Name: "soda", Price: 1000, SKU: "a b"},
+		{Name: "soda", Price: 1000, TypePrices: map[string]float64{"counter": 900}},
+		{Name: "soda", Price: 1000, TypePrices: map[string]float64{model.OrderTakeaway: 0}},
+	} {
+		if _, err := in.Item(); err == nil {
+			t.Fatalf("accepted %+v", in)
+		}
+	}
+}
+
+func TestOrderInputCheck(t *testing.T) {
+	ok := OrderInput{Lines: []model.CartLine{{ItemID: "a", Quantity: 2}}}
+	if err := ok.Check(); err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range []OrderInput{
+		{},
+		{Lines: []model.CartLine{{Quantity: 1}}},
+		{Lines: []model.CartLine{{ItemID: "a"}}},
+		{Lines: ok.Lines, Tender: "cheque"},
+		{Lines: ok.Lines, Tender: model.TenderSplit},
+		{Lines: ok.Lines, Type: "drive_through"},
+	} {
+		if err := in.Check(); err == nil {
+			t.Fatalf("accepted %+v", in)
+		}
+	}
+}
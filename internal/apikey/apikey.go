@@ -0,0 +1,158 @@
+// Package apikey issues and checks the API keys machine clients call the
+// JSON API with. A key is "pos_<id>_<secret>": the id finds the stored key
+// and the whole key is checked against a SHA-256 hash, so the secret itself
+// is never stored. Each key carries scopes naming what it may do.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Prefix starts every key, telling keys apart from PocketBase tokens.
+const Prefix = "pos_"
+
+// Scopes a key can be granted. Write scopes do not include reading.
+const (
+	ItemsRead     = "items:read"
+	ItemsWrite    = "items:write"
+	OrdersRead    = "orders:read"
+	OrdersWrite   = "orders:write"
+	PaymentsRead  = "payments:read"
+	PaymentsWrite = "payments:write"
+)
+
+// Scopes lists every scope, in the order the settings page shows them.
+var Scopes = []string{ItemsRead, ItemsWrite, OrdersRead, OrdersWrite, PaymentsRead, PaymentsWrite}
+
+var labels = map[string]string{
+	ItemsRead:     "Read items",
+	ItemsWrite:    "Create, edit and archive items",
+	OrdersRead:    "Read orders",
+	OrdersWrite:   "Place orders and change their status",
+	PaymentsRead:  "Read payments",
+	PaymentsWrite: "Take payments",
+}
+
+// Label describes a scope for people.
+func Label(scope string) string {
+	if l, ok := labels[scope]; ok {
+		return l
+	}
+	return scope
+}
+
+// ValidScope reports whether s is a scope a key can be granted.
+func ValidScope(s string) bool {
+	return slices.Contains(Scopes, s)
+}
+
+// Errors of keys that cannot be used.
+var (
+	ErrMalformed = errors.New("not an API key")
+	ErrRevoked   = errors.New("the API key has been revoked")
+	ErrExpired   = errors.New("the API key has expired")
+)
+
+// New returns a new key with its id and hash. The key is shown to the
+// admin once; only the id and hash are stored.
+func New() (key, id, hash string, err error) {
+	b := make([]byte, 4+24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	id = hex.EncodeToString(b[:4])
+	key = Prefix + id + "_" + hex.EncodeToString(b[4:])
+	return key, id, Hash(key), nil
+}
+
+// IsKey reports whether a credential looks like an API key rather than a
+// PocketBase token.
+func IsKey(s string) bool {
+	return strings.HasPrefix(s, Prefix)
+}
+
+// ID returns the id part of a key, which finds it among the stored keys.
+func ID(key string) (string, error) {
+	rest, ok := strings.CutPrefix(key, Prefix)
+	if !ok {
+		return "", ErrMalformed
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(id) != 8 || secret == "" {
+		return "", ErrMalformed
+	}
+	return id, nil
+}
+
+// Hash returns the hash a key is stored as.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Check checks a key against the stored key its id found: the hash must
+// match, and the key must be neither revoked nor expired by now.
+func Check(k model.APIKey, key string, now time.Time) error {
+	if subtle.ConstantTimeCompare([]byte(Hash(key)), []byte(k.Hash)) != 1 {
+		return ErrMalformed
+	}
+	if k.Revoked {
+		return ErrRevoked
+	}
+	if Expired(k, now) {
+		return ErrExpired
+	}
+	return nil
+}
+
+// Expired reports whether a key has expired by now. Keys without an
+// expiry never do; an expiry that cannot be read counts as expired.
+func Expired(k model.APIKey, now time.Time) bool {
+	if k.ExpiresAt == "" {
+		return false
+	}
+	t, err := model.ParseTime(k.ExpiresAt)
+	return err != nil || !now.Before(t)
+}
+
+// Allows reports whether a key has been granted scope.
+func Allows(k model.APIKey, scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// TouchEvery is how often a key's last use is recorded. Keys used more
+// often are not written to on every request.
+const TouchEvery = time.Minute
+
+// TouchDue reports whether a key's last use should be recorded now.
+func TouchDue(k model.APIKey, now time.Time) bool {
+	t, err := model.ParseTime(k.LastUsedAt)
+	return err != nil || now.Sub(t) >= TouchEvery
+}
+
+// Expiry reads the optional last day of a key, "YYYY-MM-DD" in loc. The
+// key works through the end of that day, which must not be past already.
+// It returns the zero time for keys that do not expire.
+func Expiry(day string, loc *time.Location, now time.Time) (time.Time, error) {
+	if day = strings.TrimSpace(day); day == "" {
+		return time.Time{}, nil
+	}
+	d, err := time.ParseInLocation("2006-01-02", day, loc)
+	if err != nil {
+		return time.Time{}, errors.New("expiry must be a date")
+	}
+	end := d.AddDate(0, 0, 1)
+	if !now.Before(end) {
+		return time.Time{}, errors.New("expiry is in the past")
+	}
+	return end, nil
+}
@@ -0,0 +1,109 @@
+package apikey
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestNew(t *testing.T) {
+	key, id, hash, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsKey(key) {
+		t.Fatalf("key %q lacks the prefix", key)
+	}
+	got, err := ID(key)
+	if err != nil || got != id {
+		t.Fatalf("id %q, %v; want %q", got, err, id)
+	}
+	if hash != Hash(key) || hash == key {
+		t.Fatalf("hash %q", hash)
+	}
+
+	other, _, _, _ := New()
+	if other == key {
+		t.Fatal("two keys are the same")
+	}
+}
+
+func TestID(t *testing.T) {
+	for _, key := range []string{"", "eyJhbGciOi.x.y", "pos_", "pos_abc_def", "pos_0123abcd", "pos_0123abcd_"} {
+		if _, err := ID(key); !errors.Is(err, ErrMalformed) {
+			t.Errorf("ID(%q) = %v, want ErrMalformed", key, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	key := "pos_0123abcd_secret"
+	k := model.APIKey{Hash: Hash(key)}
+
+	if err := Check(k, key, now); err != nil {
+		t.Fatalf("valid key: %v", err)
+	}
+	if err := Check(k, "pos_0123abcd_guess", now); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("wrong secret: %v", err)
+	}
+
+	revoked := k
+	revoked.Revoked = true
+	if err := Check(revoked, key, now); !errors.Is(err, ErrRevoked) {
+		t.Fatalf("revoked key: %v", err)
+	}
+
+	expiring := k
+	expiring.ExpiresAt = "2026-05-01 12:00:00.000Z"
+	if err := Check(expiring, key, now.Add(-time.Second)); err != nil {
+		t.Fatalf("key before its expiry: %v", err)
+	}
+	if err := Check(expiring, key, now); !errors.Is(err, ErrExpired) {
+		t.Fatalf("key at its expiry: %v", err)
+	}
+}
+
+func TestAllows(t *testing.T) {
+	k := model.APIKey{Scopes: []string{ItemsRead, OrdersWrite}}
+	if !Allows(k, ItemsRead) || !Allows(k, OrdersWrite) {
+		t.Fatal("granted scopes refused")
+	}
+	if Allows(k, ItemsWrite) || Allows(k, OrdersRead) {
+		t.Fatal("scopes allowed that were not granted")
+	}
+}
+
+func TestTouchDue(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	if !TouchDue(model.APIKey{}, now) {
+		t.Fatal("never used key not due")
+	}
+	if TouchDue(model.APIKey{LastUsedAt: model.FormatTime(now.Add(-30 * time.Second))}, now) {
+		t.Fatal("due again within a minute")
+	}
+	if !TouchDue(model.APIKey{LastUsedAt: model.FormatTime(now.Add(-TouchEvery))}, now) {
+		t.Fatal("not due after a minute")
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	loc := time.FixedZone("EAT", 3*60*60)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, loc)
+
+	if e, err := Expiry(" ", loc, now); err != nil || !e.IsZero() {
+		t.Fatalf("no expiry: %v, %v", e, err)
+	}
+	e, err := Expiry("2026-05-01", loc, now)
+	if err != nil || !e.Equal(time.Date(2026, 5, 2, 0, 0, 0, 0, loc)) {
+		t.Fatalf("today: %v, %v", e, err)
+	}
+	if _, err := Expiry("2026-04-30", loc, now); err == nil {
+		t.Fatal("past day accepted")
+	}
+	if _, err := Expiry("next week", loc, now); err == nil {
+		t.Fatal("non-date accepted")
+	}
+}
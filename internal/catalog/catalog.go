@@ -0,0 +1,89 @@
+// Package catalog holds the rules for menu items that are shared between
+// the single-item form and the bulk CSV import.
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NormalizeName lowercases and trims an item name so that lookups by name
+// are case-insensitive.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ParsePrice parses a selling price, which must be a positive number.
+func ParsePrice(s string) (float64, error) {
+	price, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || price <= 0 {
+		return 0, errors.New("price must be a positive number")
+	}
+	return price, nil
+}
+
+// ParseCost parses what an item costs to buy or make. It may be 0, and an
+// empty value means 0.
+func ParseCost(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	cost, err := strconv.ParseFloat(s, 64)
+	if err != nil || cost < 0 {
+		return 0, errors.New("cost must be a number ≥ 0")
+	}
+	return cost, nil
+}
+
+// ParseQuantity parses a stock quantity. An empty value means 0.
+func ParseQuantity(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	qty, err := strconv.Atoi(s)
+	if err != nil || qty < 0 {
+		return 0, errors.New("quantity must be a whole number ≥ 0")
+	}
+	return qty, nil
+}
+
+// ParseCodes splits a list of barcodes separated by commas, spaces or new
+// lines, dropping duplicates. Codes may only contain letters, digits, dashes,
+// dots and underscores.
+func ParseCodes(s string) ([]string, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+
+	var codes []string
+	seen := map[string]bool{}
+	for _, f := range fields {
+		code, err := NormalizeCode(f)
+		if err != nil {
+			return nil, err
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// NormalizeCode trims and uppercases a SKU or barcode and checks that it
+// only contains letters, digits, dashes, dots and underscores.
+func NormalizeCode(s string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(s))
+	for _, r := range code {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.' || r == '_') {
+			return "", fmt.Errorf("%q is not a valid code", s)
+		}
+	}
+	return code, nil
+}
@@ -0,0 +1,100 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	if got := NormalizeName("  Chai Masala "); got != "chai masala" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"1500", 1500, false},
+		{" 2.50 ", 2.5, false},
+		{"0", 0, true},
+		{"-10", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+	}
+	for _, c := range cases {
+		got, err := ParsePrice(c.in)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Fatalf("ParsePrice(%q): got %v, %v", c.in, got, err)
+		}
+	}
+}
+
+func TestParseCost(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"1200", 1200, false},
+		{"", 0, false},
+		{"0", 0, false},
+		{"-5", 0, true},
+		{"abc", 0, true},
+	}
+	for _, c := range cases {
+		got, err := ParseCost(c.in)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Fatalf("ParseCost(%q): got %v, %v", c.in, got, err)
+		}
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"12", 12, false},
+		{"-1", 0, true},
+		{"1.5", 0, true},
+	}
+	for _, c := range cases {
+		got, err := ParseQuantity(c.in)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Fatalf("ParseQuantity(%q): got %v, %v", c.in, got, err)
+		}
+	}
+}
+
+func TestParseCodes(t *testing.T) {
+	got, err := ParseCodes(" 5901234123457, 4006381333931\n5901234123457; abc-12 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"5901234123457", "4006381333931", "ABC-12"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+
+	if got, _ := ParseCodes("  "); len(got) != 0 {
+		t.Fatalf("blank: got %v", got)
+	}
+
+	if _, err := ParseCodes("123 \"456\""); err == nil {
+		t.Fatal("expected error for quote in code")
+	}
+}
+
+func TestNormalizeCode(t *testing.T) {
+	if got, err := NormalizeCode(" sku-001 "); err != nil || got != "SKU-001" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if _, err := NormalizeCode("a b"); err == nil {
+		t.Fatal("expected error for space in code")
+	}
+}
@@ -0,0 +1,239 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Row is a validated line of an item import file.
+type Row struct {
+	Line        int
+	Name        string
+	Price       float64
+	Description string
+	Quantity    int
+	Category    string
+
+	// CategoryID is set by [ResolveCategories].
+	CategoryID string
+}
+
+// RowError is a problem with one line of an import file.
+type RowError struct {
+	Line int
+	Name string
+	Err  string
+}
+
+// columns is the column order used when the file has no header row.
+var columns = []string{"name", "price", "description", "quantity", "category"}
+
+// ParseCSV reads an item import file with the columns name, price,
+// description, quantity and category. A header row naming the columns is
+// optional; with one, the columns may come in any order.
+//
+// Each row is validated with the same rules as the single-item form. Rows
+// that fail are returned as row errors rather than failing the whole file.
+func ParseCSV(r io.Reader) ([]Row, []RowError, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	// Read records one by one to keep their line numbers; the CSV reader
+	// skips blank lines, so the record index is not the line.
+	var records [][]string
+	var lines []int
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not read CSV: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		records = append(records, rec)
+		lines = append(lines, line)
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("the file is empty")
+	}
+
+	index := map[string]int{}
+	for i, c := range columns {
+		index[c] = i
+	}
+	start := 0
+	if isHeader(records[0]) {
+		index = map[string]int{}
+		for i, h := range records[0] {
+			index[strings.ToLower(strings.TrimSpace(h))] = i
+		}
+		if _, ok := index["name"]; !ok {
+			return nil, nil, errors.New("header row has no name column")
+		}
+		if _, ok := index["price"]; !ok {
+			return nil, nil, errors.New("header row has no price column")
+		}
+		start = 1
+	}
+
+	var rows []Row
+	var rowErrs []RowError
+	for i, rec := range records[start:] {
+		line := lines[start+i]
+		field := func(name string) string {
+			j, ok := index[name]
+			if !ok || j >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[j])
+		}
+
+		if isBlank(rec) {
+			continue
+		}
+
+		row := Row{
+			Line:        line,
+			Name:        NormalizeName(field("name")),
+			Description: field("description"),
+			Category:    field("category"),
+		}
+		if row.Name == "" {
+			rowErrs = append(rowErrs, RowError{Line: line, Err: "name is required"})
+			continue
+		}
+
+		var err error
+		row.Price, err = ParsePrice(field("price"))
+		if err != nil {
+			rowErrs = append(rowErrs, RowError{Line: line, Name: row.Name, Err: err.Error()})
+			continue
+		}
+
+		row.Quantity, err = ParseQuantity(field("quantity"))
+		if err != nil {
+			rowErrs = append(rowErrs, RowError{Line: line, Name: row.Name, Err: err.Error()})
+			continue
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrs, nil
+}
+
+// Change is what an import will do with one row.
+type Change struct {
+	Row Row
+
+	// Existing is the item the row merges into. It is empty for creates.
+	Existing model.Item
+}
+
+// IsCreate reports whether the row creates a new item.
+func (c Change) IsCreate() bool {
+	return c.Existing.ID == ""
+}
+
+// NewQuantity is the stock the item will have after the import.
+func (c Change) NewQuantity() int {
+	return c.Existing.Quantity + c.Row.Quantity
+}
+
+// Plan is the preview of an import: which rows create items, which add
+// stock to existing ones, and which cannot be imported.
+type Plan struct {
+	Creates []Change
+	Updates []Change
+	Errors  []RowError
+}
+
+// BuildPlan decides for each row whether it creates an item or, like the
+// single-item form, merges into an existing item of the same name by adding
+// its quantity to the stock. lookup finds an existing item by normalized name;
+// rows it fails for, and rows naming an archived item, become row errors.
+// So do rows adding stock to a perishable item, whose lots need an expiry
+// date the file does not have.
+// A name that appears more than once in the file is only imported once.
+func BuildPlan(rows []Row, rowErrs []RowError, lookup func(name string) (model.Item, bool, error)) Plan {
+	p := Plan{Errors: append([]RowError{}, rowErrs...)}
+
+	seen := map[string]int{}
+	for _, row := range rows {
+		if first, ok := seen[row.Name]; ok {
+			p.Errors = append(p.Errors, RowError{Line: row.Line, Name: row.Name, Err: fmt.Sprintf("duplicate of line %d", first)})
+			continue
+		}
+		seen[row.Name] = row.Line
+
+		existing, ok, err := lookup(row.Name)
+		switch {
+		case err != nil:
+			p.Errors = append(p.Errors, RowError{Line: row.Line, Name: row.Name, Err: fmt.Sprintf("failed to look up item: %s", err)})
+		case ok && existing.Archived:
+			p.Errors = append(p.Errors, RowError{Line: row.Line, Name: row.Name, Err: "item is archived; restore it from its edit page first"})
+		case ok && existing.Perishable && row.Quantity > 0:
+			p.Errors = append(p.Errors, RowError{Line: row.Line, Name: row.Name, Err: "item is perishable; add its stock on the stock page so its expiry date is recorded"})
+		case ok:
+			p.Updates = append(p.Updates, Change{Row: row, Existing: existing})
+		default:
+			p.Creates = append(p.Creates, Change{Row: row})
+		}
+	}
+
+	sort.SliceStable(p.Errors, func(i, j int) bool {
+		return p.Errors[i].Line < p.Errors[j].Line
+	})
+	return p
+}
+
+// ResolveCategories looks up the category named in each row, ignoring case,
+// and sets its CategoryID. Rows naming a category that does not exist are
+// returned as row errors; rows without a category are left uncategorised.
+func ResolveCategories(rows []Row, categories []model.Category) ([]Row, []RowError) {
+	byName := map[string]string{}
+	for _, c := range categories {
+		byName[strings.ToLower(strings.TrimSpace(c.Name))] = c.ID
+	}
+
+	var res []Row
+	var rowErrs []RowError
+	for _, row := range rows {
+		if row.Category != "" {
+			id, ok := byName[strings.ToLower(row.Category)]
+			if !ok {
+				rowErrs = append(rowErrs, RowError{Line: row.Line, Name: row.Name, Err: fmt.Sprintf("unknown category %q", row.Category)})
+				continue
+			}
+			row.CategoryID = id
+		}
+		res = append(res, row)
+	}
+	return res, rowErrs
+}
+
+func isHeader(rec []string) bool {
+	for _, f := range rec {
+		if strings.EqualFold(strings.TrimSpace(f), "name") {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlank(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
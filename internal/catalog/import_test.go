@@ -0,0 +1,111 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestParseCSVWithHeader(t *testing.T) {
+	in := "Price,Name,Quantity,Category\n" +
+		"1500, Chai ,10,Drinks\n" +
+		"\n" +
+		"0,Mandazi,5,Snacks\n" +
+		"3000,,1,\n" +
+		"2500,Chapati,-2,\n"
+
+	rows, rowErrs, err := ParseCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("rows: got %+v", rows)
+	}
+	if rows[0] != (Row{Line: 2, Name: "chai", Price: 1500, Quantity: 10, Category: "Drinks"}) {
+		t.Fatalf("row: got %+v", rows[0])
+	}
+	if len(rowErrs) != 3 {
+		t.Fatalf("row errors: got %+v", rowErrs)
+	}
+	if rowErrs[0].Line != 4 || rowErrs[0].Name != "mandazi" {
+		t.Fatalf("first error: got %+v", rowErrs[0])
+	}
+}
+
+func TestParseCSVWithoutHeader(t *testing.T) {
+	rows, rowErrs, err := ParseCSV(strings.NewReader("Pilau,9000,Spiced rice,3,Food\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rowErrs) != 0 || len(rows) != 1 {
+		t.Fatalf("got %+v %+v", rows, rowErrs)
+	}
+	want := Row{Line: 1, Name: "pilau", Price: 9000, Description: "Spiced rice", Quantity: 3, Category: "Food"}
+	if rows[0] != want {
+		t.Fatalf("got %+v want %+v", rows[0], want)
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	if _, _, err := ParseCSV(strings.NewReader("")); err == nil {
+		t.Fatal("expected error for empty file")
+	}
+	if _, _, err := ParseCSV(strings.NewReader("name,quantity\nchai,1\n")); err == nil {
+		t.Fatal("expected error for missing price column")
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	rows := []Row{
+		{Line: 1, Name: "chai", Price: 1500, Quantity: 10},
+		{Line: 2, Name: "pilau", Price: 9000, Quantity: 3},
+		{Line: 3, Name: "chai", Price: 1500, Quantity: 4},
+		{Line: 4, Name: "kahawa", Price: 1000, Quantity: 2},
+		{Line: 5, Name: "mandazi", Price: 500, Quantity: 20},
+		{Line: 6, Name: "maziwa", Price: 800, Quantity: 6},
+	}
+	existing := map[string]model.Item{
+		"chai":   {ID: "c1", Name: "chai", Quantity: 7},
+		"kahawa": {ID: "k1", Name: "kahawa", Archived: true},
+		"maziwa": {ID: "m1", Name: "maziwa", Perishable: true},
+	}
+	lookup := func(name string) (model.Item, bool, error) {
+		if name == "mandazi" {
+			return model.Item{}, false, errors.New("connection refused")
+		}
+		it, ok := existing[name]
+		return it, ok, nil
+	}
+
+	p := BuildPlan(rows, []RowError{{Line: 9, Err: "bad"}}, lookup)
+
+	if len(p.Updates) != 1 || p.Updates[0].IsCreate() || p.Updates[0].NewQuantity() != 17 {
+		t.Fatalf("updates: got %+v", p.Updates)
+	}
+	if len(p.Creates) != 1 || !p.Creates[0].IsCreate() || p.Creates[0].Row.Name != "pilau" {
+		t.Fatalf("creates: got %+v", p.Creates)
+	}
+	if len(p.Errors) != 5 || p.Errors[0].Line != 3 || p.Errors[1].Line != 4 || p.Errors[2].Line != 5 || p.Errors[3].Line != 6 {
+		t.Fatalf("errors: got %+v", p.Errors)
+	}
+}
+
+func TestResolveCategories(t *testing.T) {
+	rows := []Row{
+		{Line: 1, Name: "chai", Category: "drinks"},
+		{Line: 2, Name: "pilau", Category: "Mains"},
+		{Line: 3, Name: "mandazi"},
+	}
+	categories := []model.Category{{ID: "d1", Name: "Drinks"}, {ID: "s1", Name: "Snacks"}}
+
+	got, rowErrs := ResolveCategories(rows, categories)
+
+	if len(got) != 2 || got[0].CategoryID != "d1" || got[1].CategoryID != "" {
+		t.Fatalf("rows: got %+v", got)
+	}
+	if len(rowErrs) != 1 || rowErrs[0].Line != 2 {
+		t.Fatalf("row errors: got %+v", rowErrs)
+	}
+}
@@ -0,0 +1,18 @@
+package compute
+
+import "github.com/rustacean-dev/possystem/model"
+
+// UnitPrice returns the price of one unit of an order line, including the
+// price deltas of its modifiers.
+func UnitPrice(line model.Item) float64 {
+	price := line.Price
+	for _, m := range line.Modifiers {
+		price += m.PriceDelta
+	}
+	return price
+}
+
+// LineTotal returns the unit price * quantity of an order line.
+func LineTotal(line model.Item) float64 {
+	return OrderTotal(UnitPrice(line), line.Quantity)
+}
@@ -0,0 +1,29 @@
+package compute
+
+import (
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestLineTotal(t *testing.T) {
+	cases := []struct {
+		name string
+		line model.Item
+		want float64
+	}{
+		{"no modifiers", model.Item{Price: 3000, Quantity: 2}, 6000},
+		{"with modifiers", model.Item{Price: 3000, Quantity: 2, Modifiers: []model.Modifier{
+			{Group: "Size", Name: "Large", PriceDelta: 1000},
+			{Group: "Extras", Name: "Oat milk", PriceDelta: 500},
+		}}, 9000},
+		{"discounting modifier", model.Item{Price: 3000, Quantity: 1, Modifiers: []model.Modifier{
+			{Group: "Size", Name: "Small", PriceDelta: -500},
+		}}, 2500},
+	}
+	for _, c := range cases {
+		if got := LineTotal(c.line); got != c.want {
+			t.Fatalf("%s: got %.2f want %.2f", c.name, got, c.want)
+		}
+	}
+}
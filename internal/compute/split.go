@@ -0,0 +1,63 @@
+package compute
+
+import (
+	"math"
+	"sort"
+)
+
+// Allocate divides total into parts proportional to weights, in whole
+// shillings. Rounding leaves a remainder of a few shillings, which goes to
+// the parts that lost the most to rounding, earlier parts first on ties,
+// so the parts always add up to the rounded total. Without any positive
+// weight the total is split evenly.
+func Allocate(total float64, weights []float64) []float64 {
+	if len(weights) == 0 {
+		return nil
+	}
+
+	var sum float64
+	for _, w := range weights {
+		sum += max(w, 0)
+	}
+	if sum == 0 {
+		weights = make([]float64, len(weights))
+		for i := range weights {
+			weights[i] = 1
+		}
+		sum = float64(len(weights))
+	}
+
+	units := math.Round(total)
+	parts := make([]float64, len(weights))
+	fractions := make([]int, len(weights))
+	remainder := units
+	for i, w := range weights {
+		exact := units * max(w, 0) / sum
+		parts[i] = math.Floor(exact)
+		remainder -= parts[i]
+		fractions[i] = i
+	}
+
+	sort.SliceStable(fractions, func(a, b int) bool {
+		ia, ib := fractions[a], fractions[b]
+		return units*max(weights[ia], 0)/sum-parts[ia] > units*max(weights[ib], 0)/sum-parts[ib]
+	})
+	for k := 0; remainder > 0; k++ {
+		parts[fractions[k%len(fractions)]]++
+		remainder--
+	}
+	return parts
+}
+
+// SplitEven divides total into n parts that differ by at most one
+// shilling, the first parts taking the remainder.
+func SplitEven(total float64, n int) []float64 {
+	if n <= 0 {
+		return nil
+	}
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return Allocate(total, weights)
+}
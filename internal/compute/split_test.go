@@ -0,0 +1,55 @@
+package compute
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitEven(t *testing.T) {
+	cases := []struct {
+		name  string
+		total float64
+		n     int
+		want  []float64
+	}{
+		{"exact", 9000, 3, []float64{3000, 3000, 3000}},
+		{"remainder to the first parts", 10000, 3, []float64{3334, 3333, 3333}},
+		{"two left over", 10001, 3, []float64{3334, 3334, 3333}},
+		{"one part", 4500, 1, []float64{4500}},
+		{"rounds the total", 100.6, 2, []float64{51, 50}},
+		{"no parts", 9000, 0, nil},
+	}
+	for _, c := range cases {
+		got := SplitEven(c.total, c.n)
+		if !slices.Equal(got, c.want) {
+			t.Fatalf("%s: got %v want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	cases := []struct {
+		name    string
+		total   float64
+		weights []float64
+		want    []float64
+	}{
+		{"by subtotal", 12000, []float64{8000, 4000}, []float64{8000, 4000}},
+		{"discounted total", 9000, []float64{8000, 4000}, []float64{6000, 3000}},
+		{"largest remainder", 1000, []float64{1, 1, 1, 3}, []float64{167, 167, 166, 500}},
+		{"no weight", 1000, []float64{0, 0}, []float64{500, 500}},
+	}
+	for _, c := range cases {
+		got := Allocate(c.total, c.weights)
+		if !slices.Equal(got, c.want) {
+			t.Fatalf("%s: got %v want %v", c.name, got, c.want)
+		}
+		var sum float64
+		for _, p := range got {
+			sum += p
+		}
+		if sum != c.total {
+			t.Fatalf("%s: parts add up to %.0f, not %.0f", c.name, sum, c.total)
+		}
+	}
+}
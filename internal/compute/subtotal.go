@@ -0,0 +1,12 @@
+package compute
+
+import "github.com/rustacean-dev/possystem/model"
+
+// Subtotal returns the sum of the line totals of all order lines.
+func Subtotal(lines []model.Item) float64 {
+	var total float64
+	for _, line := range lines {
+		total += LineTotal(line)
+	}
+	return total
+}
@@ -0,0 +1,25 @@
+package compute
+
+import (
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestSubtotal(t *testing.T) {
+	cases := []struct {
+		name  string
+		lines []model.Item
+		want  float64
+	}{
+		{"empty", nil, 0},
+		{"one line", []model.Item{{Price: 1500, Quantity: 2}}, 3000},
+		{"modifiers", []model.Item{{Price: 1500, Quantity: 2, Modifiers: []model.Modifier{{Name: "Large", PriceDelta: 500}}}}, 4000},
+		{"several lines", []model.Item{{Price: 1500, Quantity: 2}, {Price: 9000, Quantity: 1}, {Price: 500, Quantity: 0}}, 12000},
+	}
+	for _, c := range cases {
+		if got := Subtotal(c.lines); got != c.want {
+			t.Fatalf("%s: got %.2f want %.2f", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package dispatch sends delivery orders out with riders: it prices
+// deliveries by zone, times each delivery from dispatch to door, and
+// settles the cash riders collect at the end of their shift.
+package dispatch
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Board is the dispatch board: delivery orders still in the kitchen,
+// ready to go out, and out with a rider, each oldest first.
+type Board struct {
+	Preparing []model.Order
+	Ready     []model.Order
+	Out       []model.Order
+}
+
+// NewBoard sorts delivery orders onto the board. Orders of other types or
+// past delivery are left off.
+func NewBoard(orders []model.Order) Board {
+	var b Board
+	for _, o := range orders {
+		if o.Type != model.OrderDelivery {
+			continue
+		}
+		switch o.Status {
+		case model.OrderStatusPending, model.OrderStatusPreparing:
+			b.Preparing = append(b.Preparing, o)
+		case model.OrderStatusReady:
+			b.Ready = append(b.Ready, o)
+		case model.OrderStatusOutForDelivery:
+			b.Out = append(b.Out, o)
+		}
+	}
+	for _, col := range [][]model.Order{b.Preparing, b.Ready, b.Out} {
+		sort.SliceStable(col, func(i, j int) bool { return col[i].CreatedAt < col[j].CreatedAt })
+	}
+	return b
+}
+
+// Fee returns the delivery fee of a zone for an order with the given
+// subtotal.
+func Fee(z model.Zone, subtotal float64) float64 {
+	if z.FreeFrom > 0 && subtotal >= z.FreeFrom {
+		return 0
+	}
+	return z.Fee
+}
+
+// Riders returns the users with the rider role, by name.
+func Riders(users []model.User) []model.User {
+	var riders []model.User
+	for _, u := range users {
+		if u.Role == model.RoleRider {
+			riders = append(riders, u)
+		}
+	}
+	sort.Slice(riders, func(i, j int) bool {
+		return strings.ToLower(riders[i].Username) < strings.ToLower(riders[j].Username)
+	})
+	return riders
+}
+
+// Dispatch hands a ready delivery order to a rider and sends it out.
+func Dispatch(o model.Order, rider string, now time.Time) (model.Order, error) {
+	if o.Type != model.OrderDelivery {
+		return o, errors.New("only delivery orders are dispatched")
+	}
+	if o.Status != model.OrderStatusReady {
+		return o, errors.New("the order is not ready to go out")
+	}
+	if rider == "" {
+		return o, errors.New("choose a rider")
+	}
+	o.Rider = rider
+	o.Status = model.OrderStatusOutForDelivery
+	o.DispatchedAt = model.FormatTime(now)
+	return o, nil
+}
+
+// Deliver marks an order out for delivery as delivered. Cash orders are
+// paid to the rider, who now holds the order total.
+func Deliver(o model.Order, now time.Time) (model.Order, error) {
+	if o.Status != model.OrderStatusOutForDelivery {
+		return o, errors.New("the order is not out for delivery")
+	}
+	o.Status = model.OrderStatusCompleted
+	o.DeliveredAt = model.FormatTime(now)
+	o.Collected = 0
+	if o.Tender == "" || o.Tender == model.TenderCash {
+		o.Collected = o.TotalCost
+	}
+	return o, nil
+}
+
+// ViaBoard reports whether moving a delivery order to status must go
+// through the dispatch board, which records the rider, the times and the
+// cash collected: sending it out, and marking it delivered.
+func ViaBoard(o model.Order, status string) bool {
+	if o.Type != model.OrderDelivery || status == o.Status {
+		return false
+	}
+	return status == model.OrderStatusOutForDelivery ||
+		(o.Status == model.OrderStatusOutForDelivery && status == model.OrderStatusCompleted)
+}
+
+// Duration returns how long a delivery took from dispatch to the door, or
+// has taken so far if it is still out. It is zero if the order was never
+// dispatched.
+func Duration(o model.Order, now time.Time) time.Duration {
+	out, err := model.ParseTime(o.DispatchedAt)
+	if err != nil {
+		return 0
+	}
+	if in, err := model.ParseTime(o.DeliveredAt); err == nil {
+		now = in
+	}
+	return now.Sub(out)
+}
+
+// Outstanding returns a rider's delivered orders whose cash has not been
+// handed in yet, and the cash they hold.
+func Outstanding(orders []model.Order, rider string) ([]model.Order, float64) {
+	var owed []model.Order
+	var total float64
+	for _, o := range orders {
+		if o.Rider != rider || o.Status != model.OrderStatusCompleted || o.DeliveredAt == "" || o.Settlement != "" {
+			continue
+		}
+		owed = append(owed, o)
+		total += o.Collected
+	}
+	return owed, total
+}
+
+// Settle records the cash a rider handed in against the deliveries they
+// made. A negative difference means cash is missing.
+func Settle(rider string, orders []model.Order, counted float64, note, settledBy string) (model.RiderSettlement, error) {
+	if len(orders) == 0 {
+		return model.RiderSettlement{}, errors.New("there are no deliveries to settle")
+	}
+	if counted < 0 || math.IsNaN(counted) {
+		return model.RiderSettlement{}, errors.New("the counted cash cannot be negative")
+	}
+
+	s := model.RiderSettlement{
+		Rider:     rider,
+		Counted:   counted,
+		Note:      strings.TrimSpace(note),
+		SettledBy: settledBy,
+	}
+	for _, o := range orders {
+		s.Orders = append(s.Orders, o.ID)
+		s.Expected += o.Collected
+	}
+	s.Difference = s.Counted - s.Expected
+	return s, nil
+}
@@ -0,0 +1,122 @@
+package dispatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestNewBoard(t *testing.T) {
+	b := NewBoard([]model.Order{
+		{ID: "b", Type: model.OrderDelivery, Status: model.OrderStatusReady, CreatedAt: "2026-01-01 12:05:00.000Z"},
+		{ID: "a", Type: model.OrderDelivery, Status: model.OrderStatusReady, CreatedAt: "2026-01-01 12:00:00.000Z"},
+		{ID: "c", Type: model.OrderDelivery, Status: model.OrderStatusPending},
+		{ID: "d", Type: model.OrderDelivery, Status: model.OrderStatusOutForDelivery},
+		{ID: "e", Type: model.OrderDelivery, Status: model.OrderStatusCompleted},
+		{ID: "f", Type: model.OrderTakeaway, Status: model.OrderStatusReady},
+	})
+	if len(b.Ready) != 2 || b.Ready[0].ID != "a" || len(b.Preparing) != 1 || len(b.Out) != 1 {
+		t.Fatalf("got %+v", b)
+	}
+}
+
+func TestFee(t *testing.T) {
+	z := model.Zone{Fee: 3000, FreeFrom: 50000}
+	if f := Fee(z, 20000); f != 3000 {
+		t.Fatalf("fee %v", f)
+	}
+	if f := Fee(z, 50000); f != 0 {
+		t.Fatalf("fee over the free threshold %v", f)
+	}
+	if f := Fee(model.Zone{Fee: 2000}, 1e6); f != 2000 {
+		t.Fatalf("fee without a threshold %v", f)
+	}
+}
+
+func TestDispatchAndDeliver(t *testing.T) {
+	now := time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)
+	o := model.Order{Type: model.OrderDelivery, Status: model.OrderStatusReady, Tender: model.TenderCash, TotalCost: 23000}
+
+	if _, err := Dispatch(o, "", now); err == nil {
+		t.Fatal("dispatched without a rider")
+	}
+	if _, err := Dispatch(model.Order{Type: model.OrderTakeaway, Status: model.OrderStatusReady}, "r1", now); err == nil {
+		t.Fatal("dispatched a takeaway")
+	}
+	if _, err := Deliver(o, now); err == nil {
+		t.Fatal("delivered an order that never went out")
+	}
+
+	out, err := Dispatch(o, "r1", now)
+	if err != nil || out.Status != model.OrderStatusOutForDelivery || out.Rider != "r1" || out.DispatchedAt == "" {
+		t.Fatalf("got %+v, %v", out, err)
+	}
+	if d := Duration(out, now.Add(10*time.Minute)); d != 10*time.Minute {
+		t.Fatalf("out for %v", d)
+	}
+
+	done, err := Deliver(out, now.Add(25*time.Minute))
+	if err != nil || done.Status != model.OrderStatusCompleted || done.Collected != 23000 {
+		t.Fatalf("got %+v, %v", done, err)
+	}
+	if d := Duration(done, now.Add(time.Hour)); d != 25*time.Minute {
+		t.Fatalf("took %v", d)
+	}
+
+	out.Tender = model.TenderCard
+	if done, _ := Deliver(out, now); done.Collected != 0 {
+		t.Fatalf("collected %v on a card order", done.Collected)
+	}
+}
+
+func TestViaBoard(t *testing.T) {
+	ready := model.Order{Type: model.OrderDelivery, Status: model.OrderStatusReady}
+	out := model.Order{Type: model.OrderDelivery, Status: model.OrderStatusOutForDelivery}
+	if !ViaBoard(ready, model.OrderStatusOutForDelivery) || !ViaBoard(out, model.OrderStatusCompleted) {
+		t.Fatal("dispatch and delivery must go through the board")
+	}
+	if ViaBoard(ready, model.OrderStatusCancelled) || ViaBoard(out, model.OrderStatusCancelled) {
+		t.Fatal("cancelling must not need the board")
+	}
+	if ViaBoard(model.Order{Type: model.OrderTakeaway, Status: model.OrderStatusReady}, model.OrderStatusCompleted) {
+		t.Fatal("takeaways are not dispatched")
+	}
+}
+
+func TestSettle(t *testing.T) {
+	orders := []model.Order{
+		{ID: "a", Rider: "r1", Status: model.OrderStatusCompleted, DeliveredAt: "x", Collected: 10000},
+		{ID: "b", Rider: "r1", Status: model.OrderStatusCompleted, DeliveredAt: "x", Collected: 5000},
+		{ID: "c", Rider: "r1", Status: model.OrderStatusCompleted, DeliveredAt: "x", Collected: 7000, Settlement: "s0"},
+		{ID: "d", Rider: "r2", Status: model.OrderStatusCompleted, DeliveredAt: "x", Collected: 9000},
+		{ID: "e", Rider: "r1", Status: model.OrderStatusOutForDelivery, Collected: 0},
+	}
+
+	owed, total := Outstanding(orders, "r1")
+	if len(owed) != 2 || total != 15000 {
+		t.Fatalf("got %+v, %v", owed, total)
+	}
+
+	s, err := Settle("r1", owed, 14000, " short ", "m1")
+	if err != nil || s.Expected != 15000 || s.Difference != -1000 || len(s.Orders) != 2 || s.Note != "short" {
+		t.Fatalf("got %+v, %v", s, err)
+	}
+	if _, err := Settle("r1", nil, 0, "", "m1"); err == nil {
+		t.Fatal("settled nothing")
+	}
+	if _, err := Settle("r1", owed, -1, "", "m1"); err == nil {
+		t.Fatal("accepted negative cash")
+	}
+}
+
+func TestRiders(t *testing.T) {
+	riders := Riders([]model.User{
+		{ID: "1", Username: "zuberi", Role: model.RoleRider},
+		{ID: "2", Username: "cashier"},
+		{ID: "3", Username: "Baraka", Role: model.RoleRider},
+	})
+	if len(riders) != 2 || riders[0].Username != "Baraka" {
+		t.Fatalf("got %+v", riders)
+	}
+}
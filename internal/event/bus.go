@@ -0,0 +1,95 @@
+package event
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// DedupeWindow is how long an event key is remembered to drop a second
+// report of the same change.
+const DedupeWindow = 5 * time.Minute
+
+// Bus delivers published events to subscribers within the process. It is
+// safe for concurrent use, and a nil *Bus drops everything published on it.
+type Bus struct {
+	mu   sync.Mutex
+	subs map[chan Event][]string
+	seen map[string]time.Time
+	now  func() time.Time
+}
+
+// NewBus returns a Bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{
+		subs: map[chan Event][]string{},
+		seen: map[string]time.Time{},
+		now:  time.Now,
+	}
+}
+
+// Subscribe returns a channel receiving the events with the given names, or
+// every event if no names are given. Call cancel when done listening.
+func (b *Bus) Subscribe(names ...string) (events <-chan Event, cancel func()) {
+	ch := make(chan Event, 32)
+
+	b.mu.Lock()
+	b.subs[ch] = names
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish hands an event to its subscribers, unless an event with the same
+// key was published within [DedupeWindow]. A subscriber that has fallen
+// behind misses the event rather than holding up the publisher.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.first(e.Key()) {
+		return
+	}
+
+	for ch, names := range b.subs {
+		if len(names) > 0 && !slices.Contains(names, e.Name()) {
+			continue
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// first remembers key and reports whether it is new. Expired keys are
+// forgotten along the way. b.mu must be held.
+func (b *Bus) first(key string) bool {
+	if key == "" {
+		return true
+	}
+
+	now := b.now()
+	for k, at := range b.seen {
+		if now.Sub(at) > DedupeWindow {
+			delete(b.seen, k)
+		}
+	}
+
+	if _, ok := b.seen[key]; ok {
+		return false
+	}
+	b.seen[key] = now
+	return true
+}
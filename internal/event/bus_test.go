@@ -0,0 +1,101 @@
+package event
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestBus(t *testing.T) {
+	b := NewBus()
+	orders, cancelOrders := b.Subscribe(NameOrderCreated)
+	all, cancelAll := b.Subscribe()
+	defer cancelAll()
+
+	b.Publish(StockChanged{Movement: "m1", Item: "soda", Quantity: -1})
+	b.Publish(OrderCreated{Order: model.Order{ID: "o1"}})
+
+	if got := <-orders; got.Key() != "order.created:o1" {
+		t.Fatalf("orders got %s", got.Key())
+	}
+	if a, c := <-all, <-all; a.Name() != NameStockChanged || c.Name() != NameOrderCreated {
+		t.Fatalf("all got %s, %s", a.Name(), c.Name())
+	}
+
+	// Cancelling closes the channel and stops delivery
+	cancelOrders()
+	cancelOrders()
+	b.Publish(OrderCreated{Order: model.Order{ID: "o2"}})
+	if _, ok := <-orders; ok {
+		t.Fatal("cancelled subscription still receives")
+	}
+	<-all
+
+	// A full subscriber does not block publishing
+	for i := range 50 {
+		b.Publish(TicketChanged{Ticket: model.Ticket{ID: "t", Status: string(rune('a' + i))}})
+	}
+
+	var nilBus *Bus
+	nilBus.Publish(OrderCreated{})
+}
+
+func TestBusDedupe(t *testing.T) {
+	now := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+	b := NewBus()
+	b.now = func() time.Time { return now }
+	events, cancel := b.Subscribe()
+	defer cancel()
+
+	b.Publish(OrderStatusChanged{Order: "o1", From: "pending", To: "completed", At: "t1"})
+	b.Publish(OrderStatusChanged{Order: "o1", From: "pending", To: "completed", At: "t1"})
+	b.Publish(StockChanged{Item: "soda"})
+	b.Publish(StockChanged{Item: "soda"})
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want the repeated status change dropped", len(events))
+	}
+
+	now = now.Add(DedupeWindow + time.Second)
+	b.Publish(OrderStatusChanged{Order: "o1", From: "pending", To: "completed", At: "t1"})
+	if len(events) != 4 {
+		t.Fatal("key was not forgotten after the window")
+	}
+
+	// Each part of a split bill is a payment of its own
+	b.Publish(PaymentReceived{Order: "o1", Payment: "p1", Amount: 5000})
+	b.Publish(PaymentReceived{Order: "o1", Payment: "p2", Amount: 5000})
+	b.Publish(PaymentReceived{Order: "o1", Payment: "p2", Amount: 5000})
+	if len(events) != 6 {
+		t.Fatalf("got %d events, want one per part", len(events))
+	}
+
+	// An order going back to a status it had is a change of its own
+	b.Publish(OrderStatusChanged{Order: "o2", From: "preparing", To: "ready", At: "t1"})
+	b.Publish(OrderStatusChanged{Order: "o2", From: "ready", To: "preparing", At: "t2"})
+	b.Publish(OrderStatusChanged{Order: "o2", From: "preparing", To: "ready", At: "t3"})
+	if len(events) != 9 {
+		t.Fatalf("got %d events, want every status change", len(events))
+	}
+}
+
+func TestEncode(t *testing.T) {
+	now := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+	data, err := Encode(PaymentReceived{Order: "o1", Tender: "cash", Amount: 5000}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Type string
+		Time time.Time
+		Data PaymentReceived
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != NamePaymentReceived || !got.Time.Equal(now) || got.Data.Amount != 5000 {
+		t.Fatalf("got %s", data)
+	}
+}
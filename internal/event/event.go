@@ -0,0 +1,133 @@
+// Package event carries changes to orders, stock and the kitchen from where
+// they happen to whoever reacts to them: kitchen screens, dashboards and
+// webhooks.
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Event is something that happened, published on a [Bus].
+type Event interface {
+	// Name identifies the kind of event, e.g. "order.created".
+	Name() string
+	// Key identifies this one occurrence. The same change reported twice,
+	// by the app and by the PocketBase bridge, shares a key and is only
+	// delivered once. Events with an empty key are always delivered.
+	Key() string
+}
+
+// Event names.
+const (
+	NameOrderCreated       = "order.created"
+	NameOrderStatusChanged = "order.status_changed"
+	NameStockChanged       = "stock.changed"
+	NamePaymentReceived    = "payment.received"
+	NameTicketChanged      = "ticket.changed"
+)
+
+// Names lists every event name.
+var Names = []string{
+	NameOrderCreated,
+	NameOrderStatusChanged,
+	NameStockChanged,
+	NamePaymentReceived,
+	NameTicketChanged,
+}
+
+// OrderCreated is published when an order is placed.
+type OrderCreated struct {
+	Order model.Order `json:"order"`
+}
+
+func (e OrderCreated) Name() string { return NameOrderCreated }
+func (e OrderCreated) Key() string  { return NameOrderCreated + ":" + e.Order.ID }
+
+// OrderStatusChanged is published when an order moves to another status.
+// From is empty when the previous status is not known. At is when the
+// order was saved with its new status, which tells an order moving back
+// and forth between two statuses apart from the same change reported twice.
+type OrderStatusChanged struct {
+	Order string `json:"order"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to"`
+	At    string `json:"at,omitempty"`
+}
+
+func (e OrderStatusChanged) Name() string { return NameOrderStatusChanged }
+func (e OrderStatusChanged) Key() string {
+	return NameOrderStatusChanged + ":" + e.Order + ":" + e.From + ":" + e.To + ":" + e.At
+}
+
+// StockChanged is published for every stock movement that changed the
+// stored quantity of an item or ingredient. Quantity is the change.
+type StockChanged struct {
+	Movement   string  `json:"movement"`
+	Item       string  `json:"item,omitempty"`
+	Ingredient string  `json:"ingredient,omitempty"`
+	Kind       string  `json:"kind"`
+	Quantity   float64 `json:"quantity"`
+	Order      string  `json:"order,omitempty"`
+}
+
+func (e StockChanged) Name() string { return NameStockChanged }
+func (e StockChanged) Key() string {
+	if e.Movement == "" {
+		return ""
+	}
+	return NameStockChanged + ":" + e.Movement
+}
+
+// PaymentReceived is published when an order is paid, or one part of its
+// split bill. Payment is the ID of that part.
+type PaymentReceived struct {
+	Order   string  `json:"order"`
+	Payment string  `json:"payment,omitempty"`
+	Tender  string  `json:"tender"`
+	Amount  float64 `json:"amount"`
+}
+
+func (e PaymentReceived) Name() string { return NamePaymentReceived }
+func (e PaymentReceived) Key() string {
+	if e.Payment != "" {
+		return NamePaymentReceived + ":" + e.Order + ":" + e.Payment
+	}
+	return NamePaymentReceived + ":" + e.Order
+}
+
+// TicketChanged is published when a kitchen ticket is created or bumped.
+type TicketChanged struct {
+	Ticket model.Ticket `json:"ticket"`
+}
+
+func (e TicketChanged) Name() string { return NameTicketChanged }
+func (e TicketChanged) Key() string {
+	return NameTicketChanged + ":" + e.Ticket.ID + ":" + e.Ticket.Status
+}
+
+// Paid returns the events of an order being placed and paid for. Tabs are
+// paid when they are closed, so opening one is only an order placed.
+func Paid(o model.Order) []Event {
+	if o.Status == model.OrderStatusOpen {
+		return []Event{OrderCreated{Order: o}}
+	}
+	return []Event{
+		OrderCreated{Order: o},
+		PaymentReceived{Order: o.ID, Tender: o.Tender, Amount: o.TotalCost},
+	}
+}
+
+// Envelope is how events are sent to browsers and webhooks.
+type Envelope struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data Event     `json:"data"`
+}
+
+// Encode wraps an event in an [Envelope] and returns it as JSON.
+func Encode(e Event, now time.Time) ([]byte, error) {
+	return json.Marshal(Envelope{Type: e.Name(), Time: now.UTC(), Data: e})
+}
@@ -0,0 +1,237 @@
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// BridgeOptions configure [Bridge].
+type BridgeOptions struct {
+	Log *slog.Logger
+	// URL of PocketBase; defaults to http://127.0.0.1:8090.
+	URL string
+	// Token returns an auth token the subscriptions are made with. It is
+	// called on every (re)connect.
+	Token func(ctx context.Context) (string, error)
+	// Retry is the pause before reconnecting; defaults to five seconds.
+	Retry time.Duration
+}
+
+// Bridge subscribes to PocketBase's realtime API and publishes changes to
+// orders and the stock ledger on a [Bus]. That way changes made outside
+// this app, in the PocketBase dashboard or by another instance, reach the
+// subscribers too. Changes this app publishes itself are reported again by
+// PocketBase and dropped by the bus as duplicates.
+type Bridge struct {
+	bus      *Bus
+	log      *slog.Logger
+	url      string
+	token    func(ctx context.Context) (string, error)
+	retry    time.Duration
+	statuses map[string]string
+}
+
+// NewBridge returns a Bridge publishing on bus.
+func NewBridge(bus *Bus, opts BridgeOptions) *Bridge {
+	if opts.Log == nil {
+		opts.Log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	if opts.URL == "" {
+		opts.URL = "http://127.0.0.1:8090"
+	}
+	if opts.Retry <= 0 {
+		opts.Retry = 5 * time.Second
+	}
+	return &Bridge{
+		bus:      bus,
+		log:      opts.Log,
+		url:      strings.TrimSuffix(opts.URL, "/"),
+		token:    opts.Token,
+		retry:    opts.Retry,
+		statuses: map[string]string{},
+	}
+}
+
+// Run keeps a realtime connection open until ctx is done, reconnecting
+// after errors.
+func (b *Bridge) Run(ctx context.Context) error {
+	b.log.Info("Starting PocketBase realtime bridge", "url", b.url)
+
+	for {
+		if err := b.listen(ctx); err != nil && ctx.Err() == nil {
+			b.log.Error("Error in PocketBase realtime connection", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			b.log.Info("Stopped PocketBase realtime bridge")
+			return nil
+		case <-time.After(b.retry):
+		}
+	}
+}
+
+// listen connects once and publishes events until the connection ends.
+func (b *Bridge) listen(ctx context.Context) error {
+	token, err := b.token(ctx)
+	if err != nil {
+		return fmt.Errorf("logging in: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", b.url+"/api/realtime", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream stays open, so no client timeout
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("realtime connection refused (%d)", resp.StatusCode)
+	}
+
+	return readSSE(resp.Body, func(name string, data []byte) error {
+		if name == "PB_CONNECT" {
+			var connect struct {
+				ClientID string `json:"clientId"`
+			}
+			if err := json.Unmarshal(data, &connect); err != nil {
+				return err
+			}
+			return b.subscribe(ctx, connect.ClientID, token)
+		}
+
+		collection, _, _ := strings.Cut(name, "/")
+		var msg struct {
+			Action string          `json:"action"`
+			Record json.RawMessage `json:"record"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return err
+		}
+		for _, e := range b.translate(collection, msg.Action, msg.Record) {
+			b.bus.Publish(e)
+		}
+		return nil
+	})
+}
+
+// subscribe asks PocketBase to send changes of orders and stock movements
+// to the connected client.
+func (b *Bridge) subscribe(ctx context.Context, clientID, token string) error {
+	data, _ := json.Marshal(map[string]any{
+		"clientId":      clientID,
+		"subscriptions": []string{"orders/*", "stock_movements/*"},
+	})
+	req, err := http.NewRequestWithContext(ctx, "POST", b.url+"/api/realtime", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to subscribe (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// translate turns a PocketBase record change into events. Status changes
+// are only noticed for orders the bridge has seen before; a tab closing is
+// also its payment.
+func (b *Bridge) translate(collection, action string, record json.RawMessage) []Event {
+	switch collection {
+	case "orders":
+		var o model.Order
+		if err := json.Unmarshal(record, &o); err != nil {
+			return nil
+		}
+		before, known := b.statuses[o.ID]
+		b.statuses[o.ID] = o.Status
+
+		switch {
+		case action == "create":
+			return Paid(o)
+		case action == "update" && known && before != o.Status:
+			changed := OrderStatusChanged{Order: o.ID, From: before, To: o.Status, At: o.Updated}
+			// Split bills are paid part by part instead
+			if before == model.OrderStatusOpen && o.Status == model.OrderStatusCompleted && o.Tender != model.TenderSplit {
+				return []Event{changed, PaymentReceived{Order: o.ID, Tender: o.Tender, Amount: o.TotalCost}}
+			}
+			return []Event{changed}
+		}
+
+	case "stock_movements":
+		if action != "create" {
+			return nil
+		}
+		var m model.StockMovement
+		if err := json.Unmarshal(record, &m); err != nil {
+			return nil
+		}
+		return []Event{StockChanged{
+			Movement:   m.ID,
+			Item:       m.Item,
+			Ingredient: m.Ingredient,
+			Kind:       m.Kind,
+			Quantity:   m.Quantity,
+			Order:      m.Order,
+		}}
+	}
+	return nil
+}
+
+// readSSE calls fn for every message of a Server-Sent Events stream until
+// the stream ends or fn fails.
+func readSSE(r io.Reader, fn func(name string, data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var name string
+	var data []byte
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data != nil {
+				if err := fn(name, data); err != nil {
+					return err
+				}
+			}
+			name, data = "", nil
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data != nil {
+				data = append(data, '\n')
+			}
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
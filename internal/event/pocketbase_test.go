@@ -0,0 +1,133 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadSSE(t *testing.T) {
+	stream := "id:abc\nevent:PB_CONNECT\ndata:{\"clientId\":\"abc\"}\n\n" +
+		": comment\n\n" +
+		"event: orders/*\ndata: {\"a\":1,\ndata: \"b\":2}\n\n"
+
+	var got []string
+	err := readSSE(strings.NewReader(stream), func(name string, data []byte) error {
+		got = append(got, name+" "+string(data))
+		return nil
+	})
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("err = %v", err)
+	}
+	want := []string{`PB_CONNECT {"clientId":"abc"}`, "orders/* {\"a\":1,\n\"b\":2}"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestBridgeTranslate(t *testing.T) {
+	b := NewBridge(NewBus(), BridgeOptions{})
+	record := func(s string) json.RawMessage { return json.RawMessage(s) }
+
+	got := b.translate("orders", "create", record(`{"id":"o1","status":"pending","tender":"card","totalcost":9000}`))
+	if len(got) != 2 || got[0].Key() != "order.created:o1" || got[1].(PaymentReceived).Amount != 9000 {
+		t.Fatalf("create: %+v", got)
+	}
+
+	// Edits without a status change are not events
+	if got := b.translate("orders", "update", record(`{"id":"o1","status":"pending"}`)); len(got) != 0 {
+		t.Fatalf("edit: %+v", got)
+	}
+	got = b.translate("orders", "update", record(`{"id":"o1","status":"refunded","updated_at":"2025-03-12 18:00:00.000Z"}`))
+	if want := (OrderStatusChanged{Order: "o1", From: "pending", To: "refunded", At: "2025-03-12 18:00:00.000Z"}); len(got) != 1 || got[0] != want {
+		t.Fatalf("status: %+v", got)
+	}
+
+	// Orders not seen before have no known status to compare against
+	if got := b.translate("orders", "update", record(`{"id":"o2","status":"refunded"}`)); len(got) != 0 {
+		t.Fatalf("unknown order: %+v", got)
+	}
+
+	// Tabs are paid when they close
+	got = b.translate("orders", "create", record(`{"id":"o3","status":"open"}`))
+	if len(got) != 1 || got[0].Name() != NameOrderCreated {
+		t.Fatalf("open tab: %+v", got)
+	}
+	got = b.translate("orders", "update", record(`{"id":"o3","status":"completed","tender":"cash","totalcost":12000}`))
+	if want := (PaymentReceived{Order: "o3", Tender: "cash", Amount: 12000}); len(got) != 2 || got[1] != want {
+		t.Fatalf("closed tab: %+v", got)
+	}
+	b.translate("orders", "create", record(`{"id":"o4","status":"open"}`))
+	if got := b.translate("orders", "update", record(`{"id":"o4","status":"completed","tender":"split"}`)); len(got) != 1 {
+		t.Fatalf("split tab: %+v", got)
+	}
+
+	got = b.translate("stock_movements", "create", record(`{"id":"m1","item":"soda","kind":"sale","quantity":-2,"order":"o1"}`))
+	if want := (StockChanged{Movement: "m1", Item: "soda", Kind: "sale", Quantity: -2, Order: "o1"}); len(got) != 1 || got[0] != want {
+		t.Fatalf("movement: %+v", got)
+	}
+}
+
+func TestBridgeRun(t *testing.T) {
+	subscribed := make(chan string, 1)
+	subscriptions := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			var body struct {
+				ClientID      string   `json:"clientId"`
+				Subscriptions []string `json:"subscriptions"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			subscribed <- r.Header.Get("Authorization") + " " + body.ClientID + " " + strings.Join(body.Subscriptions, ",")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id:c1\nevent:PB_CONNECT\ndata:{\"clientId\":\"c1\"}\n\n")
+		w.(http.Flusher).Flush()
+		subscriptions <- <-subscribed
+		fmt.Fprint(w, "event:stock_movements/*\ndata:{\"action\":\"create\",\"record\":{\"id\":\"m1\",\"item\":\"soda\",\"kind\":\"waste\",\"quantity\":-1}}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	bus := NewBus()
+	events, cancelEvents := bus.Subscribe(NameStockChanged)
+	defer cancelEvents()
+
+	b := NewBridge(bus, BridgeOptions{
+		URL:   srv.URL,
+		Token: func(context.Context) (string, error) { return "tok", nil },
+		Retry: time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- b.Run(ctx) }()
+
+	select {
+	case e := <-events:
+		if e.Key() != "stock.changed:m1" {
+			t.Fatalf("got %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+
+	if got := <-subscriptions; got != "Bearer tok c1 orders/*,stock_movements/*" {
+		t.Fatalf("subscribed with %q", got)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
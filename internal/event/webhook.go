@@ -0,0 +1,146 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook body, as
+// "sha256=<hex>", when a secret is configured.
+const SignatureHeader = "X-POS-Signature"
+
+// WebhookOptions configure [Webhooks].
+type WebhookOptions struct {
+	Log *slog.Logger
+	// URLs every event is posted to.
+	URLs []string
+	// Secret signs the body of each delivery; optional.
+	Secret string
+	// Names of the events to deliver; all events if empty.
+	Names []string
+	// Client defaults to one with a ten second timeout.
+	Client *http.Client
+	// Attempts per delivery before giving up; defaults to three.
+	Attempts int
+}
+
+// Webhooks posts events from a [Bus] to external URLs as JSON envelopes.
+type Webhooks struct {
+	bus      *Bus
+	log      *slog.Logger
+	urls     []string
+	secret   string
+	names    []string
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+}
+
+// NewWebhooks returns Webhooks delivering the events published on bus.
+func NewWebhooks(bus *Bus, opts WebhookOptions) *Webhooks {
+	if opts.Log == nil {
+		opts.Log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.Attempts <= 0 {
+		opts.Attempts = 3
+	}
+	return &Webhooks{
+		bus:      bus,
+		log:      opts.Log,
+		urls:     opts.URLs,
+		secret:   opts.Secret,
+		names:    opts.Names,
+		client:   opts.Client,
+		attempts: opts.Attempts,
+		backoff:  time.Second,
+	}
+}
+
+// Run delivers events until ctx is done. Failed deliveries are logged and
+// dropped once every attempt has failed.
+func (w *Webhooks) Run(ctx context.Context) error {
+	w.log.Info("Starting webhooks", "urls", len(w.urls))
+
+	events, cancel := w.bus.Subscribe(w.names...)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.log.Info("Stopped webhooks")
+			return nil
+		case e := <-events:
+			body, err := Encode(e, time.Now())
+			if err != nil {
+				w.log.Error("Error encoding event", "event", e.Name(), "error", err)
+				continue
+			}
+			for _, url := range w.urls {
+				if err := w.deliver(ctx, url, e.Name(), body); err != nil {
+					w.log.Error("Error delivering webhook", "url", url, "event", e.Name(), "error", err)
+				}
+			}
+		}
+	}
+}
+
+// deliver posts body to url, retrying with a growing pause in between.
+func (w *Webhooks) deliver(ctx context.Context, url, name string, body []byte) error {
+	var err error
+	for attempt := range w.attempts {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(w.backoff * time.Duration(attempt)):
+			}
+		}
+		if err = w.post(ctx, url, name, body); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (w *Webhooks) post(ctx context.Context, url, name string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-POS-Event", name)
+	if w.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature of a webhook body, for [SignatureHeader].
+// Receivers recompute it with the shared secret to check the sender.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,76 @@
+package event
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhooks(t *testing.T) {
+	var calls atomic.Int32
+	bodies := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first attempt fails, the retry succeeds
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(SignatureHeader) != Sign("s3cret", body) || r.Header.Get("X-POS-Event") != NameStockChanged {
+			t.Errorf("bad headers %v", r.Header)
+		}
+		bodies <- string(body)
+	}))
+	defer srv.Close()
+
+	bus := NewBus()
+	wh := NewWebhooks(bus, WebhookOptions{URLs: []string{srv.URL}, Secret: "s3cret", Names: []string{NameStockChanged}})
+	wh.backoff = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- wh.Run(ctx) }()
+
+	// Wait for Run to subscribe
+	for {
+		bus.mu.Lock()
+		n := len(bus.subs)
+		bus.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	bus.Publish(PaymentReceived{Order: "o1"})
+	bus.Publish(StockChanged{Movement: "m1", Item: "soda", Quantity: -2})
+
+	select {
+	case body := <-bodies:
+		if want := `"type":"stock.changed"`; !strings.Contains(body, want) {
+			t.Fatalf("body %s", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery")
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("%d calls", calls.Load())
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	got := Sign("key", []byte("The quick brown fox jumps over the lazy dog"))
+	if want := "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"; got != want {
+		t.Fatalf("got %s", got)
+	}
+}
@@ -0,0 +1,193 @@
+// Package floor manages dine-in tables: seating guests, asking for the
+// bill, merging tables for larger parties and laying them out on the floor
+// plan.
+package floor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Area is one room or section of the floor plan, with its tables placed
+// on a grid of Cols by Rows cells.
+type Area struct {
+	Name   string
+	Tables []model.Table
+	Cols   int
+	Rows   int
+}
+
+// Areas groups tables by area, in area name order, each sorted by number.
+func Areas(tables []model.Table) []Area {
+	byName := map[string]*Area{}
+	var names []string
+	for _, t := range tables {
+		a, ok := byName[t.Area]
+		if !ok {
+			a = &Area{Name: t.Area, Cols: 1, Rows: 1}
+			byName[t.Area] = a
+			names = append(names, t.Area)
+		}
+		a.Tables = append(a.Tables, t)
+		a.Cols = max(a.Cols, t.X)
+		a.Rows = max(a.Rows, t.Y)
+	}
+	sort.Strings(names)
+
+	areas := make([]Area, 0, len(names))
+	for _, name := range names {
+		a := byName[name]
+		sort.SliceStable(a.Tables, func(i, j int) bool { return a.Tables[i].Number < a.Tables[j].Number })
+		areas = append(areas, *a)
+	}
+	return areas
+}
+
+// Main returns the table a table is merged into, or the table itself. The
+// main table holds the status and orders of the party.
+func Main(t model.Table, byID map[string]model.Table) model.Table {
+	if t.MergedInto == "" {
+		return t
+	}
+	if main, ok := byID[t.MergedInto]; ok {
+		return main
+	}
+	return t
+}
+
+// Status returns the status shown for a table: that of its main table,
+// and free for tables that were never seated.
+func Status(t model.Table, byID map[string]model.Table) string {
+	if s := Main(t, byID).Status; s != "" {
+		return s
+	}
+	return model.TableFree
+}
+
+// Seat marks a free table as occupied from now. Occupied tables keep the
+// time their guests sat down.
+func Seat(t model.Table, now time.Time) model.Table {
+	if t.Status == "" || t.Status == model.TableFree {
+		t.Status = model.TableOccupied
+		t.SeatedAt = model.FormatTime(now)
+	}
+	return t
+}
+
+// Receive seats a table for an order placed at or moved to it. The seating
+// time goes back to when the order was placed if that was earlier, so the
+// order counts toward the current sitting.
+func Receive(t model.Table, o model.Order, now time.Time) model.Table {
+	t = Seat(t, now)
+	if o.CreatedAt != "" && o.CreatedAt < t.SeatedAt {
+		t.SeatedAt = o.CreatedAt
+	}
+	return t
+}
+
+// RequestBill marks an occupied table as waiting for the bill.
+func RequestBill(t model.Table) (model.Table, error) {
+	if t.Status != model.TableOccupied && t.Status != model.TableBillRequested {
+		return t, errors.New("only occupied tables can ask for the bill")
+	}
+	t.Status = model.TableBillRequested
+	return t, nil
+}
+
+// Clear frees the table with the given ID once its guests have left, along
+// with the tables merged into it. It returns the tables that changed.
+func Clear(tables []model.Table, id string) []model.Table {
+	var changed []model.Table
+	for _, t := range tables {
+		if t.ID != id && t.MergedInto != id {
+			continue
+		}
+		t.Status = model.TableFree
+		t.SeatedAt = ""
+		t.MergedInto = ""
+		changed = append(changed, t)
+	}
+	return changed
+}
+
+// Merge joins table other into table target for one larger party. The
+// target is seated if it was free, keeping the earlier seating time of
+// the two, and tables already merged into other move along to target.
+// It returns the tables that changed.
+func Merge(tables []model.Table, target, other string, now time.Time) ([]model.Table, error) {
+	byID := map[string]model.Table{}
+	for _, t := range tables {
+		byID[t.ID] = t
+	}
+
+	main, ok := byID[target]
+	if !ok {
+		return nil, errors.New("unknown table")
+	}
+	joined, ok := byID[other]
+	if !ok {
+		return nil, errors.New("unknown table")
+	}
+	if target == other {
+		return nil, errors.New("a table cannot be merged with itself")
+	}
+	if main.MergedInto != "" {
+		return nil, fmt.Errorf("table %d is merged into another table", main.Number)
+	}
+	if joined.MergedInto != "" {
+		return nil, fmt.Errorf("table %d is already merged", joined.Number)
+	}
+
+	seatedAt := main.SeatedAt
+	if joined.SeatedAt != "" && (seatedAt == "" || joined.SeatedAt < seatedAt) {
+		seatedAt = joined.SeatedAt
+	}
+	main = Seat(main, now)
+	if seatedAt != "" {
+		main.SeatedAt = seatedAt
+	}
+	if joined.Status == model.TableBillRequested {
+		main.Status = model.TableBillRequested
+	}
+
+	changed := []model.Table{main}
+	for _, t := range tables {
+		if t.ID == other || t.MergedInto == other {
+			t.MergedInto = target
+			t.Status = model.TableFree
+			t.SeatedAt = ""
+			changed = append(changed, t)
+		}
+	}
+	return changed, nil
+}
+
+// Split takes a merged table back out of its party and frees it. Tables
+// that are not merged into another are refused: freeing them would drop
+// their sitting, or strand the tables merged into them.
+func Split(t model.Table) (model.Table, error) {
+	if t.MergedInto == "" {
+		return t, fmt.Errorf("table %d is not merged into another table", t.Number)
+	}
+	t.MergedInto = ""
+	t.Status = model.TableFree
+	t.SeatedAt = ""
+	return t, nil
+}
+
+// Elapsed returns how long the guests at a table have been seated, or zero
+// for free tables.
+func Elapsed(t model.Table, now time.Time) time.Duration {
+	if t.Status == "" || t.Status == model.TableFree {
+		return 0
+	}
+	seated, err := model.ParseTime(t.SeatedAt)
+	if err != nil || seated.After(now) {
+		return 0
+	}
+	return now.Sub(seated)
+}
@@ -0,0 +1,136 @@
+package floor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestAreas(t *testing.T) {
+	areas := Areas([]model.Table{
+		{ID: "t3", Number: 3, Area: "Terrace", X: 2, Y: 1},
+		{ID: "t2", Number: 2, Area: "Hall", X: 1, Y: 3},
+		{ID: "t1", Number: 1, Area: "Hall", X: 4, Y: 1},
+	})
+
+	if len(areas) != 2 || areas[0].Name != "Hall" || areas[1].Name != "Terrace" {
+		t.Fatalf("got %+v", areas)
+	}
+	if hall := areas[0]; hall.Cols != 4 || hall.Rows != 3 || hall.Tables[0].Number != 1 {
+		t.Fatalf("hall %+v", hall)
+	}
+	if terrace := areas[1]; terrace.Cols != 2 || terrace.Rows != 1 {
+		t.Fatalf("terrace %+v", terrace)
+	}
+}
+
+func TestSeatAndBill(t *testing.T) {
+	now := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+
+	table := Seat(model.Table{ID: "t1"}, now)
+	if table.Status != model.TableOccupied || table.SeatedAt != "2025-03-12 18:00:00.000Z" {
+		t.Fatalf("got %+v", table)
+	}
+	if again := Seat(table, now.Add(time.Hour)); again.SeatedAt != table.SeatedAt {
+		t.Fatal("seating an occupied table reset its time")
+	}
+	if got := Elapsed(table, now.Add(42*time.Minute)); got != 42*time.Minute {
+		t.Fatalf("elapsed %s", got)
+	}
+
+	table, err := RequestBill(table)
+	if err != nil || table.Status != model.TableBillRequested {
+		t.Fatalf("got %+v, %v", table, err)
+	}
+	if _, err := RequestBill(model.Table{Status: model.TableFree}); err == nil {
+		t.Fatal("free table asked for the bill")
+	}
+	if Elapsed(model.Table{Status: model.TableFree, SeatedAt: table.SeatedAt}, now) != 0 {
+		t.Fatal("free table has an elapsed time")
+	}
+}
+
+func TestMergeAndClear(t *testing.T) {
+	now := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+	tables := []model.Table{
+		{ID: "t1", Number: 1, Status: model.TableOccupied, SeatedAt: "2025-03-12 17:30:00.000Z"},
+		{ID: "t2", Number: 2, Status: model.TableOccupied, SeatedAt: "2025-03-12 17:10:00.000Z"},
+		{ID: "t3", Number: 3, MergedInto: "t2"},
+		{ID: "t4", Number: 4},
+	}
+
+	changed, err := Merge(tables, "t1", "t2", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 3 {
+		t.Fatalf("got %+v", changed)
+	}
+	if main := changed[0]; main.SeatedAt != "2025-03-12 17:10:00.000Z" || main.Status != model.TableOccupied {
+		t.Fatalf("main %+v", main)
+	}
+	if changed[1].MergedInto != "t1" || changed[2].ID != "t3" || changed[2].MergedInto != "t1" {
+		t.Fatalf("merged %+v", changed[1:])
+	}
+
+	byID := map[string]model.Table{}
+	for _, tb := range append(tables[:0:0], changed[0], changed[1], changed[2], tables[3]) {
+		byID[tb.ID] = tb
+	}
+	if Status(byID["t3"], byID) != model.TableOccupied || Status(byID["t4"], byID) != model.TableFree {
+		t.Fatal("merged tables show the status of their main table")
+	}
+
+	for _, tt := range []struct{ target, other string }{
+		{"t1", "t1"}, {"t3", "t4"}, {"t4", "t3"}, {"t1", "nope"},
+	} {
+		if _, err := Merge(tables, tt.target, tt.other, now); err == nil {
+			t.Errorf("merge %s into %s: expected error", tt.other, tt.target)
+		}
+	}
+
+	cleared := Clear([]model.Table{byID["t1"], byID["t2"], byID["t3"], byID["t4"]}, "t1")
+	if len(cleared) != 3 {
+		t.Fatalf("cleared %+v", cleared)
+	}
+	for _, tb := range cleared {
+		if tb.Status != model.TableFree || tb.MergedInto != "" || tb.SeatedAt != "" {
+			t.Fatalf("not cleared: %+v", tb)
+		}
+	}
+}
+
+func TestReceive(t *testing.T) {
+	now := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+	moved := model.Order{CreatedAt: "2025-03-12 17:20:00.000Z"}
+
+	table := Receive(model.Table{Status: model.TableFree}, moved, now)
+	if table.Status != model.TableOccupied || table.SeatedAt != moved.CreatedAt {
+		t.Fatalf("got %+v", table)
+	}
+
+	seated := model.Table{Status: model.TableOccupied, SeatedAt: "2025-03-12 17:00:00.000Z"}
+	if got := Receive(seated, moved, now); got.SeatedAt != seated.SeatedAt {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	got, err := Split(model.Table{ID: "t3", Number: 3, MergedInto: "t2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MergedInto != "" || got.Status != model.TableFree {
+		t.Fatalf("got %+v", got)
+	}
+
+	for _, tb := range []model.Table{
+		{ID: "t1", Number: 1, Status: model.TableOccupied, SeatedAt: "2025-03-12 17:30:00.000Z"},
+		{ID: "t2", Number: 2},
+	} {
+		if _, err := Split(tb); err == nil {
+			t.Fatalf("split table %d that is not merged", tb.Number)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Package kitchen routes order lines to kitchen stations and moves the
+// resulting tickets through preparation.
+package kitchen
+
+import (
+	"slices"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Tickets older than these are shown as running late and overdue.
+const (
+	LateAfter    = 5 * time.Minute
+	OverdueAfter = 10 * time.Minute
+)
+
+// Urgency levels returned by [Urgency].
+const (
+	OnTime  = "on-time"
+	Late    = "late"
+	Overdue = "overdue"
+)
+
+// Station returns the station items of the category are prepared at.
+// Unknown and uncategorised items go to the grill.
+func Station(c model.Category) string {
+	if slices.Contains(model.Stations, c.Station) {
+		return c.Station
+	}
+	return model.StationGrill
+}
+
+// Tickets splits the lines of an order into one new ticket per round and
+// station, keyed by category ID in categories. Tickets come by round, then
+// in [model.Stations] order, and stations with nothing to prepare get no
+// ticket.
+func Tickets(o model.Order, categories map[string]model.Category) []model.Ticket {
+	type key struct {
+		round   int
+		station string
+	}
+	lines := map[key][]model.Item{}
+	var rounds []int
+	for _, l := range o.Items {
+		k := key{l.Round, Station(categories[l.Category])}
+		if !slices.Contains(rounds, l.Round) {
+			rounds = append(rounds, l.Round)
+		}
+		lines[k] = append(lines[k], l)
+	}
+	slices.Sort(rounds)
+
+	var tickets []model.Ticket
+	for _, round := range rounds {
+		for _, station := range model.Stations {
+			k := key{round, station}
+			if len(lines[k]) == 0 {
+				continue
+			}
+			tickets = append(tickets, model.Ticket{
+				Order:   o.ID,
+				Station: station,
+				Status:  model.TicketNew,
+				Lines:   lines[k],
+				Round:   round,
+			})
+		}
+	}
+	return tickets
+}
+
+// Next returns the status a ticket moves to when bumped. It returns false
+// for served tickets and unknown statuses.
+func Next(status string) (string, bool) {
+	i := slices.Index(model.TicketStatuses, status)
+	if i < 0 || i == len(model.TicketStatuses)-1 {
+		return "", false
+	}
+	return model.TicketStatuses[i+1], true
+}
+
+// Open reports whether a ticket still belongs on the kitchen screen.
+func Open(t model.Ticket) bool {
+	return t.Status != model.TicketServed
+}
+
+// Elapsed returns how long ago the ticket was created, or zero if its
+// creation time cannot be read.
+func Elapsed(t model.Ticket, now time.Time) time.Duration {
+	created, err := model.ParseTime(t.Created)
+	if err != nil || created.After(now) {
+		return 0
+	}
+	return now.Sub(created)
+}
+
+// Urgency grades the ticket's age for colouring.
+func Urgency(t model.Ticket, now time.Time) string {
+	switch d := Elapsed(t, now); {
+	case d >= OverdueAfter:
+		return Overdue
+	case d >= LateAfter:
+		return Late
+	default:
+		return OnTime
+	}
+}
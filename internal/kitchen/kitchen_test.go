@@ -0,0 +1,98 @@
+package kitchen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestTickets(t *testing.T) {
+	categories := map[string]model.Category{
+		"drinks": {ID: "drinks", Station: model.StationBar},
+		"mains":  {ID: "mains", Station: model.StationGrill},
+		"sides":  {ID: "sides"},
+	}
+	o := model.Order{ID: "o1", Items: []model.Item{
+		{Name: "soda", Category: "drinks", Quantity: 2},
+		{Name: "burger", Category: "mains", Quantity: 1},
+		{Name: "chips", Category: "sides", Quantity: 1},
+		{Name: "special", Category: "gone", Quantity: 1},
+	}}
+
+	got := Tickets(o, categories)
+	if len(got) != 2 {
+		t.Fatalf("got %d tickets", len(got))
+	}
+	if got[0].Station != model.StationGrill || len(got[0].Lines) != 3 || got[0].Order != "o1" || got[0].Status != model.TicketNew {
+		t.Fatalf("grill ticket %+v", got[0])
+	}
+	if got[1].Station != model.StationBar || len(got[1].Lines) != 1 || got[1].Lines[0].Name != "soda" {
+		t.Fatalf("bar ticket %+v", got[1])
+	}
+
+	// Drinks only go to the bar alone
+	o.Items = o.Items[:1]
+	if got := Tickets(o, categories); len(got) != 1 || got[0].Station != model.StationBar {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestTicketsByRound(t *testing.T) {
+	categories := map[string]model.Category{"drinks": {ID: "drinks", Station: model.StationBar}}
+	o := model.Order{ID: "o1", Items: []model.Item{
+		{Name: "burger", Category: "mains", Quantity: 1, Round: 2},
+		{Name: "soda", Category: "drinks", Quantity: 2, Round: 1},
+		{Name: "beer", Category: "drinks", Quantity: 1, Round: 2},
+	}}
+
+	got := Tickets(o, categories)
+	if len(got) != 3 {
+		t.Fatalf("got %d tickets", len(got))
+	}
+	if got[0].Round != 1 || got[0].Station != model.StationBar || got[0].Lines[0].Name != "soda" {
+		t.Fatalf("first ticket %+v", got[0])
+	}
+	if got[1].Round != 2 || got[1].Station != model.StationGrill || got[2].Round != 2 || got[2].Station != model.StationBar {
+		t.Fatalf("second round %+v, %+v", got[1], got[2])
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		status, want string
+		ok           bool
+	}{
+		{model.TicketNew, model.TicketPreparing, true},
+		{model.TicketPreparing, model.TicketReady, true},
+		{model.TicketReady, model.TicketServed, true},
+		{model.TicketServed, "", false},
+		{"bogus", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Next(tt.status)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Next(%q) = %q, %v", tt.status, got, ok)
+		}
+	}
+}
+
+func TestUrgency(t *testing.T) {
+	now := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+	ticket := func(ago time.Duration) model.Ticket {
+		return model.Ticket{Created: model.FormatTime(now.Add(-ago))}
+	}
+
+	if got := Urgency(ticket(2*time.Minute), now); got != OnTime {
+		t.Errorf("2m: %s", got)
+	}
+	if got := Urgency(ticket(LateAfter), now); got != Late {
+		t.Errorf("5m: %s", got)
+	}
+	if got := Urgency(ticket(15*time.Minute), now); got != Overdue {
+		t.Errorf("15m: %s", got)
+	}
+	if got := Urgency(model.Ticket{}, now); got != OnTime {
+		t.Errorf("no time: %s", got)
+	}
+}
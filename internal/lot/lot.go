@@ -0,0 +1,102 @@
+// Package lot keeps perishable stock in lots and sells the ones that expire
+// first before the rest.
+package lot
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// dateLayout is the layout of expiry dates on forms and in reports.
+const dateLayout = "2006-01-02"
+
+// Expiry returns the expiry date of a lot as YYYY-MM-DD, or "" if it has none.
+func Expiry(l model.Lot) string {
+	if len(l.Expires) < len(dateLayout) {
+		return ""
+	}
+	return l.Expires[:len(dateLayout)]
+}
+
+// Sort orders lots first-expiry-first-out: earliest expiry first, lots
+// without an expiry last, and otherwise the oldest lot first.
+func Sort(lots []model.Lot) {
+	sort.SliceStable(lots, func(i, j int) bool {
+		a, b := Expiry(lots[i]), Expiry(lots[j])
+		if a != b {
+			if a == "" || b == "" {
+				return b == ""
+			}
+			return a < b
+		}
+		return lots[i].Received < lots[j].Received
+	})
+}
+
+// Take is an amount taken from one lot.
+type Take struct {
+	Lot      string
+	Quantity int
+}
+
+// Consume picks qty units from the lots, first-expiry-first-out. If the
+// lots hold less than qty, it takes everything and the rest is stock that
+// is not tracked in lots, such as stock from before the item was perishable.
+func Consume(lots []model.Lot, qty int) []Take {
+	sorted := append([]model.Lot{}, lots...)
+	Sort(sorted)
+
+	var takes []Take
+	for _, l := range sorted {
+		if qty <= 0 {
+			break
+		}
+		if l.Quantity <= 0 {
+			continue
+		}
+		n := min(l.Quantity, qty)
+		takes = append(takes, Take{Lot: l.ID, Quantity: n})
+		qty -= n
+	}
+	return takes
+}
+
+// Expired reports whether a lot is past its expiry date on the day of now.
+// A lot can still be sold on its expiry date.
+func Expired(l model.Lot, now time.Time) bool {
+	e := Expiry(l)
+	return e != "" && e < now.Format(dateLayout)
+}
+
+// Expiring returns the lots with stock left that expire within days of now,
+// including those already expired, first-expiry-first-out.
+func Expiring(lots []model.Lot, now time.Time, days int) []model.Lot {
+	until := now.AddDate(0, 0, days).Format(dateLayout)
+
+	var res []model.Lot
+	for _, l := range lots {
+		if e := Expiry(l); l.Quantity > 0 && e != "" && e <= until {
+			res = append(res, l)
+		}
+	}
+	Sort(res)
+	return res
+}
+
+// ParseExpiry reads an expiry date from a form. Blank means the lot does not
+// expire. The date is returned in the form PocketBase stores dates in.
+func ParseExpiry(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	d, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return "", errors.New("expiry must be a date")
+	}
+	return model.FormatTime(d), nil
+}
@@ -0,0 +1,71 @@
+package lot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestConsume(t *testing.T) {
+	lots := []model.Lot{
+		{ID: "late", Quantity: 10, Expires: "2025-03-20 00:00:00.000Z", Received: "2025-03-10 08:00:00.000Z"},
+		{ID: "never", Quantity: 10, Received: "2025-03-01 08:00:00.000Z"},
+		{ID: "soon", Quantity: 3, Expires: "2025-03-14 00:00:00.000Z", Received: "2025-03-10 09:00:00.000Z"},
+		{ID: "soon-old", Quantity: 2, Expires: "2025-03-14 00:00:00.000Z", Received: "2025-03-09 09:00:00.000Z"},
+		{ID: "empty", Quantity: 0, Expires: "2025-03-12 00:00:00.000Z"},
+	}
+
+	got := Consume(lots, 8)
+	want := []Take{{"soon-old", 2}, {"soon", 3}, {"late", 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v", got)
+	}
+	if lots[0].ID != "late" {
+		t.Fatal("Consume reordered the caller's lots")
+	}
+
+	// More than the lots hold takes everything
+	got = Consume(lots, 40)
+	if len(got) != 4 || got[3] != (Take{"never", 10}) {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestExpiring(t *testing.T) {
+	now := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+	lots := []model.Lot{
+		{ID: "gone", Quantity: 1, Expires: "2025-03-10 00:00:00.000Z"},
+		{ID: "today", Quantity: 1, Expires: "2025-03-12 00:00:00.000Z"},
+		{ID: "in3", Quantity: 1, Expires: "2025-03-15 00:00:00.000Z"},
+		{ID: "in4", Quantity: 1, Expires: "2025-03-16 00:00:00.000Z"},
+		{ID: "sold", Quantity: 0, Expires: "2025-03-11 00:00:00.000Z"},
+		{ID: "never", Quantity: 1},
+	}
+
+	var ids []string
+	for _, l := range Expiring(lots, now, 3) {
+		ids = append(ids, l.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"gone", "today", "in3"}) {
+		t.Fatalf("got %v", ids)
+	}
+
+	if !Expired(lots[0], now) || Expired(lots[1], now) || Expired(lots[5], now) {
+		t.Fatal("a lot is sellable up to and including its expiry date")
+	}
+}
+
+func TestParseExpiry(t *testing.T) {
+	got, err := ParseExpiry(" 2025-03-14 ")
+	if err != nil || got != "2025-03-14 00:00:00.000Z" || Expiry(model.Lot{Expires: got}) != "2025-03-14" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if got, err := ParseExpiry(""); err != nil || got != "" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if _, err := ParseExpiry("14/03/2025"); err == nil {
+		t.Fatal("expected error")
+	}
+}
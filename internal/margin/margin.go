@@ -0,0 +1,139 @@
+// Package margin works out what orders earned over what they cost.
+package margin
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Uncategorised names the group of items without a category.
+const Uncategorised = "Uncategorised"
+
+// Row is the gross margin of one item, category or day.
+type Row struct {
+	Name     string
+	Quantity int
+	Revenue  float64
+	Cost     float64
+}
+
+// Margin is revenue less cost.
+func (r Row) Margin() float64 {
+	return r.Revenue - r.Cost
+}
+
+// Percent is the margin as a percentage of revenue, or 0 without revenue.
+func (r Row) Percent() float64 {
+	if r.Revenue == 0 {
+		return 0
+	}
+	return r.Margin() / r.Revenue * 100
+}
+
+// Report is the gross margin of a set of orders, broken down three ways.
+type Report struct {
+	Total      Row
+	ByItem     []Row
+	ByCategory []Row
+	ByDay      []Row
+}
+
+// Build totals the margin of the orders that were not cancelled or
+// refunded. Revenue is taken from the line prices with their options, before
+// order-level discounts. Cost is the cost snapshot on each line; lines sold
+// before costs were recorded fall back to the item's current cost.
+//
+// Parameters:
+//   - categories: category names by ID.
+//   - items: current items by ID, for lines without a category or cost.
+//   - loc: the time zone days are counted in.
+func Build(orders []model.Order, categories map[string]string, items map[string]model.Item, loc *time.Location) Report {
+	var r Report
+	byItem := map[string]*Row{}
+	byCategory := map[string]*Row{}
+	byDay := map[string]*Row{}
+
+	for _, o := range orders {
+		if o.Status == model.OrderStatusCancelled || o.Status == model.OrderStatusRefunded {
+			continue
+		}
+		created, err := model.ParseTime(o.CreatedAt)
+		if err != nil {
+			continue
+		}
+		day := created.In(loc).Format("2006-01-02")
+
+		for _, line := range o.Items {
+			current := items[line.ID]
+			unitCost := line.Cost
+			if unitCost == 0 {
+				unitCost = current.Cost
+			}
+			category := line.Category
+			if category == "" {
+				category = current.Category
+			}
+			categoryName := categories[category]
+			if categoryName == "" {
+				categoryName = Uncategorised
+			}
+
+			revenue := compute.LineTotal(line)
+			cost := unitCost * float64(line.Quantity)
+			for _, row := range []*Row{
+				&r.Total,
+				get(byItem, line.Name),
+				get(byCategory, categoryName),
+				get(byDay, day),
+			} {
+				row.Quantity += line.Quantity
+				row.Revenue += revenue
+				row.Cost += cost
+			}
+		}
+	}
+
+	r.ByItem = byMargin(byItem)
+	r.ByCategory = byMargin(byCategory)
+	r.ByDay = byName(byDay)
+	return r
+}
+
+func get(m map[string]*Row, name string) *Row {
+	row, ok := m[name]
+	if !ok {
+		row = &Row{Name: name}
+		m[name] = row
+	}
+	return row
+}
+
+// byMargin returns the rows largest margin first, then by name.
+func byMargin(m map[string]*Row) []Row {
+	rows := values(m)
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Margin() != rows[j].Margin() {
+			return rows[i].Margin() > rows[j].Margin()
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	return rows
+}
+
+// byName returns the rows in name order, which for days is date order.
+func byName(m map[string]*Row) []Row {
+	rows := values(m)
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	return rows
+}
+
+func values(m map[string]*Row) []Row {
+	rows := make([]Row, 0, len(m))
+	for _, row := range m {
+		rows = append(rows, *row)
+	}
+	return rows
+}
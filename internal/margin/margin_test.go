@@ -0,0 +1,51 @@
+package margin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestBuild(t *testing.T) {
+	orders := []model.Order{
+		{CreatedAt: "2025-03-11 09:00:00.000Z", Items: []model.Item{
+			{ID: "c", Name: "chai", Category: "hot", Price: 1000, Cost: 300, Quantity: 4},
+			{ID: "m", Name: "mandazi", Price: 500, Quantity: 2}, // sold before costs were recorded
+		}},
+		{CreatedAt: "2025-03-11 22:30:00.000Z", Items: []model.Item{
+			{ID: "c", Name: "chai", Category: "hot", Price: 1000, Cost: 400, Quantity: 1,
+				Modifiers: []model.Modifier{{Name: "ginger", PriceDelta: 200}}},
+		}},
+		{CreatedAt: "2025-03-12 10:00:00.000Z", Status: model.OrderStatusRefunded, Items: []model.Item{
+			{ID: "c", Name: "chai", Price: 1000, Cost: 300, Quantity: 10},
+		}},
+	}
+	categories := map[string]string{"hot": "Hot drinks", "bake": "Bakery"}
+	items := map[string]model.Item{"m": {ID: "m", Category: "bake", Cost: 150}}
+
+	// 22:30 UTC is already the next day in Dar es Salaam (UTC+3)
+	r := Build(orders, categories, items, time.FixedZone("EAT", 3*60*60))
+
+	if r.Total.Revenue != 6200 || r.Total.Cost != 1900 || r.Total.Margin() != 4300 {
+		t.Fatalf("total: %+v", r.Total)
+	}
+	if len(r.ByItem) != 2 || r.ByItem[0].Name != "chai" || r.ByItem[0].Cost != 1600 || r.ByItem[1].Cost != 300 {
+		t.Fatalf("by item: %+v", r.ByItem)
+	}
+	if len(r.ByCategory) != 2 || r.ByCategory[0].Name != "Hot drinks" || r.ByCategory[1].Name != "Bakery" {
+		t.Fatalf("by category: %+v", r.ByCategory)
+	}
+	if len(r.ByDay) != 2 || r.ByDay[0].Name != "2025-03-11" || r.ByDay[1].Name != "2025-03-12" || r.ByDay[1].Revenue != 1200 {
+		t.Fatalf("by day: %+v", r.ByDay)
+	}
+}
+
+func TestPercent(t *testing.T) {
+	if p := (Row{Revenue: 2000, Cost: 500}).Percent(); p != 75 {
+		t.Fatalf("got %v", p)
+	}
+	if p := (Row{Cost: 500}).Percent(); p != 0 {
+		t.Fatalf("got %v", p)
+	}
+}
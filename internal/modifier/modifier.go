@@ -0,0 +1,180 @@
+// Package modifier validates the options chosen for an order line against
+// the modifier groups of its item, e.g. a size and extras for a coffee.
+package modifier
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// ParseOptions reads the options of a group from the admin form, one per
+// line as "Name" or "Name, price delta", e.g. "Oat milk, 500".
+func ParseOptions(s string) ([]model.ModifierOption, error) {
+	var opts []model.ModifierOption
+	seen := map[string]bool{}
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, delta, _ := strings.Cut(line, ",")
+		opt := model.ModifierOption{Name: strings.TrimSpace(name)}
+		if opt.Name == "" {
+			return nil, fmt.Errorf("line %d: missing option name", i+1)
+		}
+		if seen[strings.ToLower(opt.Name)] {
+			return nil, fmt.Errorf("line %d: duplicate option %q", i+1, opt.Name)
+		}
+		seen[strings.ToLower(opt.Name)] = true
+
+		if delta = strings.TrimSpace(delta); delta != "" {
+			d, err := strconv.ParseFloat(strings.TrimPrefix(delta, "+"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: price must be a number", i+1)
+			}
+			opt.PriceDelta = d
+		}
+		opts = append(opts, opt)
+	}
+
+	if len(opts) == 0 {
+		return nil, errors.New("add at least one option")
+	}
+	return opts, nil
+}
+
+// FormatOptions is the inverse of ParseOptions.
+func FormatOptions(opts []model.ModifierOption) string {
+	var b strings.Builder
+	for _, o := range opts {
+		b.WriteString(o.Name)
+		if o.PriceDelta != 0 {
+			b.WriteString(", ")
+			b.WriteString(strconv.FormatFloat(o.PriceDelta, 'f', -1, 64))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ValidateGroup checks the selection limits of a group before it is saved.
+func ValidateGroup(g model.ModifierGroup) error {
+	if g.MinSelect < 0 || g.MaxSelect < 0 {
+		return errors.New("selection limits cannot be negative")
+	}
+	if g.MaxSelect > 0 && g.MinSelect > g.MaxSelect {
+		return errors.New("minimum cannot be more than maximum")
+	}
+	if g.MinSelect > len(g.Options) {
+		return errors.New("minimum is more than the number of options")
+	}
+	return nil
+}
+
+// Min returns the fewest options that must be chosen from a group.
+func Min(g model.ModifierGroup) int {
+	if g.Required && g.MinSelect < 1 {
+		return 1
+	}
+	return g.MinSelect
+}
+
+// Choose turns the option names picked per group ID into the modifiers of an
+// order line, with names and prices taken from the groups rather than from
+// the client. It fails if a pick is unknown or a group's limits are not met.
+// Modifiers are returned in group order, then option order.
+func Choose(groups []model.ModifierGroup, picks map[string][]string) ([]model.Modifier, error) {
+	known := map[string]bool{}
+	var mods []model.Modifier
+	for _, g := range groups {
+		known[g.ID] = true
+
+		chosen := map[string]bool{}
+		for _, name := range picks[g.ID] {
+			chosen[name] = true
+		}
+
+		n := 0
+		for _, o := range g.Options {
+			if !chosen[o.Name] {
+				continue
+			}
+			delete(chosen, o.Name)
+			mods = append(mods, model.Modifier{GroupID: g.ID, Group: g.Name, Name: o.Name, PriceDelta: o.PriceDelta})
+			n++
+		}
+		for name := range chosen {
+			return nil, fmt.Errorf("%s has no option %q", g.Name, name)
+		}
+
+		switch least := Min(g); {
+		case n < least && least == 1:
+			return nil, fmt.Errorf("choose a %s", g.Name)
+		case n < least:
+			return nil, fmt.Errorf("choose at least %d %s", least, g.Name)
+		case g.MaxSelect > 0 && n > g.MaxSelect:
+			return nil, fmt.Errorf("choose at most %d %s", g.MaxSelect, g.Name)
+		}
+	}
+
+	for id := range picks {
+		if !known[id] && len(picks[id]) > 0 {
+			return nil, errors.New("option group is not offered with this item")
+		}
+	}
+	return mods, nil
+}
+
+// Picks is the inverse of Choose: the option names per group ID of a line.
+func Picks(mods []model.Modifier) map[string][]string {
+	picks := map[string][]string{}
+	for _, m := range mods {
+		picks[m.GroupID] = append(picks[m.GroupID], m.Name)
+	}
+	return picks
+}
+
+// Same reports whether two lines have the same modifiers, so that adding
+// another one can increase the quantity instead of adding a line.
+func Same(a, b []model.Modifier) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].GroupID != b[i].GroupID || a[i].Name != b[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// ForItem returns the groups offered with an item, in the item's order.
+func ForItem(item model.Item, groups []model.ModifierGroup) []model.ModifierGroup {
+	byID := map[string]model.ModifierGroup{}
+	for _, g := range groups {
+		byID[g.ID] = g
+	}
+
+	var res []model.ModifierGroup
+	for _, id := range item.ModifierGroups {
+		if g, ok := byID[id]; ok {
+			res = append(res, g)
+		}
+	}
+	return res
+}
+
+// Describe formats the modifiers of a line for receipts and tickets,
+// e.g. "Large, Oat milk".
+func Describe(mods []model.Modifier) string {
+	names := make([]string, len(mods))
+	for i, m := range mods {
+		names[i] = m.Name
+	}
+	return strings.Join(names, ", ")
+}
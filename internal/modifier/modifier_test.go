@@ -0,0 +1,105 @@
+package modifier
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+var (
+	size = model.ModifierGroup{ID: "size", Name: "Size", Required: true, MaxSelect: 1, Options: []model.ModifierOption{
+		{Name: "Small", PriceDelta: -500}, {Name: "Medium"}, {Name: "Large", PriceDelta: 1000},
+	}}
+	extras = model.ModifierGroup{ID: "extras", Name: "Extras", MaxSelect: 2, Options: []model.ModifierOption{
+		{Name: "Extra shot", PriceDelta: 800}, {Name: "Oat milk", PriceDelta: 500}, {Name: "Syrup", PriceDelta: 300},
+	}}
+)
+
+func TestParseOptions(t *testing.T) {
+	got, err := ParseOptions("Small, -500\n\n Medium \nLarge, +1000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, size.Options) {
+		t.Fatalf("got %+v", got)
+	}
+	if again, _ := ParseOptions(FormatOptions(got)); !reflect.DeepEqual(again, got) {
+		t.Fatalf("round trip: got %+v", again)
+	}
+
+	for _, in := range []string{"", "Small\nsmall", ", 500", "Large, lots"} {
+		if _, err := ParseOptions(in); err == nil {
+			t.Fatalf("ParseOptions(%q): expected error", in)
+		}
+	}
+}
+
+func TestValidateGroup(t *testing.T) {
+	if err := ValidateGroup(size); err != nil {
+		t.Fatal(err)
+	}
+	bad := []model.ModifierGroup{
+		{MinSelect: -1, Options: size.Options},
+		{MinSelect: 2, MaxSelect: 1, Options: size.Options},
+		{MinSelect: 4, Options: size.Options},
+	}
+	for _, g := range bad {
+		if err := ValidateGroup(g); err == nil {
+			t.Fatalf("%+v: expected error", g)
+		}
+	}
+}
+
+func TestChoose(t *testing.T) {
+	groups := []model.ModifierGroup{size, extras}
+
+	got, err := Choose(groups, map[string][]string{"extras": {"Oat milk", "Extra shot"}, "size": {"Large"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.Modifier{
+		{GroupID: "size", Group: "Size", Name: "Large", PriceDelta: 1000},
+		{GroupID: "extras", Group: "Extras", Name: "Extra shot", PriceDelta: 800},
+		{GroupID: "extras", Group: "Extras", Name: "Oat milk", PriceDelta: 500},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v", got)
+	}
+	if again, err := Choose(groups, Picks(got)); err != nil || !reflect.DeepEqual(again, got) {
+		t.Fatalf("round trip: got %+v, %v", again, err)
+	}
+
+	cases := []struct {
+		name  string
+		picks map[string][]string
+	}{
+		{"required group missing", map[string][]string{"extras": {"Syrup"}}},
+		{"too many", map[string][]string{"size": {"Small"}, "extras": {"Syrup", "Oat milk", "Extra shot"}}},
+		{"unknown option", map[string][]string{"size": {"Huge"}}},
+		{"group not offered", map[string][]string{"size": {"Small"}, "milk": {"Soy"}}},
+	}
+	for _, c := range cases {
+		if _, err := Choose(groups, c.picks); err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+	}
+}
+
+func TestForItem(t *testing.T) {
+	item := model.Item{ModifierGroups: []string{"extras", "gone", "size"}}
+	got := ForItem(item, []model.ModifierGroup{size, extras})
+	if len(got) != 2 || got[0].ID != "extras" || got[1].ID != "size" {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestSame(t *testing.T) {
+	a := []model.Modifier{{GroupID: "size", Name: "Large"}}
+	if !Same(a, []model.Modifier{{GroupID: "size", Name: "Large", PriceDelta: 1}}) {
+		t.Fatal("expected same")
+	}
+	if Same(a, nil) || Same(a, []model.Modifier{{GroupID: "size", Name: "Small"}}) {
+		t.Fatal("expected different")
+	}
+}
@@ -0,0 +1,188 @@
+// Package ordertype handles the ways an order is served: dine-in at a
+// table, takeaway collected at the counter, and delivery to an address.
+// Each type asks for its own details, moves through its own status steps
+// and may be priced differently.
+package ordertype
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Counter stands for orders without a type when filtering.
+const Counter = "counter"
+
+// Types lists the order types, in the order they are offered.
+var Types = []string{model.OrderDineIn, model.OrderTakeaway, model.OrderDelivery}
+
+// Valid reports whether t is an order type or empty, for a counter sale.
+func Valid(t string) bool {
+	return t == "" || slices.Contains(Types, t)
+}
+
+// Label names an order type for staff, e.g. "Dine-in".
+func Label(t string) string {
+	switch t {
+	case model.OrderDineIn:
+		return "Dine-in"
+	case model.OrderTakeaway:
+		return "Takeaway"
+	case model.OrderDelivery:
+		return "Delivery"
+	default:
+		return "Counter"
+	}
+}
+
+// Validate checks that an order has the details its type needs: a table
+// for dine-in, a name and pickup time for takeaway, and an address and
+// phone number for delivery.
+func Validate(o model.Order) error {
+	switch o.Type {
+	case "":
+		return nil
+	case model.OrderDineIn:
+		if o.Table == "" {
+			return errors.New("dine-in orders need a table")
+		}
+	case model.OrderTakeaway:
+		if strings.TrimSpace(o.Customer) == "" {
+			return errors.New("takeaway orders need the customer's name")
+		}
+		if o.PickupAt == "" {
+			return errors.New("takeaway orders need a pickup time")
+		}
+	case model.OrderDelivery:
+		if strings.TrimSpace(o.Address) == "" {
+			return errors.New("delivery orders need an address")
+		}
+		if strings.TrimSpace(o.Phone) == "" {
+			return errors.New("delivery orders need a phone number")
+		}
+		if o.DeliveryFee < 0 {
+			return errors.New("the delivery fee cannot be negative")
+		}
+	default:
+		return errors.New("unknown order type " + strconv.Quote(o.Type))
+	}
+	return nil
+}
+
+// Steps returns the statuses an order of type t moves through, from
+// pending to completed.
+func Steps(t string) []string {
+	switch t {
+	case model.OrderDineIn:
+		return []string{model.OrderStatusPending, model.OrderStatusPreparing, model.OrderStatusServed, model.OrderStatusCompleted}
+	case model.OrderTakeaway:
+		return []string{model.OrderStatusPending, model.OrderStatusPreparing, model.OrderStatusReady, model.OrderStatusCompleted}
+	case model.OrderDelivery:
+		return []string{model.OrderStatusPending, model.OrderStatusPreparing, model.OrderStatusReady, model.OrderStatusOutForDelivery, model.OrderStatusCompleted}
+	default:
+		return []string{model.OrderStatusPending, model.OrderStatusCompleted}
+	}
+}
+
+// Statuses returns the statuses an order can be moved to: the steps of
+// its type, then cancelled and refunded. A status off those is kept first
+// so the order can stay where it is. Open tabs are paid when they are
+// closed, so they can only stay open or be cancelled.
+func Statuses(o model.Order) []string {
+	if o.Status == model.OrderStatusOpen {
+		return []string{model.OrderStatusOpen, model.OrderStatusCancelled}
+	}
+	statuses := append(Steps(o.Type), model.OrderStatusCancelled, model.OrderStatusRefunded)
+	if o.Status != "" && !slices.Contains(statuses, o.Status) {
+		statuses = append([]string{o.Status}, statuses...)
+	}
+	return statuses
+}
+
+// Next returns the step after the order's current status, and false at
+// the last step or off the steps of its type.
+func Next(o model.Order) (string, bool) {
+	steps := Steps(o.Type)
+	i := slices.Index(steps, o.Status)
+	if i < 0 || i == len(steps)-1 {
+		return "", false
+	}
+	return steps[i+1], true
+}
+
+// StepLabel names a status as staff see it for an order of type t, e.g.
+// "Delivered" for a completed delivery.
+func StepLabel(t, status string) string {
+	switch status {
+	case model.OrderStatusOutForDelivery:
+		return "Out for delivery"
+	case model.OrderStatusCompleted:
+		switch t {
+		case model.OrderTakeaway:
+			return "Collected"
+		case model.OrderDelivery:
+			return "Delivered"
+		}
+	}
+	if status == "" {
+		return ""
+	}
+	return strings.ToUpper(status[:1]) + status[1:]
+}
+
+// Price returns what an item sells for in an order of type t: its price
+// for the type if it has one, else its normal price.
+func Price(it model.Item, t string) float64 {
+	if p, ok := it.TypePrices[t]; ok && p > 0 {
+		return p
+	}
+	return it.Price
+}
+
+// ParsePrices parses the per-type prices of an item, keyed by type. Empty
+// values leave the normal price in place; it returns nil if all are empty.
+func ParsePrices(values map[string]string) (map[string]float64, error) {
+	var prices map[string]float64
+	for _, t := range Types {
+		s := strings.TrimSpace(values[t])
+		if s == "" {
+			continue
+		}
+		p, err := strconv.ParseFloat(s, 64)
+		if err != nil || p <= 0 {
+			return nil, errors.New(Label(t) + " price must be a number > 0")
+		}
+		if prices == nil {
+			prices = map[string]float64{}
+		}
+		prices[t] = p
+	}
+	return prices, nil
+}
+
+// ParsePickup parses a pickup time of day ("15:04") as the next time it
+// comes round after now: a time already past today is for tomorrow.
+func ParsePickup(s string, now time.Time) (time.Time, error) {
+	clock, err := time.ParseInLocation("15:04", strings.TrimSpace(s), now.Location())
+	if err != nil {
+		return time.Time{}, errors.New("pickup time must look like 18:30")
+	}
+	at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if at.Before(now.Add(-time.Minute)) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
+
+// ParseFee parses a delivery fee. It may be 0, but not empty.
+func ParseFee(s string) (float64, error) {
+	fee, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || fee < 0 {
+		return 0, errors.New("delivery fee must be a number ≥ 0")
+	}
+	return fee, nil
+}
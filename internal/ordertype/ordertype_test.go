@@ -0,0 +1,132 @@
+package ordertype
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		o    model.Order
+		ok   bool
+	}{
+		{"counter", model.Order{}, true},
+		{"dine-in", model.Order{Type: model.OrderDineIn, Table: "t1"}, true},
+		{"dine-in without table", model.Order{Type: model.OrderDineIn}, false},
+		{"takeaway", model.Order{Type: model.OrderTakeaway, Customer: "Amina", PickupAt: "2026-01-01 12:00:00.000Z"}, true},
+		{"takeaway without name", model.Order{Type: model.OrderTakeaway, Customer: " ", PickupAt: "2026-01-01 12:00:00.000Z"}, false},
+		{"takeaway without pickup", model.Order{Type: model.OrderTakeaway, Customer: "Amina"}, false},
+		{"delivery", model.Order{Type: model.OrderDelivery, Address: "Msasani", Phone: "0712"}, true},
+		{"delivery without phone", model.Order{Type: model.OrderDelivery, Address: "Msasani"}, false},
+		{"delivery with negative fee", model.Order{Type: model.OrderDelivery, Address: "Msasani", Phone: "0712", DeliveryFee: -1}, false},
+		{"unknown", model.Order{Type: "drive_through"}, false},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.o); (err == nil) != tt.ok {
+			t.Errorf("%s: got %v", tt.name, err)
+		}
+	}
+}
+
+func TestSteps(t *testing.T) {
+	o := model.Order{Type: model.OrderDelivery, Status: model.OrderStatusPending}
+	var seen []string
+	for {
+		seen = append(seen, o.Status)
+		next, ok := Next(o)
+		if !ok {
+			break
+		}
+		o.Status = next
+	}
+	want := []string{"pending", "preparing", "ready", "out_for_delivery", "completed"}
+	if !slices.Equal(seen, want) {
+		t.Fatalf("got %v, want %v", seen, want)
+	}
+
+	if _, ok := Next(model.Order{Status: model.OrderStatusCancelled}); ok {
+		t.Fatal("a cancelled order has a next step")
+	}
+	if s := Statuses(model.Order{Status: model.OrderStatusOpen}); len(s) != 2 || s[0] != model.OrderStatusOpen || s[1] != model.OrderStatusCancelled {
+		t.Fatalf("open tab statuses %v", s)
+	}
+	if s := Statuses(model.Order{Type: model.OrderDineIn}); slices.Contains(s, model.OrderStatusReady) || !slices.Contains(s, model.OrderStatusServed) {
+		t.Fatalf("dine-in statuses %v", s)
+	}
+
+	if l := StepLabel(model.OrderTakeaway, model.OrderStatusCompleted); l != "Collected" {
+		t.Fatalf("takeaway completed is %q", l)
+	}
+	if l := StepLabel("", model.OrderStatusCompleted); l != "Completed" {
+		t.Fatalf("counter completed is %q", l)
+	}
+}
+
+func TestPrice(t *testing.T) {
+	it := model.Item{Price: 5000, TypePrices: map[string]float64{model.OrderDelivery: 5500}}
+	if p := Price(it, model.OrderDelivery); p != 5500 {
+		t.Fatalf("delivery price %v", p)
+	}
+	if p := Price(it, model.OrderTakeaway); p != 5000 {
+		t.Fatalf("takeaway price %v", p)
+	}
+
+	prices, err := ParsePrices(map[string]string{model.OrderTakeaway: " 4500 ", model.OrderDelivery: ""})
+	if err != nil || len(prices) != 1 || prices[model.OrderTakeaway] != 4500 {
+		t.Fatalf("got %v, %v", prices, err)
+	}
+	if prices, err := ParsePrices(map[string]string{}); err != nil || prices != nil {
+		t.Fatalf("got %v, %v", prices, err)
+	}
+	if _, err := ParsePrices(map[string]string{model.OrderDineIn: "-1"}); err == nil {
+		t.Fatal("accepted a negative price")
+	}
+}
+
+// Items are priced as PocketBase returns them, so the per-type prices must
+// survive decoding a record into the model.
+func TestPriceOfStoredItem(t *testing.T) {
+	record := `{"id":"abc","name":"chips mayai","price":5000,"quantity":10,"type_prices":{"delivery":5500}}`
+	var it model.Item
+	if err := json.Unmarshal([]byte(record), &it); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lines are priced for their order as the POS places them
+	line := model.Item{ID: it.ID, Price: Price(it, model.OrderDelivery), Quantity: 2}
+	if total := compute.Subtotal([]model.Item{line}); total != 11000 {
+		t.Fatalf("delivery order of 2 charged %v, want 11000", total)
+	}
+	if p := Price(it, model.OrderDineIn); p != 5000 {
+		t.Fatalf("dine-in price %v", p)
+	}
+}
+
+func TestParsePickup(t *testing.T) {
+	now := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+
+	at, err := ParsePickup("18:30", now)
+	if err != nil || !at.Equal(time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC)) {
+		t.Fatalf("got %v, %v", at, err)
+	}
+	at, err = ParsePickup("09:15", now)
+	if err != nil || !at.Equal(time.Date(2026, 3, 11, 9, 15, 0, 0, time.UTC)) {
+		t.Fatalf("got %v, %v", at, err)
+	}
+	if _, err := ParsePickup("soon", now); err == nil {
+		t.Fatal("accepted an invalid time")
+	}
+
+	if fee, err := ParseFee("0"); err != nil || fee != 0 {
+		t.Fatalf("got %v, %v", fee, err)
+	}
+	if _, err := ParseFee(""); err == nil {
+		t.Fatal("accepted an empty fee")
+	}
+}
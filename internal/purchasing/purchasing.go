@@ -0,0 +1,171 @@
+// Package purchasing handles purchase orders: their lines, the status
+// workflow and receiving goods against them.
+package purchasing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/internal/catalog"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Outstanding is how much of a line is still to be delivered.
+func Outstanding(l model.PurchaseLine) int {
+	return max(l.Quantity-l.Received, 0)
+}
+
+// Total is the value of a purchase order at its unit costs.
+func Total(po model.PurchaseOrder) float64 {
+	var total float64
+	for _, l := range po.Lines {
+		total += float64(l.Quantity) * l.Cost
+	}
+	return total
+}
+
+// ReceivedValue is the value of what has been delivered so far.
+func ReceivedValue(po model.PurchaseOrder) float64 {
+	var total float64
+	for _, l := range po.Lines {
+		total += float64(l.Received) * l.Cost
+	}
+	return total
+}
+
+// CanSend reports whether a purchase order can be sent to its supplier.
+func CanSend(po model.PurchaseOrder) bool {
+	return po.Status == model.PurchaseDraft && len(po.Lines) > 0 && po.Supplier != ""
+}
+
+// CanReceive reports whether goods can be received against a purchase order.
+func CanReceive(po model.PurchaseOrder) bool {
+	return po.Status == model.PurchaseSent || po.Status == model.PurchasePartial
+}
+
+// CanCancel reports whether a purchase order can be cancelled. A partly
+// received order can be cancelled to stop waiting for the rest.
+func CanCancel(po model.PurchaseOrder) bool {
+	return po.Status == model.PurchaseDraft || CanReceive(po)
+}
+
+// Receive books a delivery against a purchase order: amounts is the
+// quantity delivered per item ID. It returns the order with its received
+// quantities and status updated. Deliveries larger than what is
+// outstanding are refused.
+func Receive(po model.PurchaseOrder, amounts map[string]int) (model.PurchaseOrder, error) {
+	if !CanReceive(po) {
+		return po, fmt.Errorf("cannot receive goods on a %s purchase order", strings.ReplaceAll(po.Status, "_", " "))
+	}
+
+	lines := make([]model.PurchaseLine, len(po.Lines))
+	copy(lines, po.Lines)
+
+	received := false
+	for i, l := range lines {
+		n := amounts[l.Item]
+		if n == 0 {
+			continue
+		}
+		if n < 0 {
+			return po, errors.New("received quantities must be ≥ 0")
+		}
+		if n > Outstanding(l) {
+			return po, fmt.Errorf("only %d of '%s' are outstanding", Outstanding(l), l.Name)
+		}
+		lines[i].Received += n
+		received = true
+	}
+	if !received {
+		return po, errors.New("nothing was received")
+	}
+
+	po.Lines = lines
+	po.Status = model.PurchaseReceived
+	for _, l := range lines {
+		if Outstanding(l) > 0 {
+			po.Status = model.PurchasePartial
+			break
+		}
+	}
+	return po, nil
+}
+
+// AverageCost is the weighted-average unit cost after receiving goods:
+// the stock on hand at its current cost blended with the delivery at its
+// purchase cost. Negative stock counts as none, so an oversold item takes
+// the cost of the delivery.
+func AverageCost(onHand int, cost float64, received int, unitCost float64) float64 {
+	onHand = max(onHand, 0)
+	if onHand+received <= 0 {
+		return cost
+	}
+	total := float64(onHand)*cost + float64(received)*unitCost
+	return math.Round(total/float64(onHand+received)*100) / 100
+}
+
+// ParseLines reads the line rows of the purchase order form. Rows without
+// an item are skipped; an item may appear only once. Names and costs
+// missing from the form are taken from items.
+func ParseLines(itemIDs, quantities, costs []string, items map[string]model.Item) ([]model.PurchaseLine, error) {
+	var lines []model.PurchaseLine
+	seen := map[string]bool{}
+	for i, id := range itemIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		it, ok := items[id]
+		if !ok {
+			return nil, errors.New("unknown item")
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("'%s' is listed twice", it.Name)
+		}
+		seen[id] = true
+
+		qty, err := strconv.Atoi(strings.TrimSpace(at(quantities, i)))
+		if err != nil || qty <= 0 {
+			return nil, fmt.Errorf("quantity of '%s' must be a whole number > 0", it.Name)
+		}
+
+		cost := it.Cost
+		if raw := strings.TrimSpace(at(costs, i)); raw != "" {
+			cost, err = catalog.ParseCost(raw)
+			if err != nil {
+				return nil, fmt.Errorf("cost of '%s' must be a number ≥ 0", it.Name)
+			}
+		}
+
+		lines = append(lines, model.PurchaseLine{Item: id, Name: it.Name, Quantity: qty, Cost: cost})
+	}
+	return lines, nil
+}
+
+// ParseAmounts reads the receiving form: a delivered quantity per item ID.
+// Blank fields mean nothing was delivered.
+func ParseAmounts(fields map[string]string) (map[string]int, error) {
+	amounts := map[string]int{}
+	for id, raw := range fields {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return nil, errors.New("received quantities must be whole numbers ≥ 0")
+		}
+		amounts[id] = n
+	}
+	return amounts, nil
+}
+
+func at(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package purchasing
+
+import (
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestReceive(t *testing.T) {
+	po := model.PurchaseOrder{
+		Status: model.PurchaseSent,
+		Lines: []model.PurchaseLine{
+			{Item: "s", Name: "soda", Quantity: 24, Cost: 500},
+			{Item: "w", Name: "water", Quantity: 12, Cost: 300},
+		},
+	}
+
+	partial, err := Receive(po, map[string]int{"s": 24, "w": 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partial.Status != model.PurchasePartial || partial.Lines[1].Received != 6 {
+		t.Fatalf("got %+v", partial)
+	}
+	if po.Lines[1].Received != 0 {
+		t.Fatal("Receive changed the original lines")
+	}
+	if ReceivedValue(partial) != 24*500+6*300 || Total(partial) != 24*500+12*300 {
+		t.Fatalf("values: %v of %v", ReceivedValue(partial), Total(partial))
+	}
+
+	if _, err := Receive(partial, map[string]int{"w": 7}); err == nil {
+		t.Fatal("expected error for over-delivery")
+	}
+	if _, err := Receive(partial, map[string]int{}); err == nil {
+		t.Fatal("expected error for empty delivery")
+	}
+
+	done, err := Receive(partial, map[string]int{"w": 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Status != model.PurchaseReceived || CanReceive(done) || CanCancel(done) {
+		t.Fatalf("got %+v", done)
+	}
+
+	if _, err := Receive(model.PurchaseOrder{Status: model.PurchaseDraft, Lines: po.Lines}, map[string]int{"s": 1}); err == nil {
+		t.Fatal("expected error for a draft")
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	items := map[string]model.Item{
+		"s": {ID: "s", Name: "soda", Cost: 500},
+		"w": {ID: "w", Name: "water", Cost: 300},
+	}
+
+	got, err := ParseLines([]string{"s", "", "w"}, []string{"24", "", "12"}, []string{"", "", "280"}, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Cost != 500 || got[0].Name != "soda" || got[1].Cost != 280 || got[1].Quantity != 12 {
+		t.Fatalf("got %+v", got)
+	}
+
+	bad := [][3][]string{
+		{{"s", "s"}, {"1", "2"}, {}},
+		{{"s"}, {"0"}, {}},
+		{{"s"}, {"2"}, {"-1"}},
+		{{"x"}, {"2"}, {}},
+	}
+	for _, b := range bad {
+		if _, err := ParseLines(b[0], b[1], b[2], items); err == nil {
+			t.Errorf("ParseLines(%v): expected error", b)
+		}
+	}
+}
+
+func TestCanSend(t *testing.T) {
+	po := model.PurchaseOrder{Status: model.PurchaseDraft, Supplier: "d"}
+	if CanSend(po) {
+		t.Fatal("an empty order cannot be sent")
+	}
+	po.Lines = []model.PurchaseLine{{Item: "s", Quantity: 1}}
+	if !CanSend(po) || !CanCancel(po) || CanReceive(po) {
+		t.Fatalf("draft: %+v", po)
+	}
+}
+
+func TestAverageCost(t *testing.T) {
+	tests := []struct {
+		onHand   int
+		cost     float64
+		received int
+		unitCost float64
+		want     float64
+	}{
+		{10, 500, 30, 600, 575},
+		{0, 500, 12, 450, 450},
+		{-4, 500, 12, 450, 450},
+		{3, 100, 0, 900, 100},
+		{2, 1000, 1, 0, 666.67},
+	}
+	for _, tt := range tests {
+		if got := AverageCost(tt.onHand, tt.cost, tt.received, tt.unitCost); got != tt.want {
+			t.Errorf("AverageCost(%d, %v, %d, %v) = %v, want %v", tt.onHand, tt.cost, tt.received, tt.unitCost, got, tt.want)
+		}
+	}
+}
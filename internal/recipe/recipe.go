@@ -0,0 +1,134 @@
+// Package recipe works out ingredient stock for made-to-order items: how
+// many portions the ingredients allow, and what an order uses up.
+package recipe
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// epsilon absorbs float rounding, so 3 × 0.1 l of 0.3 l stock is 3 portions.
+const epsilon = 1e-9
+
+// Portions returns how many portions of a recipe the ingredient stock
+// allows. A missing ingredient allows none.
+func Portions(recipe []model.RecipeLine, stock map[string]model.Ingredient) int {
+	portions := math.MaxInt
+	for _, line := range recipe {
+		ing, ok := stock[line.Ingredient]
+		if !ok || line.Quantity <= 0 {
+			return 0
+		}
+		n := int(math.Floor(ing.Quantity/line.Quantity + epsilon))
+		portions = min(portions, max(n, 0))
+	}
+	if portions == math.MaxInt {
+		return 0
+	}
+	return portions
+}
+
+// Apply sets the Quantity of every item with a recipe to the number of
+// portions its ingredients allow, so that stock checks and sold-out tiles
+// treat both kinds of items alike. The items are modified in place.
+func Apply(items []model.Item, ingredients []model.Ingredient) {
+	stock := ByID(ingredients)
+	for i := range items {
+		if len(items[i].Recipe) > 0 {
+			items[i].Quantity = Portions(items[i].Recipe, stock)
+		}
+	}
+}
+
+// Usage sums the ingredients used by an order, given the quantity wanted
+// of each item ID. Items without a recipe use no ingredients.
+func Usage(items map[string]model.Item, wanted map[string]int) map[string]float64 {
+	used := map[string]float64{}
+	for id, qty := range wanted {
+		for _, line := range items[id].Recipe {
+			used[line.Ingredient] += line.Quantity * float64(qty)
+		}
+	}
+	return used
+}
+
+// Shortages returns the names of the ingredients there is not enough of,
+// sorted by name.
+func Shortages(used map[string]float64, ingredients []model.Ingredient) []string {
+	stock := ByID(ingredients)
+	var short []string
+	for id, qty := range used {
+		ing, ok := stock[id]
+		if !ok {
+			short = append(short, id)
+			continue
+		}
+		if qty > ing.Quantity+epsilon {
+			short = append(short, ing.Name)
+		}
+	}
+	sort.Strings(short)
+	return short
+}
+
+// ByID indexes ingredients by ID.
+func ByID(ingredients []model.Ingredient) map[string]model.Ingredient {
+	m := make(map[string]model.Ingredient, len(ingredients))
+	for _, ing := range ingredients {
+		m[ing.ID] = ing
+	}
+	return m
+}
+
+// ParseLines reads the recipe rows of the item form: parallel lists of
+// ingredient IDs and quantities. Rows without an ingredient are skipped.
+func ParseLines(ingredients, quantities []string) ([]model.RecipeLine, error) {
+	var lines []model.RecipeLine
+	seen := map[string]bool{}
+	for i, id := range ingredients {
+		if id == "" {
+			continue
+		}
+		if seen[id] {
+			return nil, errors.New("each ingredient can only be used once")
+		}
+		seen[id] = true
+
+		var raw string
+		if i < len(quantities) {
+			raw = quantities[i]
+		}
+		qty, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || qty <= 0 {
+			return nil, fmt.Errorf("row %d: quantity must be a positive number", i+1)
+		}
+		lines = append(lines, model.RecipeLine{Ingredient: id, Quantity: qty})
+	}
+	return lines, nil
+}
+
+// ParseQuantity parses an ingredient stock level, which may have decimals
+// but cannot be negative. An empty value means 0.
+func ParseQuantity(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	qty, err := strconv.ParseFloat(s, 64)
+	if err != nil || qty < 0 {
+		return 0, errors.New("quantity must be a number ≥ 0")
+	}
+	return qty, nil
+}
+
+// ValidUnit reports whether u is one of [model.Units].
+func ValidUnit(u string) bool {
+	return slices.Contains(model.Units, u)
+}
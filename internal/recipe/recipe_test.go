@@ -0,0 +1,99 @@
+package recipe
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+var ingredients = []model.Ingredient{
+	{ID: "bun", Name: "Bun", Unit: model.UnitPiece, Quantity: 10},
+	{ID: "beef", Name: "Beef", Unit: model.UnitGram, Quantity: 450},
+	{ID: "milk", Name: "Milk", Unit: model.UnitMl, Quantity: 0.3},
+}
+
+var burger = []model.RecipeLine{{Ingredient: "bun", Quantity: 1}, {Ingredient: "beef", Quantity: 150}}
+
+func TestPortions(t *testing.T) {
+	stock := ByID(ingredients)
+	cases := []struct {
+		name   string
+		recipe []model.RecipeLine
+		want   int
+	}{
+		{"limited by scarcest ingredient", burger, 3},
+		{"float rounding", []model.RecipeLine{{Ingredient: "milk", Quantity: 0.1}}, 3},
+		{"missing ingredient", []model.RecipeLine{{Ingredient: "cheese", Quantity: 1}}, 0},
+		{"no recipe", nil, 0},
+	}
+	for _, c := range cases {
+		if got := Portions(c.recipe, stock); got != c.want {
+			t.Fatalf("%s: got %d want %d", c.name, got, c.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	items := []model.Item{{ID: "burger", Quantity: 99, Recipe: burger}, {ID: "soda", Quantity: 7}}
+	Apply(items, ingredients)
+	if items[0].Quantity != 3 || items[1].Quantity != 7 {
+		t.Fatalf("got %+v", items)
+	}
+}
+
+func TestUsageAndShortages(t *testing.T) {
+	items := map[string]model.Item{
+		"burger": {ID: "burger", Recipe: burger},
+		"double": {ID: "double", Recipe: []model.RecipeLine{{Ingredient: "bun", Quantity: 1}, {Ingredient: "beef", Quantity: 300}}},
+		"soda":   {ID: "soda"},
+	}
+
+	used := Usage(items, map[string]int{"burger": 2, "soda": 5})
+	if !reflect.DeepEqual(used, map[string]float64{"bun": 2, "beef": 300}) {
+		t.Fatalf("got %v", used)
+	}
+	if short := Shortages(used, ingredients); len(short) != 0 {
+		t.Fatalf("unexpected shortages %v", short)
+	}
+
+	// Each fits on its own, but together they need 600 g of beef
+	used = Usage(items, map[string]int{"burger": 2, "double": 1, "soda": 1})
+	if short := Shortages(used, ingredients); !reflect.DeepEqual(short, []string{"Beef"}) {
+		t.Fatalf("got %v", short)
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	got, err := ParseLines([]string{"bun", "", "beef"}, []string{"1", "", " 150 "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, burger) {
+		t.Fatalf("got %+v", got)
+	}
+
+	bad := [][2][]string{
+		{{"bun", "bun"}, {"1", "2"}},
+		{{"bun"}, {"0"}},
+		{{"bun"}, {"lots"}},
+		{{"bun"}, {}},
+	}
+	for _, b := range bad {
+		if _, err := ParseLines(b[0], b[1]); err == nil {
+			t.Fatalf("ParseLines(%v, %v): expected error", b[0], b[1])
+		}
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	if q, err := ParseQuantity("2.5"); err != nil || q != 2.5 {
+		t.Fatalf("got %v, %v", q, err)
+	}
+	if q, err := ParseQuantity(""); err != nil || q != 0 {
+		t.Fatalf("got %v, %v", q, err)
+	}
+	if _, err := ParseQuantity("-1"); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,118 @@
+package reorder
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Alerts holds the items found due for reordering by the last check.
+// It is safe for concurrent use.
+type Alerts struct {
+	mu      sync.RWMutex
+	items   []model.Item
+	checked time.Time
+}
+
+// Items returns the items due for reordering, lowest stock first.
+func (a *Alerts) Items() []model.Item {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.items
+}
+
+// Checked returns when stock was last checked, or the zero time.
+func (a *Alerts) Checked() time.Time {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.checked
+}
+
+// set replaces the due items and returns those that were not due before.
+func (a *Alerts) set(due []model.Item, now time.Time) []model.Item {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	before := map[string]bool{}
+	for _, it := range a.items {
+		before[it.ID] = true
+	}
+	var raised []model.Item
+	for _, it := range due {
+		if !before[it.ID] {
+			raised = append(raised, it)
+		}
+	}
+
+	a.items = due
+	a.checked = now
+	return raised
+}
+
+// CheckerOptions configure [Checker].
+type CheckerOptions struct {
+	Log *slog.Logger
+	// Fetch returns the current items.
+	Fetch func(ctx context.Context) ([]model.Item, error)
+	// Interval between checks; defaults to one minute.
+	Interval time.Duration
+}
+
+// Checker periodically checks stock levels and raises an alert for each
+// item that drops to its reorder point.
+type Checker struct {
+	log      *slog.Logger
+	fetch    func(ctx context.Context) ([]model.Item, error)
+	interval time.Duration
+	alerts   *Alerts
+}
+
+// NewChecker returns a Checker that keeps alerts up to date.
+func NewChecker(alerts *Alerts, opts CheckerOptions) *Checker {
+	if opts.Log == nil {
+		opts.Log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	return &Checker{log: opts.Log, fetch: opts.Fetch, interval: opts.Interval, alerts: alerts}
+}
+
+// Run checks stock right away and then every interval until ctx is done.
+// Failed checks are logged and keep the previous alerts.
+func (c *Checker) Run(ctx context.Context) error {
+	c.log.Info("Starting reorder checker", "interval", c.interval)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Check(ctx); err != nil {
+			c.log.Error("Error checking stock levels", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			c.log.Info("Stopped reorder checker")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches the items once and updates the alerts.
+func (c *Checker) Check(ctx context.Context) error {
+	items, err := c.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, it := range c.alerts.set(DueItems(items), time.Now()) {
+		c.log.Warn("Item is due for reordering", "item", it.Name, "quantity", it.Quantity, "reorder_point", it.ReorderPoint)
+	}
+	return nil
+}
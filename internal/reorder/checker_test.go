@@ -0,0 +1,53 @@
+package reorder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestCheckerCheck(t *testing.T) {
+	items := []model.Item{
+		{ID: "1", Name: "soda", Quantity: 2, ReorderPoint: 6},
+		{ID: "2", Name: "water", Quantity: 10, ReorderPoint: 6},
+	}
+	var fail bool
+	alerts := &Alerts{}
+	c := NewChecker(alerts, CheckerOptions{
+		Fetch: func(context.Context) ([]model.Item, error) {
+			if fail {
+				return nil, errors.New("offline")
+			}
+			return items, nil
+		},
+	})
+
+	if err := c.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := alerts.Items(); len(got) != 1 || got[0].Name != "soda" || alerts.Checked().IsZero() {
+		t.Fatalf("got %+v", got)
+	}
+
+	// A failed check keeps the previous alerts
+	fail = true
+	if err := c.Check(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(alerts.Items()) != 1 {
+		t.Fatalf("alerts were cleared: %+v", alerts.Items())
+	}
+
+	// Restocked items clear their alert
+	fail = false
+	items[0].Quantity = 24
+	items[1].Quantity = 1
+	if err := c.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := alerts.Items(); len(got) != 1 || got[0].Name != "water" {
+		t.Fatalf("got %+v", got)
+	}
+}
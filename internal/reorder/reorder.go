@@ -0,0 +1,128 @@
+// Package reorder finds items that have run low and works out what to
+// reorder from each supplier.
+package reorder
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Due reports whether an item's stock is at or below its reorder point.
+// Items without a reorder point, and recipe items, which have no stock of
+// their own, are never due.
+func Due(it model.Item) bool {
+	return it.ReorderPoint > 0 && len(it.Recipe) == 0 && it.Quantity <= it.ReorderPoint
+}
+
+// DueItems returns the items that are due for reordering, lowest stock first.
+func DueItems(items []model.Item) []model.Item {
+	var due []model.Item
+	for _, it := range items {
+		if Due(it) {
+			due = append(due, it)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].Quantity < due[j].Quantity })
+	return due
+}
+
+// Amount is how much of an item to order to bring it back to its par level.
+// Without a par level, it orders back up to one above the reorder point.
+func Amount(it model.Item) int {
+	target := it.ParLevel
+	if target <= it.ReorderPoint {
+		target = it.ReorderPoint + 1
+	}
+	if it.Quantity < 0 {
+		return target
+	}
+	return max(target-it.Quantity, 0)
+}
+
+// Line is one item on a reorder list.
+type Line struct {
+	Item   model.Item
+	Amount int
+}
+
+// Group is the part of a reorder list for one supplier. Items without a
+// known supplier have a zero Supplier.
+type Group struct {
+	Supplier model.Supplier
+	Lines    []Line
+	// Cost is the value of the group at the items' unit costs.
+	Cost float64
+}
+
+// List builds the reorder list of the due items, grouped by supplier in
+// name order with items lacking a supplier last. Lines are sorted by item name.
+func List(items []model.Item, suppliers []model.Supplier) []Group {
+	byID := map[string]model.Supplier{}
+	for _, s := range suppliers {
+		byID[s.ID] = s
+	}
+
+	groups := map[string]*Group{}
+	for _, it := range items {
+		if !Due(it) {
+			continue
+		}
+		s := byID[it.Supplier]
+		g, ok := groups[s.ID]
+		if !ok {
+			g = &Group{Supplier: s}
+			groups[s.ID] = g
+		}
+		n := Amount(it)
+		g.Lines = append(g.Lines, Line{Item: it, Amount: n})
+		g.Cost += float64(n) * it.Cost
+	}
+
+	list := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		sort.Slice(g.Lines, func(i, j int) bool { return g.Lines[i].Item.Name < g.Lines[j].Item.Name })
+		list = append(list, *g)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].Supplier, list[j].Supplier
+		if (a.ID == "") != (b.ID == "") {
+			return b.ID == ""
+		}
+		return a.Name < b.Name
+	})
+	return list
+}
+
+// ParseLevels reads the reorder point and par level fields of the item
+// form. Blank fields mean zero; a par level must not be below the reorder
+// point.
+func ParseLevels(point, par string) (int, int, error) {
+	p, err := parseLevel(point)
+	if err != nil {
+		return 0, 0, err
+	}
+	l, err := parseLevel(par)
+	if err != nil {
+		return 0, 0, err
+	}
+	if l > 0 && l < p {
+		return 0, 0, errors.New("par level must be at least the reorder point")
+	}
+	return p, l, nil
+}
+
+func parseLevel(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, errors.New("stock levels must be whole numbers ≥ 0")
+	}
+	return n, nil
+}
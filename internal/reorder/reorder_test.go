@@ -0,0 +1,66 @@
+package reorder
+
+import (
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestAmount(t *testing.T) {
+	tests := []struct {
+		item model.Item
+		want int
+	}{
+		{model.Item{Quantity: 3, ReorderPoint: 5, ParLevel: 20}, 17},
+		{model.Item{Quantity: 5, ReorderPoint: 5}, 1},
+		{model.Item{Quantity: -2, ReorderPoint: 5, ParLevel: 10}, 10},
+		{model.Item{Quantity: 30, ReorderPoint: 5, ParLevel: 20}, 0},
+	}
+	for _, tt := range tests {
+		if got := Amount(tt.item); got != tt.want {
+			t.Errorf("Amount(%+v) = %d, want %d", tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	suppliers := []model.Supplier{{ID: "b", Name: "Bakery"}, {ID: "d", Name: "Drinks Ltd"}}
+	items := []model.Item{
+		{ID: "1", Name: "soda", Quantity: 2, ReorderPoint: 6, ParLevel: 24, Supplier: "d", Cost: 500},
+		{ID: "2", Name: "bread", Quantity: 1, ReorderPoint: 2, ParLevel: 10, Supplier: "b", Cost: 1000},
+		{ID: "3", Name: "water", Quantity: 20, ReorderPoint: 6, ParLevel: 24, Supplier: "d"},
+		{ID: "4", Name: "napkins", Quantity: 0, ReorderPoint: 1, ParLevel: 5},
+		{ID: "5", Name: "juice", Quantity: 0, ReorderPoint: 2, ParLevel: 4, Supplier: "d", Cost: 800},
+		{ID: "6", Name: "burger", Quantity: 0, ReorderPoint: 2, Recipe: []model.RecipeLine{{Ingredient: "x", Quantity: 1}}},
+		{ID: "7", Name: "tea", Quantity: 0},
+	}
+
+	got := List(items, suppliers)
+	if len(got) != 3 {
+		t.Fatalf("got %d groups: %+v", len(got), got)
+	}
+	if got[0].Supplier.Name != "Bakery" || got[1].Supplier.Name != "Drinks Ltd" || got[2].Supplier.ID != "" {
+		t.Fatalf("group order: %+v", got)
+	}
+	drinks := got[1]
+	if len(drinks.Lines) != 2 || drinks.Lines[0].Item.Name != "juice" || drinks.Lines[1].Amount != 22 {
+		t.Fatalf("drinks: %+v", drinks.Lines)
+	}
+	if drinks.Cost != 4*800+22*500 {
+		t.Fatalf("drinks cost: %v", drinks.Cost)
+	}
+}
+
+func TestParseLevels(t *testing.T) {
+	if p, l, err := ParseLevels("5", " 20 "); err != nil || p != 5 || l != 20 {
+		t.Fatalf("got %d %d %v", p, l, err)
+	}
+	if p, l, err := ParseLevels("", ""); err != nil || p != 0 || l != 0 {
+		t.Fatalf("got %d %d %v", p, l, err)
+	}
+	for _, in := range [][2]string{{"10", "5"}, {"-1", ""}, {"x", ""}} {
+		if _, _, err := ParseLevels(in[0], in[1]); err == nil {
+			t.Errorf("ParseLevels(%q, %q): expected error", in[0], in[1])
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package report aggregates orders into the sales summaries used for
+// X-reports (mid-day snapshots) and Z-reports (end-of-day closings).
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Between returns the orders created in the half-open window [from, to).
+// A zero from or to leaves that side of the window open.
+func Between(orders []model.Order, from, to time.Time) []model.Order {
+	var res []model.Order
+	for _, o := range orders {
+		created, err := model.ParseTime(o.CreatedAt)
+		if err != nil {
+			continue
+		}
+		if !from.IsZero() && created.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !created.Before(to) {
+			continue
+		}
+		res = append(res, o)
+	}
+	return res
+}
+
+// Summarize totals the given orders.
+//
+// Cancelled orders and open tabs, which are not paid yet, are only counted
+// by status. Refunded orders count towards
+// gross sales and are then taken off again as refunds, so that
+// Net = Gross - Refunds always holds.
+func Summarize(orders []model.Order) model.SalesSummary {
+	var s model.SalesSummary
+	tenders := map[string]*model.Breakdown{}
+	cashiers := map[string]*model.Breakdown{}
+	statuses := map[string]*model.Breakdown{}
+
+	for _, o := range orders {
+		s.OrderCount++
+		add(statuses, statusOf(o), 1, o.TotalCost)
+
+		if o.Status == model.OrderStatusCancelled || o.Status == model.OrderStatusOpen {
+			continue
+		}
+
+		for _, line := range o.Items {
+			s.ItemCount += line.Quantity
+		}
+
+		s.Gross += o.TotalCost
+
+		net := o.TotalCost
+		if o.Status == model.OrderStatusRefunded {
+			s.Refunds += o.TotalCost
+			net = 0
+		}
+
+		add(tenders, tenderOf(o), 1, net)
+		add(cashiers, CashierOf(o), 1, net)
+	}
+
+	s.Net = s.Gross - s.Refunds
+	s.ByTender = sorted(tenders)
+	s.ByCashier = sorted(cashiers)
+	s.ByStatus = sorted(statuses)
+	return s
+}
+
+// CashierOf returns the best available name for the user who rang up the order.
+func CashierOf(o model.Order) string {
+	switch {
+	case o.Expand.User.Username != "":
+		return o.Expand.User.Username
+	case o.UserID != "":
+		return o.UserID
+	default:
+		return "Unknown"
+	}
+}
+
+func tenderOf(o model.Order) string {
+	if o.Tender == "" {
+		return "unspecified"
+	}
+	return o.Tender
+}
+
+func statusOf(o model.Order) string {
+	if o.Status == "" {
+		return model.OrderStatusPending
+	}
+	return o.Status
+}
+
+func add(m map[string]*model.Breakdown, name string, orders int, amount float64) {
+	b, ok := m[name]
+	if !ok {
+		b = &model.Breakdown{Name: name}
+		m[name] = b
+	}
+	b.Orders += orders
+	b.Amount += amount
+}
+
+// sorted returns the breakdowns largest amount first, then by name.
+func sorted(m map[string]*model.Breakdown) []model.Breakdown {
+	res := make([]model.Breakdown, 0, len(m))
+	for _, b := range m {
+		res = append(res, *b)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Amount != res[j].Amount {
+			return res[i].Amount > res[j].Amount
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestSummarize(t *testing.T) {
+	orders := []model.Order{
+		{UserID: "u1", Tender: "cash", Status: "pending", TotalCost: 9000,
+			Items: []model.Item{{Quantity: 2}, {Quantity: 1}}},
+		{UserID: "u2", Tender: "card", Status: "completed", TotalCost: 5000, Items: []model.Item{{Quantity: 1}}},
+		{UserID: "u1", Tender: "cash", Status: "refunded", TotalCost: 3000, Items: []model.Item{{Quantity: 1}}},
+		{UserID: "u2", Tender: "cash", Status: "cancelled", TotalCost: 7000, Items: []model.Item{{Quantity: 4}}},
+	}
+
+	s := Summarize(orders)
+
+	if s.OrderCount != 4 {
+		t.Fatalf("order count: got %d want 4", s.OrderCount)
+	}
+	if s.ItemCount != 5 {
+		t.Fatalf("item count: got %d want 5", s.ItemCount)
+	}
+	if s.Gross != 17000 || s.Refunds != 3000 || s.Net != 14000 {
+		t.Fatalf("totals: got gross %.0f refunds %.0f net %.0f", s.Gross, s.Refunds, s.Net)
+	}
+	if len(s.ByTender) != 2 || s.ByTender[0].Name != "cash" || s.ByTender[0].Amount != 9000 || s.ByTender[0].Orders != 2 {
+		t.Fatalf("by tender: got %+v", s.ByTender)
+	}
+	if len(s.ByCashier) != 2 || s.ByCashier[0].Name != "u1" || s.ByCashier[1].Amount != 5000 {
+		t.Fatalf("by cashier: got %+v", s.ByCashier)
+	}
+	if len(s.ByStatus) != 4 {
+		t.Fatalf("by status: got %+v", s.ByStatus)
+	}
+}
+
+func TestSummarizeOpenTab(t *testing.T) {
+	s := Summarize([]model.Order{
+		{Tender: "cash", Status: "completed", TotalCost: 5000},
+		{Status: "open", TotalCost: 12000, Items: []model.Item{{Quantity: 3}}},
+	})
+	if s.OrderCount != 2 || s.Net != 5000 || s.ItemCount != 0 || len(s.ByTender) != 1 {
+		t.Fatalf("got %+v", s)
+	}
+	if len(s.ByStatus) != 2 {
+		t.Fatalf("by status: got %+v", s.ByStatus)
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	s := Summarize(nil)
+	if s.OrderCount != 0 || s.Net != 0 || len(s.ByTender) != 0 {
+		t.Fatalf("got %+v", s)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	orders := []model.Order{
+		{ID: "a", CreatedAt: "2025-03-01 07:59:59.000Z"},
+		{ID: "b", CreatedAt: "2025-03-01 08:00:00.000Z"},
+		{ID: "c", CreatedAt: "2025-03-01 17:30:00.000Z"},
+		{ID: "d", CreatedAt: "not a time"},
+	}
+	from := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 17, 30, 0, 0, time.UTC)
+
+	got := Between(orders, from, to)
+	if len(got) != 1 || got[0].ID != "b" {
+		t.Fatalf("got %+v", got)
+	}
+
+	got = Between(orders, from, time.Time{})
+	if len(got) != 2 {
+		t.Fatalf("open end: got %d orders want 2", len(got))
+	}
+}
@@ -0,0 +1,143 @@
+// Package split divides the bill of an order between guests who pay
+// separately: by assigning lines to guests, by seat number, or evenly.
+package split
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Ways a bill can be split.
+const (
+	ByItem = "items"
+	BySeat = "seats"
+	Evenly = "even"
+)
+
+// Modes lists every way a bill can be split.
+var Modes = []string{ByItem, BySeat, Evenly}
+
+// MaxParts is the most parts a bill can be split into.
+const MaxParts = 20
+
+// Part is what one guest pays.
+type Part struct {
+	Number int
+	Label  string
+	Lines  []model.Item
+	Amount float64
+}
+
+// ByGuest splits a bill by the guest each line is assigned to: guests[i]
+// is the guest number, from 1, of the order's line i. Guests without lines
+// get no part. The order total is shared in proportion to the lines, so
+// the parts add up to it.
+func ByGuest(o model.Order, guests []int) ([]Part, error) {
+	if len(guests) != len(o.Items) {
+		return nil, errors.New("every line needs a guest")
+	}
+	for _, g := range guests {
+		if g < 1 || g > MaxParts {
+			return nil, fmt.Errorf("guests are numbered 1 to %d", MaxParts)
+		}
+	}
+	return group(o, guests, "Guest %d"), nil
+}
+
+// BySeats splits a bill by the seat number of each line.
+func BySeats(o model.Order) ([]Part, error) {
+	seats := make([]int, len(o.Items))
+	for i, l := range o.Items {
+		if l.Seat < 1 {
+			return nil, fmt.Errorf("'%s' has no seat", l.Name)
+		}
+		seats[i] = l.Seat
+	}
+	return group(o, seats, "Seat %d"), nil
+}
+
+// Even splits a bill into n equal parts, the first parts taking the
+// remainder of a shilling or two.
+func Even(o model.Order, n int) ([]Part, error) {
+	if n < 2 || n > MaxParts {
+		return nil, fmt.Errorf("split into 2 to %d parts", MaxParts)
+	}
+	parts := make([]Part, n)
+	for i, amount := range compute.SplitEven(o.TotalCost, n) {
+		parts[i] = Part{Number: i + 1, Label: fmt.Sprintf("%d of %d", i+1, n), Amount: amount}
+	}
+	return parts, nil
+}
+
+// group puts line i into part numbers[i], in part number order, and shares
+// the order total between the parts.
+func group(o model.Order, numbers []int, label string) []Part {
+	byNumber := map[int]*Part{}
+	var order []int
+	for i, l := range o.Items {
+		n := numbers[i]
+		p, ok := byNumber[n]
+		if !ok {
+			p = &Part{Number: n, Label: fmt.Sprintf(label, n)}
+			byNumber[n] = p
+			order = append(order, n)
+		}
+		p.Lines = append(p.Lines, l)
+	}
+	sort.Ints(order)
+
+	parts := make([]Part, len(order))
+	weights := make([]float64, len(order))
+	for i, n := range order {
+		parts[i] = *byNumber[n]
+		weights[i] = compute.Subtotal(parts[i].Lines)
+	}
+	for i, amount := range compute.Allocate(o.TotalCost, weights) {
+		parts[i].Amount = amount
+	}
+	return parts
+}
+
+// Payments turns parts into due payments of an order.
+func Payments(o model.Order, parts []Part) []model.Payment {
+	payments := make([]model.Payment, len(parts))
+	for i, p := range parts {
+		payments[i] = model.Payment{
+			Order:  o.ID,
+			Part:   p.Number,
+			Label:  p.Label,
+			Amount: p.Amount,
+			Lines:  p.Lines,
+			Status: model.PaymentDue,
+		}
+	}
+	return payments
+}
+
+// Settled reports whether every part of a split bill is paid.
+func Settled(payments []model.Payment) bool {
+	if len(payments) == 0 {
+		return false
+	}
+	for _, p := range payments {
+		if p.Status != model.PaymentPaid {
+			return false
+		}
+	}
+	return true
+}
+
+// Started reports whether any part of a split bill is paid, after which
+// the split can no longer be changed.
+func Started(payments []model.Payment) bool {
+	for _, p := range payments {
+		if p.Status == model.PaymentPaid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package split
+
+import (
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+var order = model.Order{ID: "o1", TotalCost: 19000, Items: []model.Item{
+	{Name: "beer", Price: 3000, Quantity: 2, Seat: 2},
+	{Name: "burger", Price: 9000, Quantity: 1, Seat: 1},
+	{Name: "chips", Price: 4000, Quantity: 1, Seat: 2},
+}}
+
+func TestByGuest(t *testing.T) {
+	parts, err := ByGuest(order, []int{3, 1, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("got %d parts", len(parts))
+	}
+	if p := parts[0]; p.Number != 1 || p.Label != "Guest 1" || p.Amount != 9000 || len(p.Lines) != 1 {
+		t.Fatalf("first part %+v", p)
+	}
+	if p := parts[1]; p.Label != "Guest 3" || p.Amount != 10000 || len(p.Lines) != 2 {
+		t.Fatalf("second part %+v", p)
+	}
+
+	if _, err := ByGuest(order, []int{1, 2}); err == nil {
+		t.Fatal("split with a line left over")
+	}
+	if _, err := ByGuest(order, []int{1, 0, 2}); err == nil {
+		t.Fatal("split with guest 0")
+	}
+}
+
+func TestByGuestDiscounted(t *testing.T) {
+	o := order
+	o.TotalCost = 10001
+	parts, _ := ByGuest(o, []int{1, 2, 2})
+	if parts[0].Amount+parts[1].Amount != 10001 {
+		t.Fatalf("parts add up to %.0f", parts[0].Amount+parts[1].Amount)
+	}
+}
+
+func TestBySeats(t *testing.T) {
+	parts, err := BySeats(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 || parts[0].Label != "Seat 1" || parts[0].Amount != 9000 || parts[1].Amount != 10000 {
+		t.Fatalf("got %+v", parts)
+	}
+
+	o := order
+	o.Items = append([]model.Item{{Name: "water", Price: 1000, Quantity: 1}}, o.Items...)
+	if _, err := BySeats(o); err == nil {
+		t.Fatal("split with a line without a seat")
+	}
+}
+
+func TestEven(t *testing.T) {
+	o := order
+	o.TotalCost = 10000
+	parts, err := Even(o, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 3 || parts[0].Amount != 3334 || parts[2].Amount != 3333 || parts[1].Label != "2 of 3" {
+		t.Fatalf("got %+v", parts)
+	}
+	if _, err := Even(o, 1); err == nil {
+		t.Fatal("split into one part")
+	}
+}
+
+func TestPayments(t *testing.T) {
+	parts, _ := Even(order, 2)
+	payments := Payments(order, parts)
+	if len(payments) != 2 || payments[0].Order != "o1" || payments[1].Part != 2 || payments[1].Status != model.PaymentDue {
+		t.Fatalf("got %+v", payments)
+	}
+	if Settled(payments) || Started(payments) {
+		t.Fatal("nothing is paid yet")
+	}
+
+	payments[0].Status = model.PaymentPaid
+	if Settled(payments) || !Started(payments) {
+		t.Fatal("one part is paid")
+	}
+	payments[1].Status = model.PaymentPaid
+	if !Settled(payments) {
+		t.Fatal("every part is paid")
+	}
+	if Settled(nil) {
+		t.Fatal("an unsplit bill is not settled")
+	}
+}
@@ -0,0 +1,64 @@
+// Package stock derives stock levels from the stock movement ledger and
+// checks them against the quantities stored on items and ingredients.
+package stock
+
+import (
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// epsilon absorbs float rounding when comparing ingredient quantities.
+const epsilon = 1e-9
+
+// Balance returns the stock level the movements add up to.
+func Balance(movements []model.StockMovement) float64 {
+	var total float64
+	for _, m := range movements {
+		total += m.Quantity
+	}
+	return total
+}
+
+// Drift returns how far the stored quantity is from the ledger balance:
+// positive when more is stored than the ledger accounts for. It is 0 when
+// the two agree.
+func Drift(stored float64, movements []model.StockMovement) float64 {
+	d := stored - Balance(movements)
+	if math.Abs(d) < epsilon {
+		return 0
+	}
+	return d
+}
+
+// Manual lists the movement kinds staff can record by hand. Sales are
+// recorded by orders and stocktakes by counts.
+var Manual = []string{
+	model.MovementRestock,
+	model.MovementWaste,
+	model.MovementRefund,
+	model.MovementAdjustment,
+}
+
+// Signed turns a quantity entered by hand into the change in stock for the
+// kind of movement: restocks and refund returns add stock, waste removes it,
+// and adjustments are taken as entered.
+func Signed(kind string, qty float64) (float64, error) {
+	if !slices.Contains(Manual, kind) {
+		return 0, errors.New("unknown kind of movement")
+	}
+	if qty == 0 {
+		return 0, errors.New("quantity cannot be 0")
+	}
+
+	switch kind {
+	case model.MovementRestock, model.MovementRefund:
+		return math.Abs(qty), nil
+	case model.MovementWaste:
+		return -math.Abs(qty), nil
+	default:
+		return qty, nil
+	}
+}
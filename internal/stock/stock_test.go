@@ -0,0 +1,51 @@
+package stock
+
+import (
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestBalanceAndDrift(t *testing.T) {
+	ledger := []model.StockMovement{
+		{Kind: model.MovementRestock, Quantity: 20},
+		{Kind: model.MovementSale, Quantity: -3},
+		{Kind: model.MovementWaste, Quantity: -1},
+		{Kind: model.MovementRefund, Quantity: 1},
+	}
+	if got := Balance(ledger); got != 17 {
+		t.Fatalf("balance: got %v", got)
+	}
+	if got := Drift(17, ledger); got != 0 {
+		t.Fatalf("drift: got %v", got)
+	}
+	if got := Drift(15, ledger); got != -2 {
+		t.Fatalf("drift: got %v", got)
+	}
+	if got := Drift(0.3, []model.StockMovement{{Quantity: 0.1}, {Quantity: 0.2}}); got != 0 {
+		t.Fatalf("rounding: got %v", got)
+	}
+}
+
+func TestSigned(t *testing.T) {
+	cases := []struct {
+		kind    string
+		qty     float64
+		want    float64
+		wantErr bool
+	}{
+		{model.MovementRestock, 5, 5, false},
+		{model.MovementRestock, -5, 5, false},
+		{model.MovementWaste, 2, -2, false},
+		{model.MovementRefund, 1, 1, false},
+		{model.MovementAdjustment, -4, -4, false},
+		{model.MovementSale, 1, 0, true},
+		{model.MovementWaste, 0, 0, true},
+	}
+	for _, c := range cases {
+		got, err := Signed(c.kind, c.qty)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Fatalf("Signed(%s, %v): got %v, %v", c.kind, c.qty, got, err)
+		}
+	}
+}
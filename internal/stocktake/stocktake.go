@@ -0,0 +1,94 @@
+// Package stocktake compares counted stock with the quantities on record.
+package stocktake
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Totals adds up the counts of each item, since an item may be counted in
+// several places or passes.
+func Totals(counts []model.StocktakeCount) map[string]int {
+	totals := map[string]int{}
+	for _, c := range counts {
+		totals[c.Item] += c.Quantity
+	}
+	return totals
+}
+
+// Variance compares the counted totals with the items' quantities on record
+// and values the difference at cost. Items that were not counted are left
+// out, so a partial stocktake does not zero the rest of the stock. Lines are
+// sorted by name.
+func Variance(items []model.Item, totals map[string]int) []model.VarianceLine {
+	var lines []model.VarianceLine
+	for _, it := range items {
+		counted, ok := totals[it.ID]
+		if !ok {
+			continue
+		}
+		v := counted - it.Quantity
+		lines = append(lines, model.VarianceLine{
+			Item:     it.ID,
+			Name:     it.Name,
+			Expected: it.Quantity,
+			Counted:  counted,
+			Variance: v,
+			Cost:     it.Cost,
+			Value:    float64(v) * it.Cost,
+		})
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].Name < lines[j].Name })
+	return lines
+}
+
+// Summary is the total of a variance report.
+type Summary struct {
+	Counted int
+	// Over and Short are the numbers of items found above and below record.
+	Over, Short int
+	// Gain and Loss are the values of the surplus and the shortfall at cost;
+	// Loss is negative.
+	Gain, Loss float64
+	Net        float64
+}
+
+// Summarize totals a variance report.
+func Summarize(lines []model.VarianceLine) Summary {
+	var s Summary
+	for _, l := range lines {
+		s.Counted++
+		switch {
+		case l.Variance > 0:
+			s.Over++
+			s.Gain += l.Value
+		case l.Variance < 0:
+			s.Short++
+			s.Loss += l.Value
+		}
+	}
+	s.Net = s.Gain + s.Loss
+	return s
+}
+
+// ParseCounts reads one counting pass from the count form: a quantity per
+// item ID. Blank fields mean the item was not counted in this pass.
+func ParseCounts(fields map[string]string) (map[string]int, error) {
+	counts := map[string]int{}
+	for id, raw := range fields {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return nil, errors.New("counts must be whole numbers ≥ 0")
+		}
+		counts[id] = n
+	}
+	return counts, nil
+}
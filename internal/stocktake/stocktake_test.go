@@ -0,0 +1,53 @@
+package stocktake
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestVariance(t *testing.T) {
+	items := []model.Item{
+		{ID: "s", Name: "soda", Quantity: 24, Cost: 500},
+		{ID: "c", Name: "chips", Quantity: 10, Cost: 300},
+		{ID: "w", Name: "water", Quantity: 12, Cost: 200},
+		{ID: "u", Name: "uncounted", Quantity: 99, Cost: 100},
+	}
+	counts := []model.StocktakeCount{
+		{Item: "s", Quantity: 12}, {Item: "s", Quantity: 10}, // two shelves
+		{Item: "c", Quantity: 11},
+		{Item: "w", Quantity: 12},
+	}
+
+	got := Variance(items, Totals(counts))
+	want := []model.VarianceLine{
+		{Item: "c", Name: "chips", Expected: 10, Counted: 11, Variance: 1, Cost: 300, Value: 300},
+		{Item: "s", Name: "soda", Expected: 24, Counted: 22, Variance: -2, Cost: 500, Value: -1000},
+		{Item: "w", Name: "water", Expected: 12, Counted: 12, Variance: 0, Cost: 200, Value: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v", got)
+	}
+
+	sum := Summarize(got)
+	if sum != (Summary{Counted: 3, Over: 1, Short: 1, Gain: 300, Loss: -1000, Net: -700}) {
+		t.Fatalf("got %+v", sum)
+	}
+}
+
+func TestParseCounts(t *testing.T) {
+	got, err := ParseCounts(map[string]string{"a": "3", "b": " ", "c": "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, map[string]int{"a": 3, "c": 0}) {
+		t.Fatalf("got %v", got)
+	}
+	if _, err := ParseCounts(map[string]string{"a": "-1"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := ParseCounts(map[string]string{"a": "1.5"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
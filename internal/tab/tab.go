@@ -0,0 +1,165 @@
+// Package tab keeps orders open as tabs: guests add rounds to them over
+// time, each round going to the kitchen on its own, and pay once when the
+// tab is closed.
+package tab
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rustacean-dev/possystem/internal/compute"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// Ways tabs can be listed.
+const (
+	ByTable    = "table"
+	ByCustomer = "customer"
+)
+
+// Round is one round of a tab: the lines ordered together.
+type Round struct {
+	Number int
+	Lines  []model.Item
+	Total  float64
+}
+
+// Open returns a new tab for a customer or table, without any rounds.
+func Open(customer, table, userID string) model.Order {
+	return model.Order{
+		UserID:   userID,
+		Status:   model.OrderStatusOpen,
+		Customer: strings.TrimSpace(customer),
+		Table:    table,
+		Items:    []model.Item{},
+	}
+}
+
+// Rounds groups the lines of an order by round, in round order. Lines
+// without a round, as on orders that were never a tab, form round 1.
+func Rounds(o model.Order) []Round {
+	byNumber := map[int]*Round{}
+	var numbers []int
+	for _, l := range o.Items {
+		n := max(l.Round, 1)
+		r, ok := byNumber[n]
+		if !ok {
+			r = &Round{Number: n}
+			byNumber[n] = r
+			numbers = append(numbers, n)
+		}
+		r.Lines = append(r.Lines, l)
+		r.Total += compute.LineTotal(l)
+	}
+	sort.Ints(numbers)
+
+	rounds := make([]Round, 0, len(numbers))
+	for _, n := range numbers {
+		rounds = append(rounds, *byNumber[n])
+	}
+	return rounds
+}
+
+// Next returns the number of the next round of an order.
+func Next(o model.Order) int {
+	next := 1
+	for _, l := range o.Items {
+		next = max(next, l.Round+1)
+	}
+	return next
+}
+
+// Add appends a round of lines to an open tab and updates its total. It
+// returns the tab and the lines as added, numbered with their round.
+func Add(o model.Order, lines []model.Item) (model.Order, []model.Item, error) {
+	if o.Status != model.OrderStatusOpen {
+		return o, nil, errors.New("this tab is closed")
+	}
+	if len(lines) == 0 {
+		return o, nil, errors.New("a round needs at least one line")
+	}
+
+	n := Next(o)
+	round := make([]model.Item, len(lines))
+	for i, l := range lines {
+		l.Round = n
+		round[i] = l
+	}
+
+	o.Items = append(append([]model.Item{}, o.Items...), round...)
+	o.TotalCost = compute.Subtotal(o.Items)
+	return o, round, nil
+}
+
+// Close settles an open tab with the given tender. The total is worked
+// out again from all rounds.
+func Close(o model.Order, tender string) (model.Order, error) {
+	if o.Status != model.OrderStatusOpen {
+		return o, errors.New("this tab is already closed")
+	}
+	if len(o.Items) == 0 {
+		return o, errors.New("an empty tab cannot be closed; cancel it instead")
+	}
+	o.Status = model.OrderStatusCompleted
+	o.Tender = tender
+	o.TotalCost = compute.Subtotal(o.Items)
+	return o, nil
+}
+
+// Label names a tab for staff: by customer name, table, or both, e.g.
+// "Amina (T4)". Tables are looked up by ID.
+func Label(o model.Order, tables map[string]model.Table) string {
+	table := ""
+	if t, ok := tables[o.Table]; ok {
+		table = fmt.Sprintf("T%d", t.Number)
+	}
+	switch {
+	case o.Customer != "" && table != "":
+		return o.Customer + " (" + table + ")"
+	case o.Customer != "":
+		return o.Customer
+	case table != "":
+		return table
+	default:
+		return "Tab #" + shortID(o.ID)
+	}
+}
+
+// Sort orders tabs by table number or by customer name, as by says, and
+// by opening time after that. Tabs without a table or name come last.
+func Sort(tabs []model.Order, tables map[string]model.Table, by string) {
+	sort.SliceStable(tabs, func(i, j int) bool {
+		a, b := tabs[i], tabs[j]
+		switch by {
+		case ByTable:
+			ta, oka := tables[a.Table]
+			tb, okb := tables[b.Table]
+			if oka != okb {
+				return oka
+			}
+			if ta.Number != tb.Number {
+				return ta.Number < tb.Number
+			}
+		case ByCustomer:
+			ca, cb := strings.ToLower(a.Customer), strings.ToLower(b.Customer)
+			if (ca == "") != (cb == "") {
+				return ca != ""
+			}
+			if ca != cb {
+				return ca < cb
+			}
+		}
+		return a.CreatedAt < b.CreatedAt
+	})
+}
+
+// shortID returns the last six characters of a record ID, as the kitchen
+// display shows them.
+func shortID(id string) string {
+	if len(id) <= 6 {
+		return id
+	}
+	return id[len(id)-6:]
+}
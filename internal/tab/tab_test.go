@@ -0,0 +1,93 @@
+package tab
+
+import (
+	"testing"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+func TestAddRounds(t *testing.T) {
+	o := Open(" Amina ", "t4", "u1")
+	if o.Customer != "Amina" || o.Status != model.OrderStatusOpen {
+		t.Fatalf("got %+v", o)
+	}
+
+	o, drinks, err := Add(o, []model.Item{{ID: "beer", Price: 3000, Quantity: 2}})
+	if err != nil || drinks[0].Round != 1 || o.TotalCost != 6000 {
+		t.Fatalf("got %+v, %+v, %v", o, drinks, err)
+	}
+	o, food, err := Add(o, []model.Item{
+		{ID: "chips", Price: 4000, Quantity: 1},
+		{ID: "beer", Price: 3000, Quantity: 1},
+	})
+	if err != nil || len(food) != 2 || food[0].Round != 2 || food[1].Round != 2 {
+		t.Fatalf("got %+v, %v", food, err)
+	}
+	if o.TotalCost != 13000 || len(o.Items) != 3 {
+		t.Fatalf("got %+v", o)
+	}
+
+	rounds := Rounds(o)
+	if len(rounds) != 2 || rounds[0].Total != 6000 || rounds[1].Total != 7000 || len(rounds[1].Lines) != 2 {
+		t.Fatalf("rounds %+v", rounds)
+	}
+
+	if _, _, err := Add(o, nil); err == nil {
+		t.Fatal("added an empty round")
+	}
+	if _, _, err := Add(model.Order{Status: model.OrderStatusCompleted}, drinks); err == nil {
+		t.Fatal("added a round to a closed order")
+	}
+}
+
+func TestRoundsWithoutNumbers(t *testing.T) {
+	rounds := Rounds(model.Order{Items: []model.Item{{ID: "a", Price: 1000, Quantity: 1}}})
+	if len(rounds) != 1 || rounds[0].Number != 1 || rounds[0].Total != 1000 {
+		t.Fatalf("got %+v", rounds)
+	}
+	if n := Next(model.Order{}); n != 1 {
+		t.Fatalf("next round of an empty tab is %d", n)
+	}
+}
+
+func TestClose(t *testing.T) {
+	o, _, _ := Add(Open("", "", ""), []model.Item{{ID: "a", Price: 2500, Quantity: 2}})
+	o.TotalCost = 0
+
+	closed, err := Close(o, model.TenderCard)
+	if err != nil || closed.Status != model.OrderStatusCompleted || closed.Tender != model.TenderCard || closed.TotalCost != 5000 {
+		t.Fatalf("got %+v, %v", closed, err)
+	}
+	if _, err := Close(closed, model.TenderCash); err == nil {
+		t.Fatal("closed a tab twice")
+	}
+	if _, err := Close(Open("", "", ""), model.TenderCash); err == nil {
+		t.Fatal("closed an empty tab")
+	}
+}
+
+func TestLabelAndSort(t *testing.T) {
+	tables := map[string]model.Table{"t2": {ID: "t2", Number: 2}, "t9": {ID: "t9", Number: 9}}
+	tabs := []model.Order{
+		{ID: "abc123456789", CreatedAt: "2025-03-12 18:00:00.000Z"},
+		{ID: "o2", Customer: "zoe", Table: "t2", CreatedAt: "2025-03-12 18:05:00.000Z"},
+		{ID: "o3", Customer: "Amina", Table: "t9", CreatedAt: "2025-03-12 18:10:00.000Z"},
+	}
+
+	labels := []string{Label(tabs[0], tables), Label(tabs[1], tables), Label(tabs[2], tables)}
+	if labels[0] != "Tab #456789" || labels[1] != "zoe (T2)" || labels[2] != "Amina (T9)" {
+		t.Fatalf("labels %v", labels)
+	}
+	if got := Label(model.Order{Table: "t9"}, tables); got != "T9" {
+		t.Fatalf("table label %q", got)
+	}
+
+	Sort(tabs, tables, ByTable)
+	if tabs[0].ID != "o2" || tabs[1].ID != "o3" || tabs[2].ID != "abc123456789" {
+		t.Fatalf("by table %v %v %v", tabs[0].ID, tabs[1].ID, tabs[2].ID)
+	}
+	Sort(tabs, tables, ByCustomer)
+	if tabs[0].ID != "o3" || tabs[1].ID != "o2" || tabs[2].ID != "abc123456789" {
+		t.Fatalf("by customer %v %v %v", tabs[0].ID, tabs[1].ID, tabs[2].ID)
+	}
+}
@@ -0,0 +1,74 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "Orders & Items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("name", "qty", "price"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("chai <large>", 3, 1500.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("missing part %s", name)
+		}
+	}
+
+	if !strings.Contains(files["xl/workbook.xml"], `name="Orders &amp; Items"`) {
+		t.Fatalf("sheet name not escaped: %s", files["xl/workbook.xml"])
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<row r="1">`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">chai &lt;large&gt;</t></is></c>`,
+		`<c r="B2"><v>3</v></c>`,
+		`<c r="C2"><v>1500.5</v></c>`,
+		`</sheetData></worksheet>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Fatalf("sheet missing %q:\n%s", want, sheet)
+		}
+	}
+}
+
+func TestColumn(t *testing.T) {
+	cases := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for i, want := range cases {
+		if got := column(i); got != want {
+			t.Fatalf("column(%d): got %s want %s", i, got, want)
+		}
+	}
+}
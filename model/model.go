@@ -3,47 +3,560 @@ package model
 
 // Thing with a name.
 type Item struct {
-	ID          string  `json:"id"`
-	Price       float64 `json:"price"`
-	Description string  `json:"description"`
-	Name        string  `json:"name"`
-	Quantity    int     `json:"quantity"`
-	CreaatedAt  string  `json:"created"`
-	UpdatedAt   string  `json:"updated"`
+	ID    string  `json:"id"`
+	Price float64 `json:"price"`
+	// Cost is what one unit costs to buy or make, used to value stock.
+	Cost        float64  `json:"cost"`
+	Description string   `json:"description"`
+	Name        string   `json:"name"`
+	Quantity    int      `json:"quantity"`
+	Category    string   `json:"category"`
+	SKU         string   `json:"sku,omitempty"`
+	Barcodes    []string `json:"barcodes,omitempty"`
+	// ModifierGroups are the IDs of the modifier groups offered with the item.
+	ModifierGroups []string `json:"modifier_groups,omitempty"`
+	// Recipe lists the ingredients used by one portion. Items with a recipe
+	// take their stock from the ingredients instead of Quantity.
+	Recipe []RecipeLine `json:"recipe,omitempty"`
+	// ReorderPoint is the stock level at or below which the item should be
+	// reordered, and ParLevel the level to reorder up to. Zero turns
+	// reorder alerts off for the item.
+	ReorderPoint int `json:"reorder_point,omitempty"`
+	ParLevel     int `json:"par_level,omitempty"`
+	// Supplier is the ID of the supplier the item is reordered from.
+	Supplier string `json:"supplier,omitempty"`
+	// Perishable items keep their stock in lots with expiry dates, which
+	// are sold first-expiry-first-out.
+	Perishable bool `json:"perishable,omitempty"`
+	// TypePrices overrides Price for some order types, keyed by type, e.g.
+	// a higher price for delivery.
+	TypePrices map[string]float64 `json:"type_prices,omitempty"`
+	// Modifiers and Note are only set on order lines.
+	Modifiers []Modifier `json:"modifiers,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	// Round is the round of a tab the order line was added in, from 1.
+	Round int `json:"round,omitempty"`
+	// Seat is the seat number of the guest the order line is for.
+	Seat       int    `json:"seat,omitempty"`
+	Archived   bool   `json:"archived"`
+	CreaatedAt string `json:"created"`
+	UpdatedAt  string `json:"updated"`
+}
+
+// Category groups items on the order entry screen. Items reference their
+// category by ID.
+type Category struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Colour    string `json:"colour"`
+	SortOrder int    `json:"sort_order"`
+	// Station is the kitchen station the category's items are prepared
+	// at; empty means the grill.
+	Station string `json:"station,omitempty"`
+}
+
+// Kitchen stations tickets are routed to.
+const (
+	StationGrill = "grill"
+	StationBar   = "bar"
+)
+
+// Stations lists every kitchen station.
+var Stations = []string{StationGrill, StationBar}
+
+// Kitchen ticket statuses, in the order a ticket is bumped through them.
+const (
+	TicketNew       = "new"
+	TicketPreparing = "preparing"
+	TicketReady     = "ready"
+	TicketServed    = "served"
+)
+
+// TicketStatuses lists the ticket statuses in workflow order.
+var TicketStatuses = []string{TicketNew, TicketPreparing, TicketReady, TicketServed}
+
+// Ticket is the part of an order one kitchen station prepares. An order
+// with food and drinks gets a ticket at the grill and one at the bar.
+type Ticket struct {
+	ID      string `json:"id"`
+	Order   string `json:"order"`
+	Station string `json:"station"`
+	Status  string `json:"status"`
+	Lines   []Item `json:"lines"`
+	Round   int    `json:"round,omitempty"`
+	Created string `json:"created"`
+	Updated string `json:"updated"`
+}
+
+// Units ingredients are measured in.
+const (
+	UnitGram  = "g"
+	UnitMl    = "ml"
+	UnitPiece = "pcs"
+)
+
+// Units lists every ingredient unit.
+var Units = []string{UnitGram, UnitMl, UnitPiece}
+
+// Supplier is a business items are bought from.
+type Supplier struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+	Email string `json:"email"`
+}
+
+// Ingredient is a stocked raw material, such as flour or milk.
+type Ingredient struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Unit     string  `json:"unit"`
+	Quantity float64 `json:"quantity"`
+}
+
+// RecipeLine is how much of one ingredient goes into one portion of an item.
+type RecipeLine struct {
+	Ingredient string  `json:"ingredient"`
+	Quantity   float64 `json:"quantity"`
+}
+
+// ModifierGroup is a set of options offered with an item, such as "Size"
+// or "Extras". Options are stored as JSON on the group record.
+type ModifierGroup struct {
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	Options []ModifierOption `json:"options"`
+	// Required groups need at least one option, even if MinSelect is 0.
+	Required  bool `json:"required"`
+	MinSelect int  `json:"min_select"`
+	// MaxSelect is the most options that can be chosen; 0 means no limit.
+	MaxSelect int `json:"max_select"`
+}
+
+// ModifierOption is one choice of a modifier group and its effect on the
+// line's unit price.
+type ModifierOption struct {
+	Name       string  `json:"name"`
+	PriceDelta float64 `json:"price_delta"`
 }
 
+// Modifier is an option chosen on an order line. The group name and price
+// are copied so that orders keep reading the same after the menu changes.
+type Modifier struct {
+	GroupID    string  `json:"group_id"`
+	Group      string  `json:"group"`
+	Name       string  `json:"name"`
+	PriceDelta float64 `json:"price_delta"`
+}
+
+// CartLine is one line of an order being rung up, before it is placed.
+type CartLine struct {
+	ItemID    string     `json:"item_id"`
+	Quantity  int        `json:"quantity"`
+	Modifiers []Modifier `json:"modifiers,omitempty"`
+	Note      string     `json:"note,omitempty"`
+}
+
+// Order statuses.
+const (
+	// OrderStatusOpen is an open tab that rounds are still added to.
+	OrderStatusOpen    = "open"
+	OrderStatusPending = "pending"
+	// Preparing, served, ready and out for delivery are the steps of
+	// dine-in, takeaway and delivery orders between pending and completed.
+	OrderStatusPreparing      = "preparing"
+	OrderStatusServed         = "served"
+	OrderStatusReady          = "ready"
+	OrderStatusOutForDelivery = "out_for_delivery"
+	OrderStatusCompleted      = "completed"
+	OrderStatusCancelled      = "cancelled"
+	OrderStatusRefunded       = "refunded"
+)
+
+// OrderStatuses lists every order status, in lifecycle order.
+var OrderStatuses = []string{
+	OrderStatusOpen,
+	OrderStatusPending,
+	OrderStatusPreparing,
+	OrderStatusServed,
+	OrderStatusReady,
+	OrderStatusOutForDelivery,
+	OrderStatusCompleted,
+	OrderStatusCancelled,
+	OrderStatusRefunded,
+}
+
+// Tender types an order can be paid with.
+const (
+	TenderCash   = "cash"
+	TenderCard   = "card"
+	TenderMobile = "mobile"
+	// TenderSplit marks an order paid in parts, each with a tender of its
+	// own.
+	TenderSplit = "split"
+)
+
+// Payment statuses.
+const (
+	PaymentDue  = "due"
+	PaymentPaid = "paid"
+)
+
+// Payment is one part of a split bill. Parts are set up as due when the
+// bill is split and each is paid separately; the order keeps the total.
+type Payment struct {
+	ID    string `json:"id"`
+	Order string `json:"order"`
+	// Part numbers the parts of the order from 1, and Label names them,
+	// e.g. "Guest 2", "Seat 4" or "1 of 3".
+	Part   int     `json:"part"`
+	Label  string  `json:"label"`
+	Amount float64 `json:"amount"`
+	// Lines are what the part pays for; empty when the bill is split evenly.
+	Lines   []Item `json:"lines"`
+	Status  string `json:"status"`
+	Tender  string `json:"tender"`
+	PaidAt  string `json:"paid_at"`
+	Created string `json:"created"`
+}
+
+// Order types. Orders without a type are counter sales.
+const (
+	OrderDineIn   = "dine_in"
+	OrderTakeaway = "takeaway"
+	OrderDelivery = "delivery"
+)
+
 type Order struct {
 	ID        string  `json:"id"`
 	UserID    string  `json:"user_id"`
 	Items     []Item  `json:"items"`
 	TotalCost float64 `json:"totalcost"`
+	Tender    string  `json:"tender"`
 	Status    string  `json:"status"`
-	CreatedAt string  `json:"created_at"`
-	Updated   string  `json:"updated_at"`
+	Table     string  `json:"table,omitempty"`
+	Customer  string  `json:"customer,omitempty"`
+	// Type is [OrderDineIn], [OrderTakeaway], [OrderDelivery] or empty.
+	// Takeaway orders are collected at PickupAt; delivery orders go to
+	// Address, with Phone to call and DeliveryFee included in TotalCost.
+	Type        string  `json:"type,omitempty"`
+	PickupAt    string  `json:"pickup_at,omitempty"`
+	Address     string  `json:"address,omitempty"`
+	Phone       string  `json:"phone,omitempty"`
+	DeliveryFee float64 `json:"delivery_fee,omitempty"`
+	// Zone is the delivery zone the fee was worked out from. Rider is the
+	// user taking a delivery out, which is timed by DispatchedAt and
+	// DeliveredAt. Collected is the cash the rider took on delivery, and
+	// Settlement the rider settlement it was handed in with.
+	Zone         string  `json:"zone,omitempty"`
+	Rider        string  `json:"rider,omitempty"`
+	DispatchedAt string  `json:"dispatched_at,omitempty"`
+	DeliveredAt  string  `json:"delivered_at,omitempty"`
+	Collected    float64 `json:"collected,omitempty"`
+	Settlement   string  `json:"settlement,omitempty"`
+	// Revision counts the writes to a tab's rounds and its closing, so a
+	// write based on an older read of the tab can be refused.
+	Revision  int    `json:"revision,omitempty"`
+	CreatedAt string `json:"created_at"`
+	Updated   string `json:"updated_at"`
 	Expand    struct {
 		User User `json:"user_id"`
 	} `json:"expand"`
 }
 
+// Zone is a delivery area with its fee. Orders of FreeFrom or more are
+// delivered free; zero means never.
+type Zone struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Fee      float64 `json:"fee"`
+	FreeFrom float64 `json:"free_from"`
+}
+
+// RiderSettlement records the cash a rider handed in at the end of a
+// shift against what their deliveries collected.
+type RiderSettlement struct {
+	ID    string `json:"id"`
+	Rider string `json:"rider"`
+	// Orders are the deliveries settled.
+	Orders     []string `json:"orders"`
+	Expected   float64  `json:"expected"`
+	Counted    float64  `json:"counted"`
+	Difference float64  `json:"difference"`
+	Note       string   `json:"note"`
+	SettledBy  string   `json:"settled_by"`
+	Created    string   `json:"created"`
+}
+
+// Table statuses.
+const (
+	TableFree          = "free"
+	TableOccupied      = "occupied"
+	TableBillRequested = "bill_requested"
+)
+
+// TableStatuses lists the table statuses in the order of a sitting.
+var TableStatuses = []string{TableFree, TableOccupied, TableBillRequested}
+
+// Table is a dine-in table. X and Y place it on the floor plan grid of its
+// area, counting from 1. A table merged into another one for a larger
+// party names it in MergedInto and shares its status and orders.
+type Table struct {
+	ID         string `json:"id"`
+	Number     int    `json:"number"`
+	Seats      int    `json:"seats"`
+	Area       string `json:"area"`
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	Status     string `json:"status"`
+	SeatedAt   string `json:"seated_at"`
+	MergedInto string `json:"merged_into"`
+}
+
+// Stock movement kinds.
+const (
+	MovementSale       = "sale"
+	MovementRestock    = "restock"
+	MovementAdjustment = "adjustment"
+	MovementWaste      = "waste"
+	MovementRefund     = "refund"
+	MovementStocktake  = "stocktake"
+)
+
+// MovementKinds lists every stock movement kind.
+var MovementKinds = []string{
+	MovementSale,
+	MovementRestock,
+	MovementAdjustment,
+	MovementWaste,
+	MovementRefund,
+	MovementStocktake,
+}
+
+// StockMovement is one entry of the append-only stock ledger. It concerns
+// either an item or an ingredient. Quantity is the change in stock:
+// positive when stock comes in, negative when it goes out.
+type StockMovement struct {
+	ID         string  `json:"id"`
+	Item       string  `json:"item"`
+	Ingredient string  `json:"ingredient"`
+	Kind       string  `json:"kind"`
+	Quantity   float64 `json:"quantity"`
+	Reason     string  `json:"reason"`
+	Order      string  `json:"order"`
+	// PurchaseOrder is set on restocks received against a purchase order.
+	PurchaseOrder string `json:"purchase_order"`
+	User          string `json:"user"`
+	Created       string `json:"created"`
+	Expand        struct {
+		User User `json:"user"`
+	} `json:"expand"`
+}
+
+// Purchase order statuses. A draft can still be edited; once sent, goods
+// are received against it until every line is in or it is cancelled.
+const (
+	PurchaseDraft     = "draft"
+	PurchaseSent      = "sent"
+	PurchasePartial   = "partially_received"
+	PurchaseReceived  = "received"
+	PurchaseCancelled = "cancelled"
+)
+
+// PurchaseStatuses lists the purchase order statuses in workflow order.
+var PurchaseStatuses = []string{PurchaseDraft, PurchaseSent, PurchasePartial, PurchaseReceived, PurchaseCancelled}
+
+// PurchaseOrder is an order of stock from a supplier.
+type PurchaseOrder struct {
+	ID        string         `json:"id"`
+	Supplier  string         `json:"supplier"`
+	Status    string         `json:"status"`
+	Note      string         `json:"note"`
+	Lines     []PurchaseLine `json:"lines"`
+	CreatedBy string         `json:"created_by"`
+	SentAt    string         `json:"sent_at"`
+	Created   string         `json:"created"`
+	Updated   string         `json:"updated"`
+	Expand    struct {
+		Supplier  Supplier `json:"supplier"`
+		CreatedBy User     `json:"created_by"`
+	} `json:"expand"`
+}
+
+// PurchaseLine is one item on a purchase order, at the agreed unit cost.
+// Received counts what has been delivered so far.
+type PurchaseLine struct {
+	Item     string  `json:"item"`
+	Name     string  `json:"name"`
+	Quantity int     `json:"quantity"`
+	Cost     float64 `json:"cost"`
+	Received int     `json:"received"`
+}
+
+// Lot is a batch of a perishable item received together. Quantity is
+// what is left of it; Expires is a date, empty if the lot does not expire.
+type Lot struct {
+	ID            string `json:"id"`
+	Item          string `json:"item"`
+	Quantity      int    `json:"quantity"`
+	Received      string `json:"received"`
+	Expires       string `json:"expires"`
+	PurchaseOrder string `json:"purchase_order"`
+	Created       string `json:"created"`
+	Expand        struct {
+		Item Item `json:"item"`
+	} `json:"expand"`
+}
+
+// Stocktake statuses.
+const (
+	StocktakeOpen   = "open"
+	StocktakeClosed = "closed"
+)
+
+// Stocktake is a counting session. Counts are entered while it is open,
+// possibly in several passes; closing it records the variance per counted
+// item and adjusts stock to match.
+type Stocktake struct {
+	ID       string         `json:"id"`
+	Status   string         `json:"status"`
+	Note     string         `json:"note"`
+	OpenedBy string         `json:"opened_by"`
+	ClosedBy string         `json:"closed_by"`
+	ClosedAt string         `json:"closed_at"`
+	Lines    []VarianceLine `json:"lines"`
+	Created  string         `json:"created"`
+	Expand   struct {
+		OpenedBy User `json:"opened_by"`
+	} `json:"expand"`
+}
+
+// StocktakeCount is one counted quantity of an item during a stocktake.
+// An item counted in several places has several counts, which add up.
+type StocktakeCount struct {
+	ID        string `json:"id"`
+	Stocktake string `json:"stocktake"`
+	Item      string `json:"item"`
+	Quantity  int    `json:"quantity"`
+	User      string `json:"user"`
+	Created   string `json:"created"`
+}
+
+// VarianceLine compares the counted quantity of an item with the quantity
+// on record. Variance is counted minus expected; Value is Variance at cost.
+type VarianceLine struct {
+	Item     string  `json:"item"`
+	Name     string  `json:"name"`
+	Expected int     `json:"expected"`
+	Counted  int     `json:"counted"`
+	Variance int     `json:"variance"`
+	Cost     float64 `json:"cost"`
+	Value    float64 `json:"value"`
+}
+
+// PriceChange records a change of an item's selling price.
+type PriceChange struct {
+	ID          string  `json:"id"`
+	Item        string  `json:"item"`
+	OldPrice    float64 `json:"old_price"`
+	NewPrice    float64 `json:"new_price"`
+	EffectiveAt string  `json:"effective_at"`
+	Author      string  `json:"author"`
+	Expand      struct {
+		Author User `json:"author"`
+	} `json:"expand"`
+}
+
 type LoginRequest struct {
 	Identity string `json:"identity"` // Could be username or email
 	Password string `json:"password"`
 }
 
+// Roles of users. Admins manage settings such as API keys; riders deliver
+// orders.
+const (
+	RoleAdmin = "admin"
+	RoleRider = "rider"
+)
+
+// APIKey is a key machine clients call the API with in place of a login.
+// Only a hash of the secret is kept; Prefix is the part of the key that
+// identifies it, shown in lists and used to look it up.
+type APIKey struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Hash   string   `json:"hash"`
+	Scopes []string `json:"scopes"`
+	// ExpiresAt is empty for keys that do not expire.
+	ExpiresAt  string `json:"expires_at"`
+	LastUsedAt string `json:"last_used_at"`
+	Revoked    bool   `json:"revoked"`
+	CreatedBy  string `json:"created_by"`
+	Created    string `json:"created"`
+}
+
+// User is a PocketBase user. Passwords are never read back, so they are
+// left out of the JSON.
 type User struct {
 	ID              string `json:"id"`
 	Email           string `json:"email"`
-	Password        string `json:"password"`
-	PasswordConfirm string `json:"passwordConfirm"`
+	Password        string `json:"-"`
+	PasswordConfirm string `json:"-"`
 	Username        string `json:"username"`
 	CreatedAt       string `json:"createdAt"`
 	UpdatedAt       string `json:"updatedAt"`
 	EmailVisibility bool   `json:"emailVisibility"`
 	Verified        bool   `json:"verified"`
 	Avatar          string `json:"avatar"`
+	Role            string `json:"role"`
 }
 
 type LoginResponse struct {
 	Token string `json:"token"`
 	User  User   `json:"user"`
 }
+
+// OrderFilter narrows down an order listing. From and To are calendar days
+// in "YYYY-MM-DD" form and are both inclusive; empty fields match everything.
+type OrderFilter struct {
+	From   string
+	To     string
+	Status string
+	// Type is an order type, or "counter" for orders without one.
+	Type string
+}
+
+// OrderPage is one page of orders with the pagination info PocketBase
+// returns.
+type OrderPage struct {
+	Items      []Order `json:"items"`
+	Page       int     `json:"page"`
+	PerPage    int     `json:"perPage"`
+	TotalItems int     `json:"totalItems"`
+	TotalPages int     `json:"totalPages"`
+}
+
+// ItemQuery describes one page of the item catalogue listing.
+type ItemQuery struct {
+	Search string
+	// Sort is a field name (name, price, quantity, updated), prefixed with
+	// "-" for descending order.
+	Sort string
+	// Stock is "out" for items with no stock, "low" for items at or below
+	// their reorder point, or LowStockAt if they have none, and empty for
+	// all items.
+	Stock      string
+	LowStockAt int
+	Page       int
+	PerPage    int
+}
+
+// ItemPage is one page of items with the pagination info PocketBase returns.
+type ItemPage struct {
+	Items      []Item `json:"items"`
+	Page       int    `json:"page"`
+	PerPage    int    `json:"perPage"`
+	TotalItems int    `json:"totalItems"`
+	TotalPages int    `json:"totalPages"`
+}
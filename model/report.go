@@ -0,0 +1,34 @@
+package model
+
+// Breakdown is one row of a grouped report total, e.g. all cash sales or
+// all sales rung up by one cashier.
+type Breakdown struct {
+	Name   string  `json:"name"`
+	Orders int     `json:"orders"`
+	Amount float64 `json:"amount"`
+}
+
+// SalesSummary holds the aggregated numbers shown on X- and Z-reports.
+type SalesSummary struct {
+	From       string      `json:"from"`
+	To         string      `json:"to"`
+	OrderCount int         `json:"order_count"`
+	ItemCount  int         `json:"item_count"`
+	Gross      float64     `json:"gross"`
+	Refunds    float64     `json:"refunds"`
+	Net        float64     `json:"net"`
+	ByTender   []Breakdown `json:"by_tender"`
+	ByCashier  []Breakdown `json:"by_cashier"`
+	ByStatus   []Breakdown `json:"by_status"`
+}
+
+// ZReport is a closed business day. Its summary is frozen at closing time
+// and never recomputed.
+type ZReport struct {
+	ID       string       `json:"id"`
+	Sequence int          `json:"sequence"`
+	OpenedAt string       `json:"opened_at"`
+	ClosedAt string       `json:"closed_at"`
+	ClosedBy string       `json:"closed_by"`
+	Summary  SalesSummary `json:"summary"`
+}
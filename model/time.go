@@ -0,0 +1,24 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// TimeLayout is the datetime format PocketBase uses in records.
+const TimeLayout = "2006-01-02 15:04:05.000Z"
+
+// ParseTime parses a PocketBase datetime such as "2024-05-01 10:04:05.123Z".
+// RFC 3339 timestamps are accepted as well.
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse("2006-01-02 15:04:05Z07:00", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// FormatTime formats t the way PocketBase stores datetimes.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(TimeLayout)
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetAPIKeys fetches every API key, newest first, revoked ones included.
+// Requires "List/Search" access on 'api_keys': @request.auth.role = "admin"
+func GetAPIKeys(token string) ([]model.APIKey, error) {
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/api_keys/records?sort=-created&perPage=200", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch API keys: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.APIKey `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// GetAPIKeyByPrefix fetches the API key with the given prefix, the id part
+// of the key a client sent.
+// Requires "List/Search" access on 'api_keys': @request.auth.role = "admin"
+func GetAPIKeyByPrefix(prefix, token string) (model.APIKey, error) {
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf("prefix = %q", prefix))
+	query.Set("perPage", "1")
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/api_keys/records?"+query.Encode(), nil)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return model.APIKey{}, fmt.Errorf("API key lookup failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var res struct {
+		Items []model.APIKey `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return model.APIKey{}, err
+	}
+	if len(res.Items) == 0 {
+		return model.APIKey{}, ErrNotFound
+	}
+	return res.Items[0], nil
+}
+
+// CreateAPIKey saves a new API key. Only the hash of its secret is sent.
+// Requires "Create" access on 'api_keys': @request.auth.role = "admin"
+func CreateAPIKey(k model.APIKey, token string) error {
+	return saveAPIKey("POST", "http://127.0.0.1:8090/api/collections/api_keys/records", map[string]any{
+		"name":       k.Name,
+		"prefix":     k.Prefix,
+		"hash":       k.Hash,
+		"scopes":     k.Scopes,
+		"expires_at": k.ExpiresAt,
+		"revoked":    false,
+		"created_by": k.CreatedBy,
+	}, token)
+}
+
+// RevokeAPIKey stops an API key from working. The key is kept so its name
+// and last use still show.
+// Requires "Update" access on 'api_keys': @request.auth.role = "admin"
+func RevokeAPIKey(id, token string) error {
+	return saveAPIKey("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/api_keys/records/%s", id), map[string]any{
+		"revoked": true,
+	}, token)
+}
+
+// TouchAPIKey records when an API key was last used.
+// Requires "Update" access on 'api_keys': @request.auth.role = "admin"
+func TouchAPIKey(id string, at time.Time, token string) error {
+	return saveAPIKey("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/api_keys/records/%s", id), map[string]any{
+		"last_used_at": model.FormatTime(at),
+	}, token)
+}
+
+func saveAPIKey(method, url string, fields map[string]any, token string) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to save API key (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -2,10 +2,12 @@ package repository
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/rustacean-dev/possystem/model"
@@ -38,10 +40,6 @@ func LoginUser(login model.LoginRequest) (*model.LoginResponse, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	// Log the request details for debugging
-	fmt.Println(" Sending request to:", req.URL.String())
-	fmt.Println(" Payload:", string(loginJSON))
-
 	// Set up the HTTP client with a timeout
 	client := &http.Client{
 		Timeout: 10 * time.Second,
@@ -62,6 +60,44 @@ func LoginUser(login model.LoginRequest) (*model.LoginResponse, error) {
 		return nil, fmt.Errorf("login failed: %s", string(body))
 	}
 
+	return decodeAuth(body)
+}
+
+// RefreshAuth exchanges a valid auth token for a new one, so API clients
+// can stay signed in without sending the password again.
+// PocketBase rejects tokens that have expired or whose user is gone.
+func RefreshAuth(token string) (*model.LoginResponse, error) {
+	req, err := http.NewRequest("POST", AuthAPI()+"/auth-refresh", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("refresh failed: %s", string(body))
+	}
+
+	return decodeAuth(body)
+}
+
+// decodeAuth reads the token and user record PocketBase answers a
+// successful login or refresh with.
+func decodeAuth(body []byte) (*model.LoginResponse, error) {
 	// Parse the successful response into a temporary struct
 	var response struct {
 		Token  string `json:"token"`
@@ -74,6 +110,7 @@ func LoginUser(login model.LoginRequest) (*model.LoginResponse, error) {
 			Updated         string `json:"updated"`
 			Verified        bool   `json:"verified"`
 			Avatar          string `json:"avatar"`
+			Role            string `json:"role"`
 		} `json:"record"`
 	}
 
@@ -93,9 +130,88 @@ func LoginUser(login model.LoginRequest) (*model.LoginResponse, error) {
 			Avatar:          response.Record.Avatar,
 			CreatedAt:       response.Record.Created,
 			UpdatedAt:       response.Record.Updated,
+			Role:            response.Record.Role,
 		},
 	}
 
 	return loginResponse, nil
+}
+
+// TokenUserID returns the ID of the user a PocketBase auth token was issued to.
+// The token signature is not checked here; PocketBase verifies it on every
+// request the token is used for.
+func TokenUserID(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed token")
+	}
 
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("error decoding token payload: %w", err)
+	}
+
+	var claims struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("error decoding token claims: %w", err)
+	}
+	if claims.ID == "" {
+		return "", fmt.Errorf("token has no user id")
+	}
+
+	return claims.ID, nil
+}
+
+// TokenExpired reports whether a PocketBase auth token has expired by now,
+// or cannot be read. Like [TokenUserID] it does not check the signature.
+func TokenExpired(token string, now time.Time) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return true
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return true
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return true
+	}
+	return claims.Exp != 0 && now.Unix() >= claims.Exp
+}
+
+// GetUserByID fetches a user, e.g. to look up the role of the user a token
+// was issued to.
+// Requires "View" access on 'users': id = @request.auth.id, or wider
+func GetUserByID(id, token string) (model.User, error) {
+	req, err := http.NewRequest("GET", AuthAPI()+"/records/"+id, nil)
+	if err != nil {
+		return model.User{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.User{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.User{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.User{}, fmt.Errorf("user lookup failed (%d)", resp.StatusCode)
+	}
+
+	var u model.User
+	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
+		return model.User{}, err
+	}
+	return u, nil
 }
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// batchRequest is one write of a PocketBase batch.
+type batchRequest struct {
+	Method string         `json:"method"`
+	URL    string         `json:"url"`
+	Body   map[string]any `json:"body"`
+}
+
+// batch runs writes in one PocketBase transaction: if any of them fails,
+// none is saved. Each write is checked against the rules of its collection.
+// Requires the Batch API to be enabled in the PocketBase settings.
+func batch(requests []batchRequest, token string) error {
+	data, err := json.Marshal(map[string]any{"requests": requests})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", "http://127.0.0.1:8090/api/batch", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("batch failed (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// newRecordID returns a random record ID in PocketBase's format, so a
+// record created in a batch can be referred to by the other writes.
+func newRecordID() (string, error) {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, 15)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = chars[int(b[i])%len(chars)]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetCategories fetches all menu categories in display order.
+// Requires "List/Search" access on 'categories': @request.auth.id != ""
+func GetCategories(token string) ([]model.Category, error) {
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/categories/records?sort=sort_order,name&perPage=200", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch categories: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Category `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// CreateCategory adds a menu category.
+// Requires "Create" access on 'categories': @request.auth.id != ""
+func CreateCategory(c model.Category, token string) error {
+	return saveCategory("POST", "http://127.0.0.1:8090/api/collections/categories/records", c, token)
+}
+
+// UpdateCategory saves the name, colour, sort order and station of a category.
+// Requires "Update" access on 'categories': @request.auth.id != ""
+func UpdateCategory(c model.Category, token string) error {
+	return saveCategory("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/categories/records/%s", c.ID), c, token)
+}
+
+func saveCategory(method, url string, c model.Category, token string) error {
+	data, err := json.Marshal(map[string]any{
+		"name":       c.Name,
+		"colour":     c.Colour,
+		"sort_order": c.SortOrder,
+		"station":    c.Station,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to save category (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// DeleteCategory removes a category. PocketBase refuses if items still
+// reference it, unless the relation is set to clear on delete.
+// Requires "Delete" access on 'categories': @request.auth.id != ""
+func DeleteCategory(id, token string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8090/api/collections/categories/records/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete category (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetZones fetches the delivery zones by name.
+// Requires "List/Search" access on 'zones': @request.auth.id != ""
+func GetZones(token string) ([]model.Zone, error) {
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/zones/records?sort=name&perPage=200", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch zones: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Zone `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// GetZoneByID fetches one delivery zone.
+// Requires "View" access on 'zones': @request.auth.id != ""
+func GetZoneByID(id, token string) (model.Zone, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://127.0.0.1:8090/api/collections/zones/records/%s", id), nil)
+	if err != nil {
+		return model.Zone{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Zone{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Zone{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.Zone{}, fmt.Errorf("zone lookup failed (%d)", resp.StatusCode)
+	}
+
+	var z model.Zone
+	if err := json.NewDecoder(resp.Body).Decode(&z); err != nil {
+		return model.Zone{}, err
+	}
+	return z, nil
+}
+
+// CreateZone adds a delivery zone.
+// Requires "Create" access on 'zones': @request.auth.id != ""
+func CreateZone(z model.Zone, token string) error {
+	return saveZone("POST", "http://127.0.0.1:8090/api/collections/zones/records", z, token)
+}
+
+// UpdateZone saves the name and fees of a delivery zone.
+// Requires "Update" access on 'zones': @request.auth.id != ""
+func UpdateZone(z model.Zone, token string) error {
+	return saveZone("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/zones/records/%s", z.ID), z, token)
+}
+
+// DeleteZone removes a delivery zone. Orders keep the fee they were
+// charged.
+// Requires "Delete" access on 'zones': @request.auth.id != ""
+func DeleteZone(id, token string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8090/api/collections/zones/records/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete zone (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func saveZone(method, url string, z model.Zone, token string) error {
+	data, err := json.Marshal(map[string]any{
+		"name":      z.Name,
+		"fee":       z.Fee,
+		"free_from": z.FreeFrom,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to save zone (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// GetRiders fetches the users with the rider role, by name.
+// Requires "List/Search" access on 'users' for staff, e.g. @request.auth.id != ""
+func GetRiders(token string) ([]model.User, error) {
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf("role = %q", model.RoleRider))
+	query.Set("sort", "username")
+	query.Set("perPage", "200")
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/users/records?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch riders: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.User `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// GetDeliveryOrders fetches the delivery orders on the dispatch board:
+// those not yet delivered, cancelled or refunded.
+// Requires "List/Search" access on 'orders': @request.auth.id != ""
+func GetDeliveryOrders(token string) ([]model.Order, error) {
+	return findOrders(fmt.Sprintf(
+		"type = %q && (status = %q || status = %q || status = %q || status = %q)",
+		model.OrderDelivery, model.OrderStatusPending, model.OrderStatusPreparing, model.OrderStatusReady, model.OrderStatusOutForDelivery,
+	), token)
+}
+
+// GetUnsettledDeliveries fetches the orders a rider delivered that have
+// not been settled yet, or those of every rider if rider is empty.
+// Requires "List/Search" access on 'orders': @request.auth.id != ""
+func GetUnsettledDeliveries(rider, token string) ([]model.Order, error) {
+	filter := `rider != ""`
+	if rider != "" {
+		filter = fmt.Sprintf("rider = %q", rider)
+	}
+	return findOrders(filter+` && delivered_at != "" && settlement = ""`, token)
+}
+
+func findOrders(filter, token string) ([]model.Order, error) {
+	query := url.Values{}
+	query.Set("filter", filter)
+	query.Set("sort", "created_at")
+	query.Set("perPage", "500")
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/orders/records?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch orders: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Order `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// DispatchOrder saves the rider and dispatch time of an order sent out
+// for delivery, and publishes an [event.OrderStatusChanged].
+// Requires "Update" access on 'orders': @request.auth.id != ""
+func DispatchOrder(o model.Order, from, token string) error {
+	saved, err := patchOrderRecord(o.ID, map[string]any{
+		"status":        o.Status,
+		"rider":         o.Rider,
+		"dispatched_at": o.DispatchedAt,
+	}, token)
+	if err != nil {
+		return err
+	}
+
+	Events.Publish(event.OrderStatusChanged{Order: o.ID, From: from, To: o.Status, At: saved.Updated})
+	return nil
+}
+
+// DeliverOrder saves the delivery time and cash collected of a delivered
+// order, and publishes an [event.OrderStatusChanged].
+// Requires "Update" access on 'orders': @request.auth.id != ""
+func DeliverOrder(o model.Order, from, token string) error {
+	saved, err := patchOrderRecord(o.ID, map[string]any{
+		"status":       o.Status,
+		"delivered_at": o.DeliveredAt,
+		"collected":    o.Collected,
+	}, token)
+	if err != nil {
+		return err
+	}
+
+	Events.Publish(event.OrderStatusChanged{Order: o.ID, From: from, To: o.Status, At: saved.Updated})
+	return nil
+}
+
+// GetRiderSettlements fetches a rider's past settlements, newest first.
+// Requires "List/Search" access on 'rider_settlements': @request.auth.id != ""
+func GetRiderSettlements(rider, token string) ([]model.RiderSettlement, error) {
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf("rider = %q", rider))
+	query.Set("sort", "-created")
+	query.Set("perPage", "50")
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/rider_settlements/records?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch settlements: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.RiderSettlement `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// SettleRider saves a rider settlement and marks its orders as settled in
+// one batch, so either both are saved or neither is. The orders are read
+// again first: if one has been settled in the meantime, e.g. by the same
+// form sent twice, nothing is written and [ErrConflict] is returned.
+// Should two settlements race past that check, the update rule on
+// 'orders' refuses to settle an order twice and fails the whole batch.
+// Requires the Batch API, "Create" access on 'rider_settlements' and
+// "Update" access on 'orders': @request.auth.id != "" &&
+// (@request.body.settlement:isset = false || settlement = "")
+func SettleRider(s model.RiderSettlement, token string) error {
+	for _, id := range s.Orders {
+		o, err := GetOrderByID(id, token)
+		if err != nil {
+			return err
+		}
+		if o.Settlement != "" {
+			return ErrConflict
+		}
+	}
+
+	id, err := newRecordID()
+	if err != nil {
+		return err
+	}
+	requests := []batchRequest{{
+		Method: "POST",
+		URL:    "/api/collections/rider_settlements/records",
+		Body: map[string]any{
+			"id":         id,
+			"rider":      s.Rider,
+			"orders":     s.Orders,
+			"expected":   s.Expected,
+			"counted":    s.Counted,
+			"difference": s.Difference,
+			"note":       s.Note,
+			"settled_by": s.SettledBy,
+		},
+	}}
+	for _, o := range s.Orders {
+		requests = append(requests, batchRequest{
+			Method: "PATCH",
+			URL:    "/api/collections/orders/records/" + o,
+			Body:   map[string]any{"settlement": id},
+		})
+	}
+	return batch(requests, token)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "errors"
+
+// ErrNotFound is returned by lookups that found no matching record.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned by writes that found the record changed since it
+// was read, so the caller can read it again and redo its change.
+var ErrConflict = errors.New("changed since it was read")
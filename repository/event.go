@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/rustacean-dev/possystem/internal/event"
+
+// Events, when set, is told about the changes made through this package:
+// orders placed and paid, order status changes, stock movements and
+// kitchen tickets.
+var Events *event.Bus
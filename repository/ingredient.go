@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetIngredients fetches all ingredients by name.
+// Requires "List/Search" access on 'ingredients': @request.auth.id != ""
+func GetIngredients(token string) ([]model.Ingredient, error) {
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/ingredients/records?sort=name&perPage=500", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch ingredients: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Ingredient `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// CreateIngredient adds an ingredient with its opening stock and returns
+// the saved record.
+// Requires "Create" access on 'ingredients': @request.auth.id != ""
+func CreateIngredient(ing model.Ingredient, token string) (model.Ingredient, error) {
+	return saveIngredient("POST", "http://127.0.0.1:8090/api/collections/ingredients/records", map[string]any{
+		"name":     ing.Name,
+		"unit":     ing.Unit,
+		"quantity": ing.Quantity,
+	}, token)
+}
+
+// UpdateIngredient saves the name and unit of an ingredient. Stock only
+// changes through [ApplyStockMovement].
+// Requires "Update" access on 'ingredients': @request.auth.id != ""
+func UpdateIngredient(ing model.Ingredient, token string) error {
+	_, err := saveIngredient("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/ingredients/records/%s", ing.ID), map[string]any{
+		"name": ing.Name,
+		"unit": ing.Unit,
+	}, token)
+	return err
+}
+
+func saveIngredient(method, url string, fields map[string]any, token string) (model.Ingredient, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return model.Ingredient{}, err
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return model.Ingredient{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Ingredient{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return model.Ingredient{}, fmt.Errorf("failed to save ingredient (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var saved model.Ingredient
+	if err := json.NewDecoder(resp.Body).Decode(&saved); err != nil {
+		return model.Ingredient{}, err
+	}
+	return saved, nil
+}
+
+// DeleteIngredient removes an ingredient. Recipes that use it make their
+// items unavailable until they are updated.
+// Requires "Delete" access on 'ingredients': @request.auth.id != ""
+func DeleteIngredient(id, token string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8090/api/collections/ingredients/records/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete ingredient (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
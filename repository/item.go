@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rustacean-dev/possystem/model"
@@ -15,15 +18,17 @@ import (
 // It marshals the item data into JSON and includes the bearer token if provided.
 // Requires the "Create" API rule in PocketBase to allow authenticated users:
 // @request.auth.id != ""
-func CreateItem(item model.Item, token string) error {
+//
+// It returns the saved item, so that its opening stock can be recorded.
+func CreateItem(item model.Item, token string) (model.Item, error) {
 	itemJSON, err := json.Marshal(item)
 	if err != nil {
-		return fmt.Errorf("failed to encode item: %w", err)
+		return model.Item{}, fmt.Errorf("failed to encode item: %w", err)
 	}
 
 	req, err := http.NewRequest("POST", "http://127.0.0.1:8090/api/collections/items/records", bytes.NewBuffer(itemJSON))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return model.Item{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -36,16 +41,20 @@ func CreateItem(item model.Item, token string) error {
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return model.Item{}, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("PocketBase error: %s", string(body))
+		return model.Item{}, fmt.Errorf("PocketBase error: %s", string(body))
 	}
 
-	return nil
+	var saved model.Item
+	if err := json.Unmarshal(body, &saved); err != nil {
+		return model.Item{}, err
+	}
+	return saved, nil
 }
 
 // GetItemByID fetches a specific item record from PocketBase by ID.
@@ -63,42 +72,70 @@ func GetItemByID(id, token string) (model.Item, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Item{}, ErrNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
 		return model.Item{}, fmt.Errorf("item lookup failed (%d)", resp.StatusCode)
 	}
 
-	var rec struct {
-		ID          string  `json:"id"`
-		Name        string  `json:"name"`
-		Price       float64 `json:"price"`
-		Description string  `json:"description"`
-		Quantity    int     `json:"quantity"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&rec); err != nil {
+	// Decoded straight into the model, so new item fields such as
+	// type_prices are not dropped on the way
+	var item model.Item
+	if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
 		return model.Item{}, err
 	}
-
-	return model.Item{
-		ID:          rec.ID,
-		Name:        rec.Name,
-		Price:       rec.Price,
-		Description: rec.Description,
-		Quantity:    rec.Quantity,
-	}, nil
+	return item, nil
 }
 
-// UpdateItemStock sends a PATCH request to PocketBase to update the quantity
-// of an item by its ID. The request uses JSON and includes an authorization token.
-//
-// Returns an error if the update fails.
-// UpdateItemStock modifies the quantity field of an existing item.
+// UpdateItem sends a PATCH request to PocketBase with every editable field
+// of the item: name, price, cost, description, category, codes, modifier groups,
+// recipe, reorder settings and archived. Stock only changes through [ApplyStockMovement].
 // Requires "Update" API rule: @request.auth.id != ""
-func UpdateItemStock(id string, newQty int, token string) error {
+func UpdateItem(item model.Item, token string) error {
 	data := map[string]any{
-		"quantity": newQty,
+		"name":            item.Name,
+		"price":           item.Price,
+		"cost":            item.Cost,
+		"description":     item.Description,
+		"category":        item.Category,
+		"sku":             item.SKU,
+		"barcodes":        item.Barcodes,
+		"modifier_groups": item.ModifierGroups,
+		"recipe":          item.Recipe,
+		"reorder_point":   item.ReorderPoint,
+		"par_level":       item.ParLevel,
+		"supplier":        item.Supplier,
+		"perishable":      item.Perishable,
+		"type_prices":     item.TypePrices,
+		"archived":        item.Archived,
 	}
 
 	body, _ := json.Marshal(data)
+	req, _ := http.NewRequest("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/items/records/%s", item.ID), bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update item (%d): %s", resp.StatusCode, string(b))
+	}
+
+	return nil
+}
+
+// SetItemArchived archives or restores an item. Archived items are soft-deleted:
+// they stay in PocketBase so past orders keep their item reference, but they
+// are no longer listed or sold.
+// Requires "Update" API rule: @request.auth.id != ""
+func SetItemArchived(id string, archived bool, token string) error {
+	body, _ := json.Marshal(map[string]any{"archived": archived})
 	req, _ := http.NewRequest("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/items/records/%s", id), bytes.NewBuffer(body))
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/json")
@@ -110,8 +147,198 @@ func UpdateItemStock(id string, newQty int, token string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("failed to update stock (%d)", resp.StatusCode)
+		return fmt.Errorf("failed to archive item (%d)", resp.StatusCode)
 	}
 
 	return nil
 }
+
+// SetItemCost saves the unit cost of an item, e.g. after goods are received.
+// Requires "Update" API rule: @request.auth.id != ""
+func SetItemCost(id string, cost float64, token string) error {
+	body, _ := json.Marshal(map[string]any{"cost": cost})
+	req, _ := http.NewRequest("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/items/records/%s", id), bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("failed to save item cost (%d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// CreatePriceChange records a price change in the 'price_changes' collection.
+// Price history is append-only, so the collection should have no "Update"
+// or "Delete" rule.
+// Requires "Create" API rule: @request.auth.id != ""
+func CreatePriceChange(pc model.PriceChange, token string) error {
+	data, err := json.Marshal(map[string]any{
+		"item":         pc.Item,
+		"old_price":    pc.OldPrice,
+		"new_price":    pc.NewPrice,
+		"effective_at": pc.EffectiveAt,
+		"author":       pc.Author,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", "http://127.0.0.1:8090/api/collections/price_changes/records", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to record price change (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// GetPriceChanges fetches the price history of an item, newest first,
+// expanding the author so their username can be shown.
+// Requires "List/Search" API rule: @request.auth.id != ""
+func GetPriceChanges(itemID, token string) ([]model.PriceChange, error) {
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf("item = %q", itemID))
+	query.Set("sort", "-effective_at")
+	query.Set("expand", "author")
+	query.Set("perPage", "200")
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/price_changes/records?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch price history: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.PriceChange `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// itemSortFields are the fields the item listing may be sorted by.
+var itemSortFields = map[string]bool{"name": true, "price": true, "quantity": true, "updated": true}
+
+// ListItems fetches one page of non-archived items, using PocketBase's
+// page, perPage, sort and filter list parameters.
+// Requires "List/Search" API rule: @request.auth.id != ""
+func ListItems(q model.ItemQuery, token string) (model.ItemPage, error) {
+	filters := []string{"archived = false"}
+	if q.Search != "" {
+		filters = append(filters, fmt.Sprintf("(name ~ %q || description ~ %q || category.name ~ %q)", q.Search, q.Search, q.Search))
+	}
+	switch q.Stock {
+	case "out":
+		filters = append(filters, "quantity <= 0")
+	case "low":
+		filters = append(filters, fmt.Sprintf("((reorder_point > 0 && quantity <= reorder_point) || (reorder_point <= 0 && quantity <= %d))", q.LowStockAt))
+	}
+
+	sort := "name"
+	if itemSortFields[strings.TrimPrefix(q.Sort, "-")] {
+		sort = q.Sort
+	}
+
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PerPage < 1 {
+		q.PerPage = 25
+	}
+
+	query := url.Values{}
+	query.Set("filter", strings.Join(filters, " && "))
+	query.Set("sort", sort)
+	query.Set("page", strconv.Itoa(q.Page))
+	query.Set("perPage", strconv.Itoa(q.PerPage))
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/items/records?"+query.Encode(), nil)
+	if err != nil {
+		return model.ItemPage{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.ItemPage{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return model.ItemPage{}, fmt.Errorf("failed to list items: %s", string(body))
+	}
+
+	var page model.ItemPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return model.ItemPage{}, err
+	}
+	return page, nil
+}
+
+// GetItemByCode finds the item, archived or not, whose SKU or one of whose
+// barcodes equals code. Codes must already be normalized with
+// catalog.NormalizeCode, which also keeps them safe to use in a filter.
+// Requires "List/Search" API rule: @request.auth.id != ""
+func GetItemByCode(code, token string) (model.Item, error) {
+	// barcodes is a JSON field, so match the quoted code inside its JSON text
+	filter := fmt.Sprintf(`sku = "%s" || barcodes ~ '"%s"'`, code, code)
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/items/records?filter="+url.QueryEscape(filter), nil)
+	if err != nil {
+		return model.Item{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Item{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return model.Item{}, fmt.Errorf("failed to look up code: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Item `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return model.Item{}, err
+	}
+	if len(res.Items) == 0 {
+		return model.Item{}, ErrNotFound
+	}
+	return res.Items[0], nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// CreateTicket sends part of an order to a kitchen station and returns the
+// stored ticket. Saved tickets are published as an [event.TicketChanged].
+// Requires "Create" access on 'kitchen_tickets': @request.auth.id != ""
+func CreateTicket(t model.Ticket, token string) (model.Ticket, error) {
+	data, err := json.Marshal(map[string]any{
+		"order":   t.Order,
+		"station": t.Station,
+		"status":  t.Status,
+		"lines":   t.Lines,
+		"round":   t.Round,
+	})
+	if err != nil {
+		return model.Ticket{}, err
+	}
+	return saveTicket("POST", "http://127.0.0.1:8090/api/collections/kitchen_tickets/records", data, token)
+}
+
+// SetTicketStatus moves a ticket to another status and returns it.
+// Requires "Update" access on 'kitchen_tickets': @request.auth.id != ""
+func SetTicketStatus(id, status, token string) (model.Ticket, error) {
+	data, _ := json.Marshal(map[string]any{"status": status})
+	return saveTicket("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/kitchen_tickets/records/%s", id), data, token)
+}
+
+func saveTicket(method, url string, data []byte, token string) (model.Ticket, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return model.Ticket{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Ticket{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Ticket{}, ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return model.Ticket{}, fmt.Errorf("failed to save ticket (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var t model.Ticket
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return model.Ticket{}, err
+	}
+
+	Events.Publish(event.TicketChanged{Ticket: t})
+	return t, nil
+}
+
+// GetOpenTickets fetches the tickets not yet served, oldest first. An empty
+// station returns the tickets of every station.
+// Requires "List/Search" access on 'kitchen_tickets': @request.auth.id != ""
+func GetOpenTickets(station, token string) ([]model.Ticket, error) {
+	filter := fmt.Sprintf(`status != "%s"`, model.TicketServed)
+	if station != "" {
+		filter += fmt.Sprintf(` && station = "%s"`, station)
+	}
+
+	query := url.Values{}
+	query.Set("filter", filter)
+	query.Set("sort", "created")
+	query.Set("perPage", "200")
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/kitchen_tickets/records?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch tickets: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Ticket `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// GetTicketByID fetches a single kitchen ticket.
+// Requires "View" access on 'kitchen_tickets': @request.auth.id != ""
+func GetTicketByID(id, token string) (model.Ticket, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://127.0.0.1:8090/api/collections/kitchen_tickets/records/%s", id), nil)
+	if err != nil {
+		return model.Ticket{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Ticket{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Ticket{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.Ticket{}, fmt.Errorf("ticket lookup failed (%d)", resp.StatusCode)
+	}
+
+	var t model.Ticket
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return model.Ticket{}, err
+	}
+	return t, nil
+}
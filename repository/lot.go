@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// CreateLot records a lot of a perishable item. The item's stock is changed
+// separately, through [ApplyStockMovement].
+// Requires "Create" access on 'stock_lots': @request.auth.id != ""
+func CreateLot(l model.Lot, token string) error {
+	data, err := json.Marshal(map[string]any{
+		"item":           l.Item,
+		"quantity":       l.Quantity,
+		"received":       l.Received,
+		"expires":        l.Expires,
+		"purchase_order": l.PurchaseOrder,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", "http://127.0.0.1:8090/api/collections/stock_lots/records", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to record lot (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// GetLots fetches the lots of an item that still hold stock.
+// Requires "List/Search" access on 'stock_lots': @request.auth.id != ""
+func GetLots(itemID, token string) ([]model.Lot, error) {
+	return getLots(fmt.Sprintf(`item = "%s" && quantity > 0`, itemID), token)
+}
+
+// GetExpiringLots fetches the lots with stock left that expire on or before
+// the given date (YYYY-MM-DD), with their items.
+// Requires "List/Search" access on 'stock_lots': @request.auth.id != ""
+func GetExpiringLots(until, token string) ([]model.Lot, error) {
+	return getLots(fmt.Sprintf(`quantity > 0 && expires != "" && expires <= "%s 23:59:59.999Z"`, until), token)
+}
+
+func getLots(filter, token string) ([]model.Lot, error) {
+	query := url.Values{}
+	query.Set("filter", filter)
+	query.Set("sort", "expires,received")
+	query.Set("expand", "item")
+	query.Set("perPage", "500")
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/stock_lots/records?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch lots: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Lot `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// GetLotByID fetches a single lot with its item.
+// Requires "View" access on 'stock_lots': @request.auth.id != ""
+func GetLotByID(id, token string) (model.Lot, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://127.0.0.1:8090/api/collections/stock_lots/records/%s?expand=item", id), nil)
+	if err != nil {
+		return model.Lot{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Lot{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Lot{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.Lot{}, fmt.Errorf("lot lookup failed (%d)", resp.StatusCode)
+	}
+
+	var l model.Lot
+	if err := json.NewDecoder(resp.Body).Decode(&l); err != nil {
+		return model.Lot{}, err
+	}
+	return l, nil
+}
+
+// TakeFromLot lowers what is left of a lot, using PocketBase's "quantity-"
+// modifier so concurrent sales don't overwrite each other.
+// Requires "Update" access on 'stock_lots': @request.auth.id != ""
+func TakeFromLot(id string, qty int, token string) error {
+	data, _ := json.Marshal(map[string]any{"quantity-": qty})
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/stock_lots/records/%s", id), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update lot (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetModifierGroups fetches all modifier groups by name.
+// Requires "List/Search" access on 'modifier_groups': @request.auth.id != ""
+func GetModifierGroups(token string) ([]model.ModifierGroup, error) {
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/modifier_groups/records?sort=name&perPage=200", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch modifier groups: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.ModifierGroup `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// CreateModifierGroup adds a modifier group.
+// Requires "Create" access on 'modifier_groups': @request.auth.id != ""
+func CreateModifierGroup(g model.ModifierGroup, token string) error {
+	return saveModifierGroup("POST", "http://127.0.0.1:8090/api/collections/modifier_groups/records", g, token)
+}
+
+// UpdateModifierGroup saves a modifier group and its options. Orders keep
+// the names and prices that were chosen at the time.
+// Requires "Update" access on 'modifier_groups': @request.auth.id != ""
+func UpdateModifierGroup(g model.ModifierGroup, token string) error {
+	return saveModifierGroup("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/modifier_groups/records/%s", g.ID), g, token)
+}
+
+func saveModifierGroup(method, url string, g model.ModifierGroup, token string) error {
+	data, err := json.Marshal(map[string]any{
+		"name":       g.Name,
+		"options":    g.Options,
+		"required":   g.Required,
+		"min_select": g.MinSelect,
+		"max_select": g.MaxSelect,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to save modifier group (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// DeleteModifierGroup removes a modifier group. Items offering it stop doing
+// so if the relation is set to clear on delete.
+// Requires "Delete" access on 'modifier_groups': @request.auth.id != ""
+func DeleteModifierGroup(id, token string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8090/api/collections/modifier_groups/records/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete modifier group (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
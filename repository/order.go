@@ -6,7 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/internal/ordertype"
 	"github.com/rustacean-dev/possystem/model"
 )
 
@@ -14,116 +20,329 @@ import (
 // using the `expand=user_id` query to also fetch related user info.
 // Requires "List/Search" access rule: @request.auth.id != "
 func GetAllOrders(token string) ([]model.Order, error) {
-	url := "http://127.0.0.1:8090/api/collections/orders/records?expand=user_id"
+	return GetOrders(model.OrderFilter{}, token)
+}
 
-	// Create request
-	req, err := http.NewRequest("GET", url, nil)
+// GetOrders retrieves all orders matching the filter.
+// Requires "List/Search" access rule: @request.auth.id != "
+func GetOrders(f model.OrderFilter, token string) ([]model.Order, error) {
+	var orders []model.Order
+	err := EachOrder(f, token, func(o model.Order) error {
+		orders = append(orders, o)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return orders, nil
+}
+
+// EachOrder calls fn for every order matching the filter, oldest first.
+// PocketBase pages list results, so orders are fetched one page at a time
+// and handed to fn before the next page is requested. That lets exports
+// stream rows without holding every order in memory.
+// Requires "List/Search" access rule: @request.auth.id != "
+func EachOrder(f model.OrderFilter, token string, fn func(model.Order) error) error {
+	query := url.Values{}
+	query.Set("expand", "user_id")
+	query.Set("sort", "created_at")
+	query.Set("perPage", "200")
+	filter, err := orderFilterExpr(f)
+	if err != nil {
+		return err
+	}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+
+		// Create request
+		req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/orders/records?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+
+		// Set Authorization header with Bearer
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		// Perform request
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		raw, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		// Check for non-200 response
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("failed to fetch orders: %s", string(raw))
+		}
+
+		// Decode response
+		var res struct {
+			Page       int           `json:"page"`
+			TotalPages int           `json:"totalPages"`
+			Items      []model.Order `json:"items"`
+		}
+		if err := json.Unmarshal(raw, &res); err != nil {
+			return err
+		}
+
+		for _, o := range res.Items {
+			if err := fn(o); err != nil {
+				return err
+			}
+		}
+
+		if res.Page >= res.TotalPages {
+			return nil
+		}
+	}
+}
+
+// ListOrders fetches one page of the orders matching the filter, newest
+// first, using PocketBase's page and perPage list parameters.
+// Requires "List/Search" access rule: @request.auth.id != ""
+func ListOrders(f model.OrderFilter, page, perPage int, token string) (model.OrderPage, error) {
+	query := url.Values{}
+	query.Set("expand", "user_id")
+	query.Set("sort", "-created_at")
+	query.Set("page", strconv.Itoa(page))
+	query.Set("perPage", strconv.Itoa(perPage))
+	filter, err := orderFilterExpr(f)
+	if err != nil {
+		return model.OrderPage{}, err
+	}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
 
-	// Set Authorization header with Bearer
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/orders/records?"+query.Encode(), nil)
+	if err != nil {
+		return model.OrderPage{}, err
+	}
 	req.Header.Set("Authorization", "Bearer "+token)
-	fmt.Println("🔐 Token:", token)
 
-	// Perform request
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, err
+		return model.OrderPage{}, err
 	}
 	defer resp.Body.Close()
 
-	// TEMP: Dump full raw body before decoding
-	raw, _ := io.ReadAll(resp.Body)
-	//fmt.Println("\n RAW JSON FROM POCKETBASE ↓\n", string(raw), "\n")
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return model.OrderPage{}, fmt.Errorf("failed to list orders: %s", string(body))
+	}
+
+	var res model.OrderPage
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return model.OrderPage{}, err
+	}
+	return res, nil
+}
+
+// GetOrderByID fetches a single order with its cashier.
+// Requires "View" access on 'orders': @request.auth.id != ""
+func GetOrderByID(id, token string) (model.Order, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://127.0.0.1:8090/api/collections/orders/records/%s?expand=user_id", id), nil)
+	if err != nil {
+		return model.Order{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
 
-	// Reuse raw body for decoding
-	resp.Body = io.NopCloser(bytes.NewBuffer(raw))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Order{}, err
+	}
+	defer resp.Body.Close()
 
-	// Check for non-200 response
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Order{}, ErrNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch orders: %s", string(raw))
+		return model.Order{}, fmt.Errorf("order lookup failed (%d)", resp.StatusCode)
 	}
 
-	// Decode response
-	var res struct {
-		Items []model.Order `json:"items"`
+	var o model.Order
+	if err := json.NewDecoder(resp.Body).Decode(&o); err != nil {
+		return model.Order{}, err
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, err
+	return o, nil
+}
+
+// CheckOrderFilter returns an error if the days of an order filter cannot
+// be read, so callers can tell a bad filter from a failing PocketBase.
+func CheckOrderFilter(f model.OrderFilter) error {
+	_, err := orderFilterExpr(f)
+	return err
+}
+
+// orderFilterExpr turns an order filter into a PocketBase filter expression.
+// Days are interpreted in the server's local time zone.
+func orderFilterExpr(f model.OrderFilter) (string, error) {
+	var parts []string
+
+	if f.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", f.From, time.Local)
+		if err != nil {
+			return "", fmt.Errorf("invalid from date: %w", err)
+		}
+		parts = append(parts, fmt.Sprintf("created_at >= %q", model.FormatTime(from)))
+	}
+
+	if f.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", f.To, time.Local)
+		if err != nil {
+			return "", fmt.Errorf("invalid to date: %w", err)
+		}
+		parts = append(parts, fmt.Sprintf("created_at < %q", model.FormatTime(to.AddDate(0, 0, 1))))
+	}
+
+	if f.Status != "" {
+		parts = append(parts, fmt.Sprintf("status = %q", f.Status))
 	}
 
-	// Print createdAt from each order to verify
-	for _, order := range res.Items {
-		fmt.Printf("✅ Order %s created at %s\n", order.ID, order.CreatedAt)
+	switch f.Type {
+	case "":
+	case ordertype.Counter:
+		parts = append(parts, `type = ""`)
+	default:
+		parts = append(parts, fmt.Sprintf("type = %q", f.Type))
 	}
 
-	return res.Items, nil
+	return strings.Join(parts, " && "), nil
 }
 
 // CreateOrder sends a new order to PocketBase for storage.
 // Requires "Create" rule on the 'orders' collection: @request.auth.id != ""
-func CreateOrder(order model.Order, token string) error {
+//
+// It returns the saved order, so that stock movements can refer to it, and
+// publishes the order as created and paid.
+func CreateOrder(order model.Order, token string) (model.Order, error) {
 	data, err := json.Marshal(order)
 	if err != nil {
-		return err
+		return model.Order{}, err
 	}
 
 	req, err := http.NewRequest("POST", "http://127.0.0.1:8090/api/collections/orders/records", bytes.NewReader(data))
 	if err != nil {
-		return err
+		return model.Order{}, err
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return err
+		return model.Order{}, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create order (%d): %s", resp.StatusCode, string(body))
+		return model.Order{}, fmt.Errorf("failed to create order (%d): %s", resp.StatusCode, string(body))
 	}
+
+	var saved model.Order
+	if err := json.NewDecoder(resp.Body).Decode(&saved); err != nil {
+		return model.Order{}, err
+	}
+
+	for _, e := range event.Paid(saved) {
+		Events.Publish(e)
+	}
+	return saved, nil
+}
+
+// SetOrderStatus moves an order to another status and publishes an
+// [event.OrderStatusChanged].
+// Requires "Update" access on 'orders': @request.auth.id != ""
+func SetOrderStatus(o model.Order, status, token string) error {
+	saved, err := patchOrderRecord(o.ID, map[string]any{"status": status}, token)
+	if err != nil {
+		return err
+	}
+
+	Events.Publish(event.OrderStatusChanged{Order: o.ID, From: o.Status, To: status, At: saved.Updated})
 	return nil
 }
 
-// GetAllItems fetches all item records from PocketBase.
+// GetAllItems fetches all item records from PocketBase that are not archived.
 // Requires "List/Search" access on 'items': @request.auth.id != ""
 func GetAllItems(token string) ([]model.Item, error) {
-	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/items/records", nil)
+	var items []model.Item
+	err := EachItem(false, token, func(it model.Item) error {
+		items = append(items, it)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
+	return items, nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
+// EachItem calls fn for every item, sorted by name, one page at a time.
+// Archived items are skipped unless includeArchived is set.
+// Requires "List/Search" access on 'items': @request.auth.id != ""
+func EachItem(includeArchived bool, token string, fn func(model.Item) error) error {
+	query := url.Values{}
+	query.Set("sort", "name")
+	query.Set("perPage", "200")
+	if !includeArchived {
+		query.Set("filter", "archived = false")
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to fetch items: %s", string(body))
-	}
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/items/records?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
 
-	// Decode JSON response
-	var res struct {
-		Items []model.Item `json:"items"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, err
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return fmt.Errorf("failed to fetch items: %s", string(body))
+		}
+
+		// Decode JSON response
+		var res struct {
+			Page       int          `json:"page"`
+			TotalPages int          `json:"totalPages"`
+			Items      []model.Item `json:"items"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&res)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		for _, it := range res.Items {
+			if err := fn(it); err != nil {
+				return err
+			}
+		}
+
+		if res.Page >= res.TotalPages {
+			return nil
+		}
 	}
-	return res.Items, nil
 }
 
 // GetItemByName fetches an item from PocketBase using a filter on the name field.
+// It returns ErrNotFound if no item has the name.
 // Requires "List/Search" access: @request.auth.id != ""
 func GetItemByName(name, token string) (model.Item, error) {
-	url := fmt.Sprintf("http://127.0.0.1:8090/api/collections/items/records?filter=name=\"%s\"", name)
-	req, err := http.NewRequest("GET", url, nil)
+	filter := url.QueryEscape(fmt.Sprintf("name=%q", name))
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/items/records?filter="+filter, nil)
 	if err != nil {
 		return model.Item{}, err
 	}
@@ -148,7 +367,7 @@ func GetItemByName(name, token string) (model.Item, error) {
 		return model.Item{}, err
 	}
 	if len(res.Items) == 0 {
-		return model.Item{}, fmt.Errorf("item not found")
+		return model.Item{}, ErrNotFound
 	}
 	return res.Items[0], nil
 }
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetOrderPayments fetches the parts of an order's split bill, by part.
+// Requires "List/Search" access on 'payments': @request.auth.id != ""
+func GetOrderPayments(orderID, token string) ([]model.Payment, error) {
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf("order = %q", orderID))
+	query.Set("sort", "part")
+	query.Set("perPage", "100")
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/payments/records?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch payments: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Payment `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// GetPaymentByID fetches one part of a split bill.
+// Requires "View" access on 'payments': @request.auth.id != ""
+func GetPaymentByID(id, token string) (model.Payment, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://127.0.0.1:8090/api/collections/payments/records/%s", id), nil)
+	if err != nil {
+		return model.Payment{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Payment{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Payment{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.Payment{}, fmt.Errorf("payment lookup failed (%d)", resp.StatusCode)
+	}
+
+	var p model.Payment
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return model.Payment{}, err
+	}
+	return p, nil
+}
+
+// CreatePayment saves a due part of a split bill.
+// Requires "Create" access on 'payments': @request.auth.id != ""
+func CreatePayment(p model.Payment, token string) error {
+	return savePayment("POST", "http://127.0.0.1:8090/api/collections/payments/records", map[string]any{
+		"order":  p.Order,
+		"part":   p.Part,
+		"label":  p.Label,
+		"amount": p.Amount,
+		"lines":  p.Lines,
+		"status": model.PaymentDue,
+	}, token)
+}
+
+// PayPayment marks a part of a split bill as paid with the given tender and
+// publishes an [event.PaymentReceived].
+// Requires "Update" access on 'payments': @request.auth.id != ""
+func PayPayment(p model.Payment, tender, token string) error {
+	err := savePayment("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/payments/records/%s", p.ID), map[string]any{
+		"status":  model.PaymentPaid,
+		"tender":  tender,
+		"paid_at": model.FormatTime(time.Now()),
+	}, token)
+	if err != nil {
+		return err
+	}
+
+	Events.Publish(event.PaymentReceived{Order: p.Order, Payment: p.ID, Tender: tender, Amount: p.Amount})
+	return nil
+}
+
+// DeletePayment removes a due part when a bill is split again.
+// Requires "Delete" access on 'payments': @request.auth.id != ""
+func DeletePayment(id, token string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8090/api/collections/payments/records/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete payment (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func savePayment(method, url string, fields map[string]any, token string) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to save payment (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// SetOrderSeats saves the seat numbers of an order's lines.
+// Requires "Update" access on 'orders': @request.auth.id != ""
+func SetOrderSeats(o model.Order, token string) error {
+	return patchOrder(o.ID, map[string]any{"items": o.Items}, token)
+}
+
+// SettleSplitOrder closes an order whose split bill is fully paid. The
+// payments were published part by part, so only the status change is.
+// Requires "Update" access on 'orders': @request.auth.id != ""
+func SettleSplitOrder(o model.Order, token string) error {
+	saved, err := patchOrderRecord(o.ID, map[string]any{
+		"status": model.OrderStatusCompleted,
+		"tender": model.TenderSplit,
+	}, token)
+	if err != nil {
+		return err
+	}
+
+	Events.Publish(event.OrderStatusChanged{Order: o.ID, From: o.Status, To: model.OrderStatusCompleted, At: saved.Updated})
+	return nil
+}
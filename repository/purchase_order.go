@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetPurchaseOrders fetches purchase orders, newest first, with their
+// suppliers. An empty status returns orders in every status.
+// Requires "List/Search" access on 'purchase_orders': @request.auth.id != ""
+func GetPurchaseOrders(status, token string) ([]model.PurchaseOrder, error) {
+	query := url.Values{}
+	query.Set("sort", "-created")
+	query.Set("expand", "supplier,created_by")
+	query.Set("perPage", "200")
+	if status != "" {
+		query.Set("filter", fmt.Sprintf("status = %q", status))
+	}
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/purchase_orders/records?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch purchase orders: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.PurchaseOrder `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// GetPurchaseOrderByID fetches a purchase order with its supplier.
+// Requires "View" access on 'purchase_orders': @request.auth.id != ""
+func GetPurchaseOrderByID(id, token string) (model.PurchaseOrder, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://127.0.0.1:8090/api/collections/purchase_orders/records/%s?expand=supplier,created_by", id), nil)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.PurchaseOrder{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.PurchaseOrder{}, fmt.Errorf("purchase order lookup failed (%d)", resp.StatusCode)
+	}
+
+	var po model.PurchaseOrder
+	if err := json.NewDecoder(resp.Body).Decode(&po); err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	return po, nil
+}
+
+// CreatePurchaseOrder saves a new draft purchase order for the current user
+// and returns it.
+// Requires "Create" access on 'purchase_orders': @request.auth.id != ""
+func CreatePurchaseOrder(po model.PurchaseOrder, token string) (model.PurchaseOrder, error) {
+	if po.CreatedBy == "" {
+		po.CreatedBy, _ = TokenUserID(token)
+	}
+	if po.Lines == nil {
+		po.Lines = []model.PurchaseLine{}
+	}
+	return savePurchaseOrder("POST", "http://127.0.0.1:8090/api/collections/purchase_orders/records", map[string]any{
+		"supplier":   po.Supplier,
+		"status":     model.PurchaseDraft,
+		"note":       po.Note,
+		"lines":      po.Lines,
+		"created_by": po.CreatedBy,
+	}, token)
+}
+
+// UpdatePurchaseOrder saves the supplier, status, note, lines and sent time
+// of a purchase order.
+// Requires "Update" access on 'purchase_orders': @request.auth.id != ""
+func UpdatePurchaseOrder(po model.PurchaseOrder, token string) error {
+	if po.Lines == nil {
+		po.Lines = []model.PurchaseLine{}
+	}
+	_, err := savePurchaseOrder("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/purchase_orders/records/%s", po.ID), map[string]any{
+		"supplier": po.Supplier,
+		"status":   po.Status,
+		"note":     po.Note,
+		"lines":    po.Lines,
+		"sent_at":  po.SentAt,
+	}, token)
+	return err
+}
+
+func savePurchaseOrder(method, url string, fields map[string]any, token string) (model.PurchaseOrder, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return model.PurchaseOrder{}, fmt.Errorf("failed to save purchase order (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var saved model.PurchaseOrder
+	if err := json.NewDecoder(resp.Body).Decode(&saved); err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	return saved, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetZReports fetches all closed business days, newest first.
+// Requires "List/Search" access on 'z_reports': @request.auth.id != ""
+func GetZReports(token string) ([]model.ZReport, error) {
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/z_reports/records?sort=-sequence&perPage=200", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch z-reports: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.ZReport `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// GetLatestZReport fetches the Z-report with the highest sequence number.
+// It returns an empty report (with an empty ID) if no day has been closed yet.
+// Requires "List/Search" access on 'z_reports': @request.auth.id != ""
+func GetLatestZReport(token string) (model.ZReport, error) {
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/z_reports/records?sort=-sequence&perPage=1", nil)
+	if err != nil {
+		return model.ZReport{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.ZReport{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return model.ZReport{}, fmt.Errorf("failed to fetch latest z-report: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.ZReport `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return model.ZReport{}, err
+	}
+	if len(res.Items) == 0 {
+		return model.ZReport{}, nil
+	}
+	return res.Items[0], nil
+}
+
+// GetZReportByID fetches a single Z-report.
+// Requires "View" access on 'z_reports': @request.auth.id != ""
+func GetZReportByID(id, token string) (model.ZReport, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://127.0.0.1:8090/api/collections/z_reports/records/%s", id), nil)
+	if err != nil {
+		return model.ZReport{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.ZReport{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return model.ZReport{}, fmt.Errorf("z-report lookup failed (%d)", resp.StatusCode)
+	}
+
+	var z model.ZReport
+	if err := json.NewDecoder(resp.Body).Decode(&z); err != nil {
+		return model.ZReport{}, err
+	}
+	return z, nil
+}
+
+// CreateZReport stores a closed business day and returns the saved record.
+// Z-reports are never updated afterwards, so the 'z_reports' collection should
+// have no "Update" or "Delete" rule, and a unique index on 'sequence'.
+// Requires "Create" access on 'z_reports': @request.auth.id != ""
+func CreateZReport(z model.ZReport, token string) (model.ZReport, error) {
+	data, err := json.Marshal(z)
+	if err != nil {
+		return model.ZReport{}, err
+	}
+
+	req, err := http.NewRequest("POST", "http://127.0.0.1:8090/api/collections/z_reports/records", bytes.NewReader(data))
+	if err != nil {
+		return model.ZReport{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.ZReport{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return model.ZReport{}, fmt.Errorf("failed to create z-report (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var saved model.ZReport
+	if err := json.NewDecoder(resp.Body).Decode(&saved); err != nil {
+		return model.ZReport{}, err
+	}
+	return saved, nil
+}
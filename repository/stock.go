@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// CreateStockMovement appends an entry to the stock ledger without touching
+// the stored quantity, e.g. for the opening stock of a new item or to bring
+// the ledger in line with a stored quantity. Movements are never edited, so
+// 'stock_movements' should have no "Update" or "Delete" rule.
+// The current user is recorded unless m.User is set.
+// Requires "Create" access on 'stock_movements': @request.auth.id != ""
+func CreateStockMovement(m model.StockMovement, token string) error {
+	_, err := createStockMovement(m, token)
+	return err
+}
+
+// createStockMovement appends m to the ledger and returns its ID.
+func createStockMovement(m model.StockMovement, token string) (string, error) {
+	if m.User == "" {
+		m.User, _ = TokenUserID(token)
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"item":           m.Item,
+		"ingredient":     m.Ingredient,
+		"kind":           m.Kind,
+		"quantity":       m.Quantity,
+		"reason":         m.Reason,
+		"order":          m.Order,
+		"purchase_order": m.PurchaseOrder,
+		"user":           m.User,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", "http://127.0.0.1:8090/api/collections/stock_movements/records", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to record stock movement (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var saved struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&saved); err != nil {
+		return "", err
+	}
+	return saved.ID, nil
+}
+
+// ApplyStockMovement records a movement in the ledger, then changes the
+// stored quantity of its item or ingredient by the same amount. The change
+// uses PocketBase's "quantity+" modifier, so concurrent movements don't
+// overwrite each other. A [event.StockChanged] is published once both are
+// saved.
+// Requires "Update" access on 'items' and 'ingredients': @request.auth.id != ""
+func ApplyStockMovement(m model.StockMovement, token string) error {
+	collection, id := "items", m.Item
+	if m.Ingredient != "" {
+		collection, id = "ingredients", m.Ingredient
+	}
+	if id == "" {
+		return errors.New("stock movement has no item or ingredient")
+	}
+
+	movementID, err := createStockMovement(m, token)
+	if err != nil {
+		return err
+	}
+
+	data, _ := json.Marshal(map[string]any{"quantity+": m.Quantity})
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/%s/records/%s", collection, id), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update stock (%d): %s", resp.StatusCode, string(body))
+	}
+
+	Events.Publish(event.StockChanged{
+		Movement:   movementID,
+		Item:       m.Item,
+		Ingredient: m.Ingredient,
+		Kind:       m.Kind,
+		Quantity:   m.Quantity,
+		Order:      m.Order,
+	})
+	return nil
+}
+
+// GetStockMovements fetches the whole ledger of an item, newest first, with
+// the users who recorded each movement.
+// Requires "List/Search" access on 'stock_movements': @request.auth.id != ""
+func GetStockMovements(itemID, token string) ([]model.StockMovement, error) {
+	return getStockMovements(fmt.Sprintf(`item = "%s"`, itemID), token)
+}
+
+// GetPurchaseOrderMovements fetches the deliveries received against a
+// purchase order, newest first.
+// Requires "List/Search" access on 'stock_movements': @request.auth.id != ""
+func GetPurchaseOrderMovements(poID, token string) ([]model.StockMovement, error) {
+	return getStockMovements(fmt.Sprintf(`purchase_order = "%s"`, poID), token)
+}
+
+func getStockMovements(filter, token string) ([]model.StockMovement, error) {
+	query := url.Values{}
+	query.Set("filter", filter)
+	query.Set("sort", "-created")
+	query.Set("expand", "user")
+	query.Set("perPage", "500")
+
+	var movements []model.StockMovement
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+
+		req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/stock_movements/records?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to fetch stock movements: %s", string(body))
+		}
+
+		var res struct {
+			Page       int                   `json:"page"`
+			TotalPages int                   `json:"totalPages"`
+			Items      []model.StockMovement `json:"items"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&res)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		movements = append(movements, res.Items...)
+		if res.Page >= res.TotalPages {
+			return movements, nil
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetStocktakes fetches all stocktake sessions, newest first.
+// Requires "List/Search" access on 'stocktakes': @request.auth.id != ""
+func GetStocktakes(token string) ([]model.Stocktake, error) {
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/stocktakes/records?sort=-created&perPage=200&expand=opened_by", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch stocktakes: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Stocktake `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// GetStocktakeByID fetches a single stocktake session.
+// Requires "View" access on 'stocktakes': @request.auth.id != ""
+func GetStocktakeByID(id, token string) (model.Stocktake, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://127.0.0.1:8090/api/collections/stocktakes/records/%s?expand=opened_by", id), nil)
+	if err != nil {
+		return model.Stocktake{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Stocktake{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Stocktake{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.Stocktake{}, fmt.Errorf("stocktake lookup failed (%d)", resp.StatusCode)
+	}
+
+	var st model.Stocktake
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return model.Stocktake{}, err
+	}
+	return st, nil
+}
+
+// CreateStocktake opens a stocktake session for the current user and
+// returns it.
+// Requires "Create" access on 'stocktakes': @request.auth.id != ""
+func CreateStocktake(note, token string) (model.Stocktake, error) {
+	userID, _ := TokenUserID(token)
+	data, _ := json.Marshal(map[string]any{
+		"status":    model.StocktakeOpen,
+		"note":      note,
+		"opened_by": userID,
+	})
+
+	req, err := http.NewRequest("POST", "http://127.0.0.1:8090/api/collections/stocktakes/records", bytes.NewReader(data))
+	if err != nil {
+		return model.Stocktake{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Stocktake{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return model.Stocktake{}, fmt.Errorf("failed to create stocktake (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var st model.Stocktake
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return model.Stocktake{}, err
+	}
+	return st, nil
+}
+
+// CloseStocktake marks a session closed by the current user and stores its
+// variance report.
+// Requires "Update" access on 'stocktakes': @request.auth.id != ""
+func CloseStocktake(id string, lines []model.VarianceLine, token string) error {
+	userID, _ := TokenUserID(token)
+	data, _ := json.Marshal(map[string]any{
+		"status":    model.StocktakeClosed,
+		"closed_by": userID,
+		"closed_at": model.FormatTime(time.Now()),
+		"lines":     lines,
+	})
+
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/stocktakes/records/%s", id), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to close stocktake (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// AddStocktakeCount records one counted quantity of an item.
+// Requires "Create" access on 'stocktake_counts': @request.auth.id != ""
+func AddStocktakeCount(c model.StocktakeCount, token string) error {
+	if c.User == "" {
+		c.User, _ = TokenUserID(token)
+	}
+	data, _ := json.Marshal(map[string]any{
+		"stocktake": c.Stocktake,
+		"item":      c.Item,
+		"quantity":  c.Quantity,
+		"user":      c.User,
+	})
+
+	req, err := http.NewRequest("POST", "http://127.0.0.1:8090/api/collections/stocktake_counts/records", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to record count (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// GetStocktakeCounts fetches every count of a stocktake session.
+// Requires "List/Search" access on 'stocktake_counts': @request.auth.id != ""
+func GetStocktakeCounts(stocktakeID, token string) ([]model.StocktakeCount, error) {
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf(`stocktake = "%s"`, stocktakeID))
+	query.Set("sort", "created")
+	query.Set("perPage", "500")
+
+	var counts []model.StocktakeCount
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+
+		req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/stocktake_counts/records?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to fetch counts: %s", string(body))
+		}
+
+		var res struct {
+			Page       int                    `json:"page"`
+			TotalPages int                    `json:"totalPages"`
+			Items      []model.StocktakeCount `json:"items"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&res)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, res.Items...)
+		if res.Page >= res.TotalPages {
+			return counts, nil
+		}
+	}
+}
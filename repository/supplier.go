@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetSuppliers fetches all suppliers by name.
+// Requires "List/Search" access on 'suppliers': @request.auth.id != ""
+func GetSuppliers(token string) ([]model.Supplier, error) {
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/suppliers/records?sort=name&perPage=500", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch suppliers: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Supplier `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// CreateSupplier adds a supplier.
+// Requires "Create" access on 'suppliers': @request.auth.id != ""
+func CreateSupplier(s model.Supplier, token string) error {
+	return saveSupplier("POST", "http://127.0.0.1:8090/api/collections/suppliers/records", s, token)
+}
+
+// UpdateSupplier saves the name and contact details of a supplier.
+// Requires "Update" access on 'suppliers': @request.auth.id != ""
+func UpdateSupplier(s model.Supplier, token string) error {
+	return saveSupplier("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/suppliers/records/%s", s.ID), s, token)
+}
+
+func saveSupplier(method, url string, s model.Supplier, token string) error {
+	data, err := json.Marshal(map[string]any{
+		"name":  s.Name,
+		"phone": s.Phone,
+		"email": s.Email,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to save supplier (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// DeleteSupplier removes a supplier. Items reordered from it are listed
+// without a supplier until they are updated.
+// Requires "Delete" access on 'suppliers': @request.auth.id != ""
+func DeleteSupplier(id, token string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8090/api/collections/suppliers/records/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete supplier (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/rustacean-dev/possystem/internal/event"
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetOpenTabs fetches the orders still open as tabs, oldest first.
+// Requires "List/Search" access on 'orders': @request.auth.id != ""
+func GetOpenTabs(token string) ([]model.Order, error) {
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf("status = %q", model.OrderStatusOpen))
+	query.Set("sort", "created_at")
+	query.Set("perPage", "200")
+	query.Set("expand", "user_id")
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/orders/records?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch tabs: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Order `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// SaveTabRound saves the lines and total of a tab o after a round was added
+// to the tab as read. The whole list of lines is written, so if the tab
+// changed in the meantime, e.g. another waiter added a round, nothing is
+// written and [ErrConflict] is returned.
+// Requires "Update" access on 'orders': @request.auth.id != "" &&
+// (@request.body.revision:isset = false || @request.body.revision > revision)
+func SaveTabRound(read, o model.Order, token string) error {
+	_, err := patchTab(read, map[string]any{
+		"items":     o.Items,
+		"totalcost": o.TotalCost,
+	}, token)
+	return err
+}
+
+// CloseTab saves a settled tab and publishes it as completed and paid. o is
+// the tab as read with its status, tender and total settled; if a round was
+// added to the tab since, nothing is written and [ErrConflict] is returned,
+// so the round is not left unpaid.
+// Requires "Update" access on 'orders': @request.auth.id != "" &&
+// (@request.body.revision:isset = false || @request.body.revision > revision)
+func CloseTab(o model.Order, token string) error {
+	read := o
+	read.Status = model.OrderStatusOpen
+	saved, err := patchTab(read, map[string]any{
+		"status":    o.Status,
+		"tender":    o.Tender,
+		"totalcost": o.TotalCost,
+	}, token)
+	if err != nil {
+		return err
+	}
+
+	Events.Publish(event.OrderStatusChanged{Order: o.ID, From: model.OrderStatusOpen, To: o.Status, At: saved.Updated})
+	Events.Publish(event.PaymentReceived{Order: o.ID, Tender: o.Tender, Amount: o.TotalCost})
+	return nil
+}
+
+// patchTab writes fields to a tab unless it changed since it was read. The
+// tab is read again first, and the write bumps its revision, which the
+// update rule only accepts if it is newer than the stored one. A write
+// racing another one past the check is so refused by PocketBase.
+func patchTab(read model.Order, fields map[string]any, token string) (model.Order, error) {
+	fresh, err := GetOrderByID(read.ID, token)
+	if err != nil {
+		return model.Order{}, err
+	}
+	before, err := json.Marshal(read.Items)
+	if err != nil {
+		return model.Order{}, err
+	}
+	now, err := json.Marshal(fresh.Items)
+	if err != nil {
+		return model.Order{}, err
+	}
+	if fresh.Status != read.Status || fresh.Revision != read.Revision || !bytes.Equal(before, now) {
+		return model.Order{}, ErrConflict
+	}
+
+	fields["revision"] = read.Revision + 1
+	saved, err := patchOrderRecord(read.ID, fields, token)
+	if errors.Is(err, ErrNotFound) {
+		// The rule refused the revision: someone else wrote first
+		return model.Order{}, ErrConflict
+	}
+	return saved, err
+}
+
+func patchOrder(id string, fields map[string]any, token string) error {
+	_, err := patchOrderRecord(id, fields, token)
+	return err
+}
+
+// patchOrderRecord is patchOrder returning the saved order, whose updated
+// time status change events carry.
+func patchOrderRecord(id string, fields map[string]any, token string) (model.Order, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return model.Order{}, err
+	}
+
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/orders/records/%s", id), bytes.NewReader(data))
+	if err != nil {
+		return model.Order{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Order{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Order{}, ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return model.Order{}, fmt.Errorf("failed to update order (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var saved model.Order
+	if err := json.NewDecoder(resp.Body).Decode(&saved); err != nil {
+		return model.Order{}, err
+	}
+	return saved, nil
+}
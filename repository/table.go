@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/rustacean-dev/possystem/model"
+)
+
+// GetTables fetches all tables by area and number.
+// Requires "List/Search" access on 'tables': @request.auth.id != ""
+func GetTables(token string) ([]model.Table, error) {
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/tables/records?sort=area,number&perPage=500", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch tables: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Table `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// GetTableByID fetches a single table.
+// Requires "View" access on 'tables': @request.auth.id != ""
+func GetTableByID(id, token string) (model.Table, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://127.0.0.1:8090/api/collections/tables/records/%s", id), nil)
+	if err != nil {
+		return model.Table{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return model.Table{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Table{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.Table{}, fmt.Errorf("table lookup failed (%d)", resp.StatusCode)
+	}
+
+	var t model.Table
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return model.Table{}, err
+	}
+	return t, nil
+}
+
+// CreateTable adds a free table to the floor plan.
+// Requires "Create" access on 'tables': @request.auth.id != ""
+func CreateTable(t model.Table, token string) error {
+	return saveTable("POST", "http://127.0.0.1:8090/api/collections/tables/records", map[string]any{
+		"number": t.Number,
+		"seats":  t.Seats,
+		"area":   t.Area,
+		"x":      t.X,
+		"y":      t.Y,
+		"status": model.TableFree,
+	}, token)
+}
+
+// UpdateTable saves the number, seats, area and position of a table.
+// Requires "Update" access on 'tables': @request.auth.id != ""
+func UpdateTable(t model.Table, token string) error {
+	return saveTable("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/tables/records/%s", t.ID), map[string]any{
+		"number": t.Number,
+		"seats":  t.Seats,
+		"area":   t.Area,
+		"x":      t.X,
+		"y":      t.Y,
+	}, token)
+}
+
+// SetTableState saves the status, seating time and merge of a table.
+// Requires "Update" access on 'tables': @request.auth.id != ""
+func SetTableState(t model.Table, token string) error {
+	return saveTable("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/tables/records/%s", t.ID), map[string]any{
+		"status":      t.Status,
+		"seated_at":   t.SeatedAt,
+		"merged_into": t.MergedInto,
+	}, token)
+}
+
+func saveTable(method, url string, fields map[string]any, token string) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to save table (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// DeleteTable removes a table from the floor plan.
+// Requires "Delete" access on 'tables': @request.auth.id != ""
+func DeleteTable(id, token string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8090/api/collections/tables/records/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete table (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// GetTableOrders fetches the orders placed at a table since the given
+// PocketBase datetime, oldest first: the orders of the current sitting.
+// Requires "List/Search" access on 'orders': @request.auth.id != ""
+func GetTableOrders(tableID, since, token string) ([]model.Order, error) {
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf(`table = %q && created_at >= %q && status != %q`, tableID, since, model.OrderStatusCancelled))
+	query.Set("sort", "created_at")
+	query.Set("perPage", "200")
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8090/api/collections/orders/records?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch table orders: %s", string(body))
+	}
+
+	var res struct {
+		Items []model.Order `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// SetOrderTable moves an order to another table.
+// Requires "Update" access on 'orders': @request.auth.id != ""
+func SetOrderTable(orderID, tableID, token string) error {
+	data, _ := json.Marshal(map[string]any{"table": tableID})
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("http://127.0.0.1:8090/api/collections/orders/records/%s", orderID), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to move order (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}